@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vnnyx/golang-todo-api/internal/bootstrap"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/transfer"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export <activity-group-id>",
+	Short: "Export an activity group and its todos",
+	Long: `Export writes an activity group and its todos from the configured database
+in one of the formats json, csv, todotxt or markdown. Groups of workspaces
+other than the default one need --workspace. For example:
+
+  golang-todo-api export 12 --format markdown --output groceries.md`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || id < 1 {
+			return model.ErrInvalidID
+		}
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		workspace, err := workspaceFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		uc, err := bootstrap.NewTransferUC()
+		if err != nil {
+			return err
+		}
+		data, err := uc.ExportActivity(model.WithWorkspace(context.Background(), workspace), web.ActivityExportRequest{ID: id, Format: format})
+		if err != nil {
+			return err
+		}
+		if output == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		return os.WriteFile(output, data, 0o644)
+	},
+}
+
+// workspaceFlag returns --workspace. Ids start at 1: 0 would give the command
+// the rows of every workspace.
+func workspaceFlag(cmd *cobra.Command) (int64, error) {
+	workspace, _ := cmd.Flags().GetInt64("workspace")
+	if workspace < 1 {
+		return 0, fmt.Errorf("invalid argument \"%d\" for \"-w, --workspace\" flag: must be a workspace id of 1 or more", workspace)
+	}
+	return workspace, nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("format", "f", transfer.FormatJSON, "json, csv, todotxt or markdown")
+	exportCmd.Flags().StringP("output", "o", "", "file to write to instead of stdout")
+	exportCmd.Flags().Int64P("workspace", "w", 1, "id of the workspace of the activity group")
+}
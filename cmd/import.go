@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vnnyx/golang-todo-api/internal/bootstrap"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/transfer"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import an activity group and its todos",
+	Long: `Import creates an activity group with all of its todos in the configured
+database. The format is taken from the file extension unless --format is
+given; use - to read from stdin. csv, todotxt and ics files may carry no
+title, so those need --title. With --group the todos are added to an
+existing activity group instead. Imported activity groups belong to the
+user given with --user; without one they are only reachable from the
+command line. They go to the workspace given with --workspace, the default
+workspace unless set. For example:
+
+  golang-todo-api import todo.txt --title Errands --user 3 --workspace 2
+  golang-todo-api import calendar.ics --group 12`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		title, _ := cmd.Flags().GetString("title")
+		actor, _ := cmd.Flags().GetString("actor")
+		group, _ := cmd.Flags().GetInt64("group")
+		user, _ := cmd.Flags().GetInt64("user")
+		workspace, err := workspaceFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if format == "" {
+			format = formatOf(args[0])
+		}
+
+		var data []byte
+		if args[0] == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		uc, err := bootstrap.NewTransferUC()
+		if err != nil {
+			return err
+		}
+		ctx := model.WithWorkspace(model.WithActor(context.Background(), actor), workspace)
+		if user != 0 {
+			ctx = model.WithUser(ctx, user)
+		}
+		if group != 0 {
+			todos, err := uc.ImportTodos(ctx, web.TodoImportRequest{ActivityGroupID: group, Format: format, Data: data})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported %d todos into activity group %d\n", len(todos), group)
+			return nil
+		}
+		res, err := uc.ImportActivity(ctx, web.ActivityImportRequest{Format: format, Title: title, Data: data})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "imported activity group %d %q\n", res.ID, res.Title)
+		return nil
+	},
+}
+
+// formatOf guesses the format of a file from its extension, defaulting to
+// json.
+func formatOf(name string) string {
+	ext := filepath.Ext(name)
+	for _, format := range transfer.ImportFormats {
+		if transfer.Extension(format) == ext {
+			return format
+		}
+	}
+	return transfer.FormatJSON
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("format", "f", "", "json, csv, todotxt, markdown or ics (default from the file extension)")
+	importCmd.Flags().StringP("title", "t", "", "title of the activity group, overriding the one in the file")
+	importCmd.Flags().Int64P("group", "g", 0, "id of an existing activity group to add the todos to")
+	importCmd.Flags().Int64P("user", "u", 0, "id of the user the activity group belongs to")
+	importCmd.Flags().Int64P("workspace", "w", 1, "id of the workspace to import into")
+	importCmd.Flags().String("actor", "cli", "actor recorded in the history")
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vnnyx/golang-todo-api/internal/bootstrap"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage workspaces, their members and quotas",
+	Long: `Workspace manages the workspaces teams work in on the configured database.
+Every user gets a personal workspace when signing up; shared ones are set up
+here. Workspaces are given by slug or id, and quotas of 0 are unlimited. For
+example:
+
+  golang-todo-api workspace create platform --name "Platform team" --max-todos 5000
+  golang-todo-api workspace add-member platform alice@example.com
+  golang-todo-api workspace update platform --max-activity-groups 100`,
+}
+
+var workspaceCreateCmd = &cobra.Command{
+	Use:          "create <slug>",
+	Short:        "Create a workspace",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := web.WorkspaceCreateRequest{Slug: args[0]}
+		req.Name, _ = cmd.Flags().GetString("name")
+		if req.Name == "" {
+			req.Name = args[0]
+		}
+		if cmd.Flags().Changed("max-activity-groups") {
+			n, _ := cmd.Flags().GetInt64("max-activity-groups")
+			req.MaxActivityGroups = &n
+		}
+		if cmd.Flags().Changed("max-todos") {
+			n, _ := cmd.Flags().GetInt64("max-todos")
+			req.MaxTodos = &n
+		}
+
+		uc, err := bootstrap.NewWorkspaceUC()
+		if err != nil {
+			return err
+		}
+		res, err := uc.CreateWorkspace(context.Background(), req)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "created workspace %d %q\n", res.ID, res.Slug)
+		return nil
+	},
+}
+
+var workspaceUpdateCmd = &cobra.Command{
+	Use:          "update <workspace>",
+	Short:        "Rename a workspace or change its quotas",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := web.WorkspaceUpdateRequest{Workspace: args[0]}
+		if cmd.Flags().Changed("name") {
+			name, _ := cmd.Flags().GetString("name")
+			req.Name = &name
+		}
+		if cmd.Flags().Changed("max-activity-groups") {
+			n, _ := cmd.Flags().GetInt64("max-activity-groups")
+			req.MaxActivityGroups = &n
+		}
+		if cmd.Flags().Changed("max-todos") {
+			n, _ := cmd.Flags().GetInt64("max-todos")
+			req.MaxTodos = &n
+		}
+
+		uc, err := bootstrap.NewWorkspaceUC()
+		if err != nil {
+			return err
+		}
+		res, err := uc.UpdateWorkspace(context.Background(), req)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "updated workspace %d %q\n", res.ID, res.Slug)
+		return nil
+	},
+}
+
+var workspaceAddMemberCmd = &cobra.Command{
+	Use:          "add-member <workspace> <email>",
+	Short:        "Let a user work in a workspace",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uc, err := bootstrap.NewWorkspaceUC()
+		if err != nil {
+			return err
+		}
+		err = uc.AddMember(context.Background(), web.WorkspaceMemberRequest{Workspace: args[0], Email: args[1]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "added %s to workspace %s\n", args[1], args[0])
+		return nil
+	},
+}
+
+var workspaceRemoveMemberCmd = &cobra.Command{
+	Use:          "remove-member <workspace> <email>",
+	Short:        "Take a user out of a workspace",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uc, err := bootstrap.NewWorkspaceUC()
+		if err != nil {
+			return err
+		}
+		err = uc.DeleteMember(context.Background(), web.WorkspaceMemberRequest{Workspace: args[0], Email: args[1]})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s from workspace %s\n", args[1], args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceCreateCmd, workspaceUpdateCmd, workspaceAddMemberCmd, workspaceRemoveMemberCmd)
+
+	workspaceCreateCmd.Flags().StringP("name", "n", "", "name of the workspace (default the slug)")
+	workspaceCreateCmd.Flags().Int64("max-activity-groups", 0, "most activity groups the workspace may hold, 0 for unlimited (default from the config)")
+	workspaceCreateCmd.Flags().Int64("max-todos", 0, "most todos the workspace may hold, 0 for unlimited (default from the config)")
+
+	workspaceUpdateCmd.Flags().StringP("name", "n", "", "new name of the workspace")
+	workspaceUpdateCmd.Flags().Int64("max-activity-groups", 0, "most activity groups the workspace may hold, 0 for unlimited")
+	workspaceUpdateCmd.Flags().Int64("max-todos", 0, "most todos the workspace may hold, 0 for unlimited")
+}
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -20,11 +21,13 @@ func StarServer() {
 		JSONEncoder:  json.Marshal,
 		JSONDecoder:  json.Unmarshal,
 	})
-	app.Use(cors.New())
+	// Browser clients need to read the ETag to send it back in If-Match.
+	app.Use(cors.New(cors.Config{ExposeHeaders: fiber.HeaderETag}))
 	app.Use(recover.New())
 	c := cache.New(5*time.Minute, 10*time.Minute)
-	r := di.InitializeRoute(".env", app, c)
-	r.InitRoute()
+	a := di.InitializeApp(".env", app, c)
+	a.Route.InitRoute()
+	go a.TrashPurger.Run(context.Background())
 	err := app.Listen(":3030")
 	if err != nil {
 		log.Fatalf("couldn't start server: %v", err)
@@ -0,0 +1,21 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/routes/di"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/transfer"
+)
+
+// NewTransferUC prepares the configured database for the import and export
+// commands. The memory driver is refused, as its data only lives inside a
+// running server.
+func NewTransferUC() (transfer.TransferUC, error) {
+	cfg := infrastructure.NewConfig(".env")
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return nil, fmt.Errorf("the %s storage driver keeps no data outside the server, use the API instead", cfg.StorageDriver)
+	}
+	RunMigration()
+	return di.InitializeTransferUC(".env"), nil
+}
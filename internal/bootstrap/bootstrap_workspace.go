@@ -0,0 +1,20 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/routes/di"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/workspace"
+)
+
+// NewWorkspaceUC prepares the configured database for the workspace
+// command. Like NewTransferUC it refuses the memory driver.
+func NewWorkspaceUC() (workspace.WorkspaceUC, error) {
+	cfg := infrastructure.NewConfig(".env")
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return nil, fmt.Errorf("the %s storage driver keeps no data outside the server", cfg.StorageDriver)
+	}
+	RunMigration()
+	return di.InitializeWorkspaceUC(".env"), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/sirupsen/logrus"
 	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
@@ -12,13 +13,26 @@ import (
 
 func RunMigration() {
 	cfg := infrastructure.NewConfig(".env")
-	migration, err := migrate.New(cfg.MigrationSource, fmt.Sprintf("mysql://%v:%v@tcp(%v:%v)/%v?parseTime=true",
-		cfg.MysqlUser,
-		cfg.MysqlPassword,
-		cfg.MysqlHost,
-		cfg.MysqlPort,
-		cfg.MysqlDBName,
-	))
+
+	var sourceURL, databaseURL string
+	switch cfg.StorageDriver {
+	case infrastructure.StorageDriverMemory:
+		return
+	case infrastructure.StorageDriverSQLite:
+		sourceURL = cfg.SqliteMigrationSource
+		databaseURL = fmt.Sprintf("sqlite://%v", cfg.SqlitePath)
+	default:
+		sourceURL = cfg.MigrationSource
+		databaseURL = fmt.Sprintf("mysql://%v:%v@tcp(%v:%v)/%v?parseTime=true",
+			cfg.MysqlUser,
+			cfg.MysqlPassword,
+			cfg.MysqlHost,
+			cfg.MysqlPort,
+			cfg.MysqlDBName,
+		)
+	}
+
+	migration, err := migrate.New(sourceURL, databaseURL)
 	if err != nil {
 		logrus.Fatal(err)
 	}
@@ -10,4 +10,13 @@ type ActivityController interface {
 	GetAllActivity(c *fiber.Ctx) error
 	UpdateActivity(c *fiber.Ctx) error
 	DeleteActivity(c *fiber.Ctx) error
+	RestoreActivity(c *fiber.Ctx) error
+	GetActivityHistory(c *fiber.Ctx) error
+	GetActivityWorkflow(c *fiber.Ctx) error
+	UpdateActivityWorkflow(c *fiber.Ctx) error
+	GetAllMember(c *fiber.Ctx) error
+	InsertMember(c *fiber.Ctx) error
+	UpdateMember(c *fiber.Ctx) error
+	DeleteMember(c *fiber.Ctx) error
+	TransferOwnership(c *fiber.Ctx) error
 }
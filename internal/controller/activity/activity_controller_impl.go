@@ -1,16 +1,17 @@
 package activity
 
 import (
-	"fmt"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
 	"github.com/vnnyx/golang-todo-api/internal/usecase/activity"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
 )
 
 type ActivityControllerImpl struct {
@@ -27,14 +28,15 @@ func NewActivityController(activityUC activity.ActivityUC, cache *cache.Cache) A
 
 func (controller *ActivityControllerImpl) InsertActivity(c *fiber.Ctx) error {
 	var req web.ActivityCreateRequest
-	err := c.BodyParser(&req)
+	err := validation.DecodeJSON(c.Body(), &req)
 	if err != nil {
 		return err
 	}
-	res, err := controller.activityUC.CreateActivity(c.Context(), req)
+	res, err := controller.activityUC.CreateActivity(c.UserContext(), req)
 	if err != nil {
 		return err
 	}
+	param.UncacheLists(controller.cache, c)
 
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Status:  "Success",
@@ -46,14 +48,14 @@ func (controller *ActivityControllerImpl) InsertActivity(c *fiber.Ctx) error {
 func (controller *ActivityControllerImpl) GetActivityByID(c *fiber.Ctx) error {
 	var wg sync.WaitGroup
 
-	id, err := strconv.Atoi(c.Params("id"))
+	id, err := param.ID(c)
 	if err != nil {
 		return err
 	}
 
-	data, found := controller.cache.Get(fmt.Sprintf("activity-%v", id))
+	data, found := controller.cache.Get(param.CacheKey(c, "activity-%v", id))
 	if !found {
-		res, err := controller.activityUC.GetActivityByID(c.Context(), int64(id))
+		res, err := controller.activityUC.GetActivityByID(c.UserContext(), id)
 		if err != nil {
 			return err
 		}
@@ -61,9 +63,10 @@ func (controller *ActivityControllerImpl) GetActivityByID(c *fiber.Ctx) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			controller.cache.Set(fmt.Sprintf("activity-%v", id), res, time.Until(time.Now().Add(time.Second*5)))
+			controller.cache.Set(param.CacheKey(c, "activity-%v", id), res, time.Until(time.Now().Add(time.Second*5)))
 		}()
 		wg.Wait()
+		c.Set(fiber.HeaderETag, web.ETag(res.Version))
 
 		return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 			Status:  "Success",
@@ -72,6 +75,7 @@ func (controller *ActivityControllerImpl) GetActivityByID(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, web.ETag(data.(*web.ActivityDTO).Version))
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
@@ -82,49 +86,128 @@ func (controller *ActivityControllerImpl) GetActivityByID(c *fiber.Ctx) error {
 func (controller *ActivityControllerImpl) GetAllActivity(c *fiber.Ctx) error {
 	var wg sync.WaitGroup
 
-	data, found := controller.cache.Get("allactivity")
+	cacheKey := param.CacheKey(c, "allactivity-%s", c.Request().URI().QueryString())
+	data, found := controller.cache.Get(cacheKey)
 	if !found {
-		res, err := controller.activityUC.GetAllActivity(c.Context())
+		var req web.ActivityListRequest
+		if err := c.QueryParser(&req); err != nil {
+			return model.ErrInvalidQuery.Wrap(err)
+		}
+
+		res, page, err := controller.activityUC.GetAllActivity(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
 
+		response := web.WebResponse{
+			Status:     "Success",
+			Message:    "Success",
+			Data:       res,
+			Pagination: page,
+		}
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			controller.cache.Set("allactivity", res, time.Until(time.Now().Add(time.Second*5)))
+			controller.cache.Set(cacheKey, response, time.Until(time.Now().Add(time.Second*5)))
 		}()
 		wg.Wait()
 
-		return c.Status(fiber.StatusOK).JSON(web.WebResponse{
-			Status:  "Success",
-			Message: "Success",
-			Data:    res,
-		})
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+func (controller *ActivityControllerImpl) UpdateActivity(c *fiber.Ctx) error {
+	var req web.ActivityUpdateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ID = id
+
+	version, ok := web.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok || (version != nil && req.Version != nil && *version != *req.Version) {
+		return model.ErrVersionMismatch
+	}
+	if version != nil {
+		req.Version = version
 	}
 
+	res, err := controller.activityUC.UpdateActivity(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+	c.Set(fiber.HeaderETag, web.ETag(res.Version))
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
-		Data:    data,
+		Data:    res,
 	})
 }
 
-func (controller *ActivityControllerImpl) UpdateActivity(c *fiber.Ctx) error {
-	var req web.ActivityUpdateRequest
-	id, err := strconv.Atoi(c.Params("id"))
+func (controller *ActivityControllerImpl) DeleteActivity(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	target, err := param.QueryID(c, "target")
+	if err != nil {
+		return err
+	}
+	err = controller.activityUC.DeleteActivity(c.UserContext(), web.ActivityDeleteRequest{
+		ID:     id,
+		Mode:   c.Query("mode"),
+		Target: target,
+	})
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    struct{}{},
+	})
+}
+
+func (controller *ActivityControllerImpl) RestoreActivity(c *fiber.Ctx) error {
+	id, err := param.ID(c)
 	if err != nil {
 		return err
 	}
-	err = c.BodyParser(&req)
+	res, err := controller.activityUC.RestoreActivity(c.UserContext(), id)
 	if err != nil {
 		return err
 	}
-	req.ID = int64(id)
-	res, err := controller.activityUC.UpdateActivity(c.Context(), req)
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) GetActivityHistory(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.activityUC.GetActivityHistory(c.UserContext(), id)
 	if err != nil {
 		return err
 	}
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
@@ -132,19 +215,173 @@ func (controller *ActivityControllerImpl) UpdateActivity(c *fiber.Ctx) error {
 	})
 }
 
-func (controller *ActivityControllerImpl) DeleteActivity(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
+func (controller *ActivityControllerImpl) GetActivityWorkflow(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.activityUC.GetActivityWorkflow(c.UserContext(), id)
 	if err != nil {
 		return err
 	}
-	err = controller.activityUC.DeleteActivity(c.Context(), int64(id))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) UpdateActivityWorkflow(c *fiber.Ctx) error {
+	var req web.WorkflowUpdateRequest
+	id, err := param.ID(c)
 	if err != nil {
 		return err
 	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ActivityID = id
+
+	version, ok := web.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok || (version != nil && req.Version != nil && *version != *req.Version) {
+		return model.ErrVersionMismatch
+	}
+	if version != nil {
+		req.Version = version
+	}
 
+	res, activity, err := controller.activityUC.UpdateActivityWorkflow(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+	c.Set(fiber.HeaderETag, web.ETag(activity.Version))
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) GetAllMember(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+
+	res, err := controller.activityUC.GetAllMember(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) InsertMember(c *fiber.Ctx) error {
+	var req web.MemberInviteRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ActivityGroupID = id
+
+	res, err := controller.activityUC.InviteMember(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) UpdateMember(c *fiber.Ctx) error {
+	var req web.MemberUpdateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	userID, err := param.PathID(c, "userId")
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ActivityGroupID = id
+	req.UserID = userID
+
+	res, err := controller.activityUC.UpdateMember(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *ActivityControllerImpl) DeleteMember(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	userID, err := param.PathID(c, "userId")
+	if err != nil {
+		return err
+	}
+
+	err = controller.activityUC.DeleteMember(c.UserContext(), id, userID)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
 		Data:    struct{}{},
 	})
 }
+
+func (controller *ActivityControllerImpl) TransferOwnership(c *fiber.Ctx) error {
+	var req web.OwnershipTransferRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ActivityGroupID = id
+
+	res, err := controller.activityUC.TransferOwnership(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "activity-%v", id)
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthController interface {
+	Register(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	GetCurrentUser(c *fiber.Ctx) error
+	InsertAPIKey(c *fiber.Ctx) error
+	GetAllAPIKey(c *fiber.Ctx) error
+	DeleteAPIKey(c *fiber.Ctx) error
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/auth"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+type AuthControllerImpl struct {
+	authUC auth.AuthUC
+}
+
+func NewAuthController(authUC auth.AuthUC) AuthController {
+	return &AuthControllerImpl{authUC: authUC}
+}
+
+func (controller *AuthControllerImpl) Register(c *fiber.Ctx) error {
+	var req web.RegisterRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	res, err := controller.authUC.Register(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) Login(c *fiber.Ctx) error {
+	var req web.LoginRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	res, err := controller.authUC.Login(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) Refresh(c *fiber.Ctx) error {
+	var req web.RefreshRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	res, err := controller.authUC.Refresh(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) GetCurrentUser(c *fiber.Ctx) error {
+	res, err := controller.authUC.GetCurrentUser(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) InsertAPIKey(c *fiber.Ctx) error {
+	var req web.APIKeyCreateRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	res, err := controller.authUC.CreateAPIKey(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) GetAllAPIKey(c *fiber.Ctx) error {
+	res, err := controller.authUC.GetAllAPIKey(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *AuthControllerImpl) DeleteAPIKey(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = controller.authUC.DeleteAPIKey(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    struct{}{},
+	})
+}
@@ -0,0 +1,14 @@
+package calendar
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarController interface {
+	InsertCalendarFeed(c *fiber.Ctx) error
+	InsertAllCalendarFeed(c *fiber.Ctx) error
+	DeleteCalendarFeed(c *fiber.Ctx) error
+	DeleteAllCalendarFeed(c *fiber.Ctx) error
+	GetCalendar(c *fiber.Ctx) error
+	GetAllCalendar(c *fiber.Ctx) error
+}
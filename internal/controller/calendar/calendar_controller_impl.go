@@ -0,0 +1,100 @@
+package calendar
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/ical"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/calendar"
+)
+
+type CalendarControllerImpl struct {
+	calendarUC calendar.CalendarUC
+}
+
+func NewCalendarController(calendarUC calendar.CalendarUC) CalendarController {
+	return &CalendarControllerImpl{calendarUC: calendarUC}
+}
+
+func (controller *CalendarControllerImpl) InsertCalendarFeed(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	return controller.insertCalendarFeed(c, &id)
+}
+
+func (controller *CalendarControllerImpl) InsertAllCalendarFeed(c *fiber.Ctx) error {
+	return controller.insertCalendarFeed(c, nil)
+}
+
+func (controller *CalendarControllerImpl) insertCalendarFeed(c *fiber.Ctx, activityGroupID *int64) error {
+	res, err := controller.calendarUC.CreateCalendarFeed(c.UserContext(), activityGroupID)
+	if err != nil {
+		return err
+	}
+	res.URL = c.BaseURL() + res.URL
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *CalendarControllerImpl) DeleteCalendarFeed(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	return controller.deleteCalendarFeed(c, &id)
+}
+
+func (controller *CalendarControllerImpl) DeleteAllCalendarFeed(c *fiber.Ctx) error {
+	return controller.deleteCalendarFeed(c, nil)
+}
+
+func (controller *CalendarControllerImpl) deleteCalendarFeed(c *fiber.Ctx, activityGroupID *int64) error {
+	err := controller.calendarUC.DeleteCalendarFeed(c.UserContext(), activityGroupID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    struct{}{},
+	})
+}
+
+func (controller *CalendarControllerImpl) GetCalendar(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	return controller.getCalendar(c, &id)
+}
+
+func (controller *CalendarControllerImpl) GetAllCalendar(c *fiber.Ctx) error {
+	return controller.getCalendar(c, nil)
+}
+
+// getCalendar serves a feed. Calendar apps poll feeds, so they may keep a
+// copy for a few minutes.
+func (controller *CalendarControllerImpl) getCalendar(c *fiber.Ctx, activityGroupID *int64) error {
+	var req web.CalendarRequest
+	if err := c.QueryParser(&req); err != nil {
+		return model.ErrInvalidQuery.Wrap(err)
+	}
+	req.ActivityGroupID = activityGroupID
+
+	res, err := controller.calendarUC.GetCalendar(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, ical.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Status(fiber.StatusOK).Send(res)
+}
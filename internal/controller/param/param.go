@@ -0,0 +1,82 @@
+package param
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+// ID parses the :id route parameter, rejecting anything but a positive
+// integer.
+func ID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, model.ErrInvalidID
+	}
+	return id, nil
+}
+
+// PathID parses a route parameter other than :id the same way.
+func PathID(c *fiber.Ctx, key string) (int64, error) {
+	id, err := strconv.ParseInt(c.Params(key), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apperror.InvalidField(model.ErrInvalidID.Code, key, key+" must be a positive integer")
+	}
+	return id, nil
+}
+
+// QueryID parses an optional id from the query string, returning 0 when key
+// is absent.
+func QueryID(c *fiber.Ctx, key string) (int64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apperror.InvalidField(model.ErrInvalidID.Code, key, key+" must be a positive integer")
+	}
+	return id, nil
+}
+
+// CacheKey scopes a response cache key to the user and the workspace of the
+// request, so a cached response is never served to another user or in
+// another workspace.
+func CacheKey(c *fiber.Ctx, format string, args ...interface{}) string {
+	userID, _ := model.UserFromContext(c.UserContext())
+	workspaceID, _ := model.WorkspaceFromContext(c.UserContext())
+	return fmt.Sprintf("%d:%d:", userID, workspaceID) + fmt.Sprintf(format, args...)
+}
+
+// Uncache drops a key made with CacheKey for every user, as members of an
+// activity group each have their own copy of its responses.
+func Uncache(c *cache.Cache, format string, args ...interface{}) {
+	suffix := ":" + fmt.Sprintf(format, args...)
+	for key := range c.Items() {
+		if strings.HasSuffix(key, suffix) {
+			c.Delete(key)
+		}
+	}
+}
+
+// UncacheLists drops the cached todo and activity group lists of every user
+// in the workspace of the request, as a write to one todo or group can show
+// up in the lists of all of its members.
+func UncacheLists(store *cache.Cache, c *fiber.Ctx) {
+	workspaceID, _ := model.WorkspaceFromContext(c.UserContext())
+	workspace := strconv.FormatInt(workspaceID, 10)
+	for key := range store.Items() {
+		parts := strings.SplitN(key, ":", 3)
+		if len(parts) != 3 || parts[1] != workspace {
+			continue
+		}
+		if strings.HasPrefix(parts[2], "alltodo-") || strings.HasPrefix(parts[2], "allactivity-") {
+			store.Delete(key)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package search
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchController interface {
+	Search(c *fiber.Ctx) error
+}
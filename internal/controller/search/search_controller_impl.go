@@ -0,0 +1,35 @@
+package search
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/search"
+)
+
+type SearchControllerImpl struct {
+	searchUC search.SearchUC
+}
+
+func NewSearchController(searchUC search.SearchUC) SearchController {
+	return &SearchControllerImpl{searchUC: searchUC}
+}
+
+func (controller *SearchControllerImpl) Search(c *fiber.Ctx) error {
+	var req web.SearchRequest
+	if err := c.QueryParser(&req); err != nil {
+		return model.ErrInvalidQuery.Wrap(err)
+	}
+
+	res, page, err := controller.searchUC.Search(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:     "Success",
+		Message:    "Success",
+		Data:       res,
+		Pagination: page,
+	})
+}
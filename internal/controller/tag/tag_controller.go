@@ -0,0 +1,13 @@
+package tag
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagController interface {
+	InsertTag(c *fiber.Ctx) error
+	GetTagByID(c *fiber.Ctx) error
+	GetAllTag(c *fiber.Ctx) error
+	UpdateTag(c *fiber.Ctx) error
+	DeleteTag(c *fiber.Ctx) error
+}
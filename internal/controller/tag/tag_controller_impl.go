@@ -0,0 +1,113 @@
+package tag
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/tag"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+type TagControllerImpl struct {
+	tagUC tag.TagUC
+	cache *cache.Cache
+}
+
+func NewTagController(tagUC tag.TagUC, cache *cache.Cache) TagController {
+	return &TagControllerImpl{
+		tagUC: tagUC,
+		cache: cache,
+	}
+}
+
+func (controller *TagControllerImpl) InsertTag(c *fiber.Ctx) error {
+	var req web.TagCreateRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	res, err := controller.tagUC.CreateTag(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TagControllerImpl) GetTagByID(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.tagUC.GetTagByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TagControllerImpl) GetAllTag(c *fiber.Ctx) error {
+	res, err := controller.tagUC.GetAllTag(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TagControllerImpl) UpdateTag(c *fiber.Ctx) error {
+	var req web.TagUpdateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ID = id
+
+	res, err := controller.tagUC.UpdateTag(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TagControllerImpl) DeleteTag(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = controller.tagUC.DeleteTag(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    struct{}{},
+	})
+}
@@ -8,6 +8,25 @@ type TodoController interface {
 	InsertTodo(c *fiber.Ctx) error
 	GetTodoByID(c *fiber.Ctx) error
 	GetAllTodo(c *fiber.Ctx) error
+	GetTodayTodo(c *fiber.Ctx) error
+	GetUpcomingTodo(c *fiber.Ctx) error
+	GetOverdueTodo(c *fiber.Ctx) error
+	GetMyTodo(c *fiber.Ctx) error
 	UpdateTodo(c *fiber.Ctx) error
+	MoveTodo(c *fiber.Ctx) error
 	DeleteTodo(c *fiber.Ctx) error
+	BulkTodo(c *fiber.Ctx) error
+	RestoreTodo(c *fiber.Ctx) error
+	GetTodoHistory(c *fiber.Ctx) error
+	GetTodoTransitions(c *fiber.Ctx) error
+	InsertSubtask(c *fiber.Ctx) error
+	GetSubtasks(c *fiber.Ctx) error
+	AddTodoTag(c *fiber.Ctx) error
+	RemoveTodoTag(c *fiber.Ctx) error
+	AddTodoWatcher(c *fiber.Ctx) error
+	RemoveTodoWatcher(c *fiber.Ctx) error
+	InsertComment(c *fiber.Ctx) error
+	GetComments(c *fiber.Ctx) error
+	UpdateComment(c *fiber.Ctx) error
+	DeleteComment(c *fiber.Ctx) error
 }
@@ -1,16 +1,18 @@
 package todo
 
 import (
-	"fmt"
-	"strconv"
+	"context"
 	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
 	"github.com/vnnyx/golang-todo-api/internal/usecase/todo"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
 )
 
 type TodoControllerImpl struct {
@@ -27,15 +29,16 @@ func NewTodoController(todoUC todo.TodoUC, cache *cache.Cache) TodoController {
 
 func (controller *TodoControllerImpl) InsertTodo(c *fiber.Ctx) error {
 	var req web.TodoCreateRequest
-	err := c.BodyParser(&req)
+	err := validation.DecodeJSON(c.Body(), &req)
 	if err != nil {
 		return err
 	}
 
-	res, err := controller.todoUC.CreateTodo(c.Context(), req)
+	res, err := controller.todoUC.CreateTodo(c.UserContext(), req)
 	if err != nil {
 		return err
 	}
+	param.UncacheLists(controller.cache, c)
 
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Status:  "Success",
@@ -47,14 +50,14 @@ func (controller *TodoControllerImpl) InsertTodo(c *fiber.Ctx) error {
 func (controller *TodoControllerImpl) GetTodoByID(c *fiber.Ctx) error {
 	var wg sync.WaitGroup
 
-	id, err := strconv.Atoi(c.Params("id"))
+	id, err := param.ID(c)
 	if err != nil {
 		return err
 	}
 
-	data, found := controller.cache.Get(fmt.Sprintf("todo-%v", id))
+	data, found := controller.cache.Get(param.CacheKey(c, "todo-%v", id))
 	if !found {
-		res, err := controller.todoUC.GetTodoByID(c.Context(), int64(id))
+		res, err := controller.todoUC.GetTodoByID(c.UserContext(), id)
 		if err != nil {
 			return err
 		}
@@ -62,9 +65,10 @@ func (controller *TodoControllerImpl) GetTodoByID(c *fiber.Ctx) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			controller.cache.Set(fmt.Sprintf("todo-%v", id), res, time.Until(time.Now().Add(time.Second*5)))
+			controller.cache.Set(param.CacheKey(c, "todo-%v", id), res, time.Until(time.Now().Add(time.Second*5)))
 		}()
 		wg.Wait()
+		c.Set(fiber.HeaderETag, web.ETag(res.Version))
 		return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 			Status:  "Success",
 			Message: "Success",
@@ -72,6 +76,7 @@ func (controller *TodoControllerImpl) GetTodoByID(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, web.ETag(data.(*web.TodoDTO).Version))
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
@@ -82,49 +87,139 @@ func (controller *TodoControllerImpl) GetTodoByID(c *fiber.Ctx) error {
 func (controller *TodoControllerImpl) GetAllTodo(c *fiber.Ctx) error {
 	var wg sync.WaitGroup
 
-	activityGroupID, _ := strconv.Atoi(c.Query("activity_group_id"))
-	data, found := controller.cache.Get(fmt.Sprintf("alltodo-%v", activityGroupID))
+	cacheKey := param.CacheKey(c, "alltodo-%s", c.Request().URI().QueryString())
+	data, found := controller.cache.Get(cacheKey)
 	if !found {
-		res, err := controller.todoUC.GetAllTodo(c.Context(), int64(activityGroupID))
+		var req web.TodoListRequest
+		if err := c.QueryParser(&req); err != nil {
+			return model.ErrInvalidQuery.Wrap(err)
+		}
+
+		res, page, err := controller.todoUC.GetAllTodo(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
 
+		response := web.WebResponse{
+			Status:     "Success",
+			Message:    "Success",
+			Data:       res,
+			Pagination: page,
+		}
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			controller.cache.Set(fmt.Sprintf("alltodo-%v", activityGroupID), res, time.Until(time.Now().Add(time.Second*5)))
+			controller.cache.Set(cacheKey, response, time.Until(time.Now().Add(time.Second*5)))
 		}()
 		wg.Wait()
-		return c.Status(fiber.StatusOK).JSON(web.WebResponse{
-			Status:  "Success",
-			Message: "Success",
-			Data:    res,
-		})
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+func (controller *TodoControllerImpl) GetTodayTodo(c *fiber.Ctx) error {
+	return controller.getTodoView(c, web.TodoViewToday)
+}
+
+func (controller *TodoControllerImpl) GetUpcomingTodo(c *fiber.Ctx) error {
+	return controller.getTodoView(c, web.TodoViewUpcoming)
+}
+
+func (controller *TodoControllerImpl) GetOverdueTodo(c *fiber.Ctx) error {
+	return controller.getTodoView(c, web.TodoViewOverdue)
+}
+
+func (controller *TodoControllerImpl) GetMyTodo(c *fiber.Ctx) error {
+	return controller.getTodoView(c, web.TodoViewMine)
+}
+
+// getTodoView is not cached: the views move with the clock.
+func (controller *TodoControllerImpl) getTodoView(c *fiber.Ctx, view string) error {
+	var req web.TodoViewRequest
+	if err := c.QueryParser(&req); err != nil {
+		return model.ErrInvalidQuery.Wrap(err)
+	}
+
+	res, page, err := controller.todoUC.GetTodoView(c.UserContext(), view, req)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
-		Status:  "Success",
-		Message: "Success",
-		Data:    data,
+		Status:     "Success",
+		Message:    "Success",
+		Data:       res,
+		Pagination: page,
 	})
 }
 
 func (controller *TodoControllerImpl) UpdateTodo(c *fiber.Ctx) error {
 	var req web.TodoUpdateRequest
-	id, err := strconv.Atoi(c.Params("id"))
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ID = id
+
+	version, ok := web.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok || (version != nil && req.Version != nil && *version != *req.Version) {
+		return model.ErrVersionMismatch
+	}
+	if version != nil {
+		req.Version = version
+	}
+
+	res, err := controller.todoUC.UpdateTodo(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+	if res.ParentTodoID != nil {
+		// The progress of the parent, or the parent itself, may have changed.
+		param.Uncache(controller.cache, "todo-%v", *res.ParentTodoID)
+	}
+	c.Set(fiber.HeaderETag, web.ETag(res.Version))
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) MoveTodo(c *fiber.Ctx) error {
+	var req web.TodoMoveRequest
+	id, err := param.ID(c)
 	if err != nil {
 		return err
 	}
-	err = c.BodyParser(&req)
+	err = validation.DecodeJSON(c.Body(), &req)
 	if err != nil {
 		return err
 	}
-	req.ID = int64(id)
-	res, err := controller.todoUC.UpdateTodo(c.Context(), req)
+	req.ID = id
+
+	version, ok := web.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok || (version != nil && req.Version != nil && *version != *req.Version) {
+		return model.ErrVersionMismatch
+	}
+	if version != nil {
+		req.Version = version
+	}
+
+	res, err := controller.todoUC.MoveTodo(c.UserContext(), req)
 	if err != nil {
 		return err
 	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+	c.Set(fiber.HeaderETag, web.ETag(res.Version))
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
 		Message: "Success",
@@ -133,14 +228,305 @@ func (controller *TodoControllerImpl) UpdateTodo(c *fiber.Ctx) error {
 }
 
 func (controller *TodoControllerImpl) DeleteTodo(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = controller.todoUC.DeleteTodo(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    struct{}{},
+	})
+}
+
+func (controller *TodoControllerImpl) BulkTodo(c *fiber.Ctx) error {
+	var req web.TodoBulkRequest
+	err := validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+
+	res, err := controller.todoUC.BulkTodo(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	for _, r := range res {
+		for _, id := range r.IDs {
+			param.Uncache(controller.cache, "todo-%v", id)
+		}
+		for _, t := range r.Todos {
+			if t.ParentTodoID != nil {
+				param.Uncache(controller.cache, "todo-%v", *t.ParentTodoID)
+			}
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) RestoreTodo(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.todoUC.RestoreTodo(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) GetTodoHistory(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.todoUC.GetTodoHistory(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) GetTodoTransitions(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.todoUC.GetTodoTransitions(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) InsertSubtask(c *fiber.Ctx) error {
+	var req web.SubtaskCreateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.ParentTodoID = id
+
+	res, err := controller.todoUC.CreateSubtask(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) GetSubtasks(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.todoUC.GetSubtasks(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) AddTodoTag(c *fiber.Ctx) error {
+	return controller.changeTodoTag(c, controller.todoUC.AddTodoTag)
+}
+
+func (controller *TodoControllerImpl) RemoveTodoTag(c *fiber.Ctx) error {
+	return controller.changeTodoTag(c, controller.todoUC.RemoveTodoTag)
+}
+
+func (controller *TodoControllerImpl) changeTodoTag(c *fiber.Ctx, change func(ctx context.Context, req web.TodoTagRequest) (*web.TodoDTO, error)) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	tagID, err := param.PathID(c, "tagId")
+	if err != nil {
+		return err
+	}
+	res, err := change(c.UserContext(), web.TodoTagRequest{TodoID: id, TagID: tagID})
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) AddTodoWatcher(c *fiber.Ctx) error {
+	return controller.changeTodoWatcher(c, controller.todoUC.AddTodoWatcher)
+}
+
+func (controller *TodoControllerImpl) RemoveTodoWatcher(c *fiber.Ctx) error {
+	return controller.changeTodoWatcher(c, controller.todoUC.RemoveTodoWatcher)
+}
+
+func (controller *TodoControllerImpl) changeTodoWatcher(c *fiber.Ctx, change func(ctx context.Context, req web.TodoWatcherRequest) (*web.TodoDTO, error)) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	userID, err := param.PathID(c, "userId")
+	if err != nil {
+		return err
+	}
+	res, err := change(c.UserContext(), web.TodoWatcherRequest{TodoID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) InsertComment(c *fiber.Ctx) error {
+	var req web.CommentCreateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.TodoID = id
+
+	res, err := controller.todoUC.CreateComment(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) GetComments(c *fiber.Ctx) error {
+	var req web.CommentListRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	if err = c.QueryParser(&req); err != nil {
+		return model.ErrInvalidQuery.Wrap(err)
+	}
+	req.TodoID = id
+
+	res, page, err := controller.todoUC.GetComments(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:     "Success",
+		Message:    "Success",
+		Data:       res,
+		Pagination: page,
+	})
+}
+
+func (controller *TodoControllerImpl) UpdateComment(c *fiber.Ctx) error {
+	var req web.CommentUpdateRequest
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := param.PathID(c, "commentId")
+	if err != nil {
+		return err
+	}
+	err = validation.DecodeJSON(c.Body(), &req)
+	if err != nil {
+		return err
+	}
+	req.TodoID, req.ID = id, commentID
+
+	res, err := controller.todoUC.UpdateComment(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+func (controller *TodoControllerImpl) DeleteComment(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := param.PathID(c, "commentId")
 	if err != nil {
 		return err
 	}
-	err = controller.todoUC.DeleteTodo(c.Context(), int64(id))
+	err = controller.todoUC.DeleteComment(c.UserContext(), id, commentID)
 	if err != nil {
 		return err
 	}
+	param.UncacheLists(controller.cache, c)
+	param.Uncache(controller.cache, "todo-%v", id)
 
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Status:  "Success",
@@ -0,0 +1,11 @@
+package transfer
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type TransferController interface {
+	ExportActivity(c *fiber.Ctx) error
+	ImportActivity(c *fiber.Ctx) error
+	ImportTodos(c *fiber.Ctx) error
+}
@@ -0,0 +1,88 @@
+package transfer
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/vnnyx/golang-todo-api/internal/controller/param"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	codec "github.com/vnnyx/golang-todo-api/internal/transfer"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/transfer"
+)
+
+type TransferControllerImpl struct {
+	transferUC transfer.TransferUC
+	cache      *cache.Cache
+}
+
+func NewTransferController(transferUC transfer.TransferUC, cache *cache.Cache) TransferController {
+	return &TransferControllerImpl{
+		transferUC: transferUC,
+		cache:      cache,
+	}
+}
+
+// ExportActivity sends the export as a file download.
+func (controller *TransferControllerImpl) ExportActivity(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	format := c.Query("format", codec.FormatJSON)
+	res, err := controller.transferUC.ExportActivity(c.UserContext(), web.ActivityExportRequest{
+		ID:     id,
+		Format: format,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, codec.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="activity-group-%d%s"`, id, codec.Extension(format)))
+	return c.Status(fiber.StatusOK).Send(res)
+}
+
+// ImportActivity reads the request body as is, in the format named by the
+// format query parameter.
+func (controller *TransferControllerImpl) ImportActivity(c *fiber.Ctx) error {
+	res, err := controller.transferUC.ImportActivity(c.UserContext(), web.ActivityImportRequest{
+		Format: c.Query("format", codec.FormatJSON),
+		Title:  c.Query("title"),
+		Data:   c.Body(),
+	})
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
+
+// ImportTodos adds the todos in the request body to an existing activity
+// group.
+func (controller *TransferControllerImpl) ImportTodos(c *fiber.Ctx) error {
+	id, err := param.ID(c)
+	if err != nil {
+		return err
+	}
+	res, err := controller.transferUC.ImportTodos(c.UserContext(), web.TodoImportRequest{
+		ActivityGroupID: id,
+		Format:          c.Query("format", codec.FormatJSON),
+		Data:            c.Body(),
+	})
+	if err != nil {
+		return err
+	}
+	param.UncacheLists(controller.cache, c)
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
@@ -0,0 +1,9 @@
+package trash
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrashController interface {
+	GetTrash(c *fiber.Ctx) error
+}
@@ -0,0 +1,37 @@
+package trash
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/trash"
+)
+
+type TrashControllerImpl struct {
+	trashUC trash.TrashUC
+}
+
+func NewTrashController(trashUC trash.TrashUC) TrashController {
+	return &TrashControllerImpl{
+		trashUC: trashUC,
+	}
+}
+
+func (controller *TrashControllerImpl) GetTrash(c *fiber.Ctx) error {
+	var req web.TrashRequest
+	if err := c.QueryParser(&req); err != nil {
+		return model.ErrInvalidQuery.Wrap(err)
+	}
+
+	res, page, err := controller.trashUC.GetTrash(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:     "Success",
+		Message:    "Success",
+		Data:       res,
+		Pagination: page,
+	})
+}
@@ -0,0 +1,9 @@
+package workspace
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type WorkspaceController interface {
+	GetAllWorkspace(c *fiber.Ctx) error
+}
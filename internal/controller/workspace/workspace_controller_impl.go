@@ -0,0 +1,30 @@
+package workspace
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/workspace"
+)
+
+type WorkspaceControllerImpl struct {
+	workspaceUC workspace.WorkspaceUC
+}
+
+func NewWorkspaceController(workspaceUC workspace.WorkspaceUC) WorkspaceController {
+	return &WorkspaceControllerImpl{
+		workspaceUC: workspaceUC,
+	}
+}
+
+func (controller *WorkspaceControllerImpl) GetAllWorkspace(c *fiber.Ctx) error {
+	res, err := controller.workspaceUC.GetAllWorkspace(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Status:  "Success",
+		Message: "Success",
+		Data:    res,
+	})
+}
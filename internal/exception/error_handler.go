@@ -1,59 +1,116 @@
 package exception
 
 import (
+	"context"
 	"errors"
+	"regexp"
 	"strings"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
 	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// foreignKeyErrors names the field behind the MySQL foreign keys a request
+// can break. SQLite does not report which key failed, so the usecases check
+// references before writing and anything left is ErrUnknownReference.
+var foreignKeyErrors = map[string]*apperror.Error{
+	"fk_todos_activity_group_id":          model.ErrActivityGroupNotFound,
+	"fk_calendar_feeds_activity_group_id": model.ErrActivityGroupNotFound,
+	"fk_todos_parent_todo_id":             model.ErrParentTodoNotFound,
+	"fk_todos_assignee_id":                model.ErrUnknownAssignee,
+}
+
+var constraintName = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+
+// ErrorHandler renders every error as application/problem+json. Only the
+// message of typed errors reaches the client; anything else is logged and
+// reported as a generic internal error.
 func ErrorHandler(c *fiber.Ctx, err error) error {
-	if databaseError(c, err) {
-		return nil
+	appErr := translate(err)
+	status := appErr.Kind.Status()
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		status = fiberError.Code
+	}
+	if appErr.Kind == apperror.KindInternal {
+		logrus.WithField("path", c.Path()).Error(err)
 	}
-	generalError(c, err)
-	return nil
-}
 
-func databaseError(c *fiber.Ctx, err error) bool {
-	sqlError, ok := err.(*mysql.MySQLError)
-	if !ok {
-		return false
+	problem := web.Problem{
+		Type:     "about:blank",
+		Title:    utils.StatusMessage(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: c.OriginalURL(),
+		Code:     appErr.Code,
 	}
-	switch {
-	case sqlError.Number == 1062 && strings.Contains(sqlError.Message, "email"):
-		_ = c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
-			Status:  "Bad Request",
-			Message: sqlError.Message,
-		})
-	default:
-		_ = c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Status:  "Internal Server Error",
-			Message: sqlError.Message,
+	for _, f := range appErr.Fields {
+		problem.Errors = append(problem.Errors, web.ProblemFieldError{
+			Field:   f.Field,
+			Code:    f.Code,
+			Message: f.Message,
 		})
 	}
-	return true
+
+	if err := c.Status(status).JSON(problem); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, MIMEApplicationProblemJSON)
+	return nil
 }
 
-func generalError(c *fiber.Ctx, err error) {
+// translate turns any error into a typed one, classifying the driver errors
+// the repositories let through.
+func translate(err error) *apperror.Error {
+	var appErr *apperror.Error
+	var fiberError *fiber.Error
+	var mysqlError *mysql.MySQLError
+	var sqliteError *sqlite.Error
+
 	switch {
-	case strings.Contains(err.Error(), "Not Found") || strings.Contains(err.Error(), "Failed to Delete"):
-		_ = c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-			Status:  "Not Found",
-			Message: err.Error(),
-		})
-	case errors.Is(err, model.ErrTitleCannotBeNull) || errors.Is(err, model.ErrActivityGroupIDCannotBeNull):
-		_ = c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
-			Status:  "Bad Request",
-			Message: err.Error(),
-		})
-	default:
-		_ = c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Status:  "Internal Server Error",
-			Message: err.Error(),
-		})
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &fiberError):
+		return apperror.New(apperror.KindBadRequest, statusCode(fiberError.Code), fiberError.Message)
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+		return apperror.New(apperror.KindTimeout, "timeout", "the request took too long to complete").Wrap(err)
+	case errors.As(err, &mysqlError):
+		switch mysqlError.Number {
+		case 1062:
+			return apperror.Conflict("duplicate_entry", "a resource with the same unique fields already exists").Wrap(err)
+		case 1451:
+			return model.ErrActivityGroupNotEmpty.Wrap(err)
+		case 1452:
+			if m := constraintName.FindStringSubmatch(mysqlError.Message); m != nil {
+				if e, ok := foreignKeyErrors[m[1]]; ok {
+					return e.Wrap(err)
+				}
+			}
+			return model.ErrUnknownReference.Wrap(err)
+		}
+	case errors.As(err, &sqliteError):
+		switch sqliteError.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return apperror.Conflict("duplicate_entry", "a resource with the same unique fields already exists").Wrap(err)
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return model.ErrUnknownReference.Wrap(err)
+		}
 	}
+	return apperror.Internal(err)
+}
+
+// statusCode derives a stable code such as "method_not_allowed" from an HTTP
+// status.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
 }
@@ -0,0 +1,39 @@
+package exception
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+func TestTranslateForeignKeyErrors(t *testing.T) {
+	failing := func(constraint string) error {
+		return &mysql.MySQLError{
+			Number: 1452,
+			Message: "Cannot add or update a child row: a foreign key constraint fails " +
+				"(`todo`.`todos`, CONSTRAINT `" + constraint + "` FOREIGN KEY (`x`) REFERENCES `y` (`z`))",
+		}
+	}
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{failing("fk_todos_activity_group_id"), model.ErrActivityGroupNotFound},
+		{failing("fk_todos_parent_todo_id"), model.ErrParentTodoNotFound},
+		{failing("fk_todos_assignee_id"), model.ErrUnknownAssignee},
+		{failing("fk_todos_workspace_id"), model.ErrUnknownReference},
+		{failing("fk_todo_comments_user_id"), model.ErrUnknownReference},
+		{&mysql.MySQLError{Number: 1452, Message: "a foreign key constraint fails"}, model.ErrUnknownReference},
+	}
+	for _, tt := range tests {
+		got := translate(tt.err)
+		if !errors.Is(got, tt.want) {
+			t.Errorf("translate(%v) = %v, want %v", tt.err, got.Code, tt.want)
+		}
+		if !errors.Is(got, tt.err) {
+			t.Errorf("translate(%v) does not wrap the driver error", tt.err)
+		}
+	}
+}
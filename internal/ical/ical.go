@@ -0,0 +1,343 @@
+// Package ical reads and writes the iCalendar format of RFC 5545. It only
+// deals with the syntax: components made of properties, text escaping, line
+// folding and date values. What the components mean is up to the callers.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of iCalendar data.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLine is the length in octets lines are folded at.
+const maxLine = 75
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Add appends a property. params come in name, value pairs.
+func (c *Component) Add(name, value string, params ...string) {
+	p := Property{Name: name, Value: value}
+	if len(params) > 0 {
+		p.Params = make(map[string]string, len(params)/2)
+		for i := 0; i+1 < len(params); i += 2 {
+			p.Params[params[i]] = params[i+1]
+		}
+	}
+	c.Properties = append(c.Properties, p)
+}
+
+// AddText appends a property holding text, escaping it.
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// Get returns the first property named name, or nil.
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// All returns every property named name.
+func (c *Component) All(name string) []Property {
+	var props []Property
+	for _, p := range c.Properties {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Children returns the subcomponents named name.
+func (c *Component) Children(name string) []*Component {
+	var children []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Encode writes c with CRLF line endings, folding long lines.
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	encode(bw, c)
+	return bw.Flush()
+}
+
+func encode(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		var b strings.Builder
+		b.WriteString(p.Name)
+		names := make([]string, 0, len(p.Params))
+		for name := range p.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			b.WriteString(";" + name + "=" + quoteParam(p.Params[name]))
+		}
+		b.WriteString(":" + p.Value)
+		writeLine(w, b.String())
+	}
+	for _, child := range c.Components {
+		encode(w, child)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds line into chunks of at most maxLine octets, never
+// splitting a UTF-8 sequence.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLine
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xc0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the length of the next line.
+		limit = maxLine - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+func quoteParam(v string) string {
+	if strings.ContainsAny(v, ":;,") {
+		return `"` + strings.ReplaceAll(v, `"`, "") + `"`
+	}
+	return v
+}
+
+// Decode reads the first component of r, usually a VCALENDAR. Property and
+// parameter names are upper cased; values are left as they are.
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	var root *Component
+	for _, l := range lines {
+		if l.text == "" {
+			continue
+		}
+		p, err := parseLine(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", l.number, err)
+		}
+		switch p.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(p.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			} else if root != nil {
+				return nil, fmt.Errorf("line %d: unexpected content after END:%s", l.number, root.Name)
+			} else {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, fmt.Errorf("line %d: END:%s does not close an open component", l.number, p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property %s is outside of a component", l.number, p.Name)
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, p)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no component found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%s is not closed", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+type line struct {
+	number int
+	text   string
+}
+
+// unfold joins folded lines, numbering each logical line by the physical
+// line it starts on.
+func unfold(r io.Reader) ([]line, error) {
+	var lines []line
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if n == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if len(text) > 0 && (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, line{number: n, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into name, parameters and value.
+// Parameter values may be quoted, and only quoted ones may hold ":;,".
+func parseLine(s string) (Property, error) {
+	var p Property
+	i := strings.IndexAny(s, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("%q is not a content line", s)
+	}
+	p.Name = strings.ToUpper(s[:i])
+	for s[i] == ';' {
+		s = s[i+1:]
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("parameter of %s has no value", p.Name)
+		}
+		name := strings.ToUpper(s[:eq])
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return p, fmt.Errorf("parameter %s of %s is not closed", name, p.Name)
+			}
+			value, s = s[1:end+1], s[end+2:]
+		} else {
+			end := strings.IndexAny(s, ";:")
+			if end < 0 {
+				return p, fmt.Errorf("%s has no value", p.Name)
+			}
+			value, s = s[:end], s[end:]
+		}
+		if p.Params == nil {
+			p.Params = make(map[string]string)
+		}
+		p.Params[name] = value
+		i = 0
+		if s == "" {
+			return p, fmt.Errorf("%s has no value", p.Name)
+		}
+	}
+	if s[i] != ':' {
+		return p, fmt.Errorf("%s has no value", p.Name)
+	}
+	p.Value = s[i+1:]
+	return p, nil
+}
+
+// EscapeText escapes a TEXT value.
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// UnescapeText reverses EscapeText.
+func UnescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// SplitText splits a list of TEXT values, as in CATEGORIES, and unescapes
+// them.
+func SplitText(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, UnescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, UnescapeText(s[start:]))
+}
+
+// FormatDateTime renders t as a DATE-TIME in UTC.
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// FormatDate renders the date of t as a DATE.
+func FormatDate(t time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
+
+// Time reads a DATE or DATE-TIME property. Times with a TZID are converted
+// from that zone, and floating times are taken as UTC. A DATE is midnight
+// UTC of that day.
+func (p *Property) Time() (time.Time, error) {
+	value := strings.TrimSpace(p.Value)
+	if p.Params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s %q is not a date", p.Name, value)
+		}
+		return t, nil
+	}
+
+	loc := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" && !strings.HasSuffix(value, "Z") {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s has the unknown TZID %q", p.Name, tzid)
+		}
+		loc = l
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, strings.TrimSuffix(value, "Z"), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s %q is not a date-time", p.Name, value)
+	}
+	return t.UTC(), nil
+}
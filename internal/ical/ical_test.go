@@ -0,0 +1,230 @@
+package ical
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	_ "time/tzdata"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// calendar is the component testdata/calendar.ics holds.
+func calendar() *Component {
+	cal := &Component{Name: "VCALENDAR"}
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", "-//golang-todo-api//EN")
+	cal.AddText("X-WR-CALNAME", "Home, garden; and the rest")
+
+	todo := &Component{Name: "VTODO"}
+	todo.Add("UID", "todo-1@golang-todo-api")
+	todo.AddText("SUMMARY", `Pick up the "café" order; milk, eggs \ bread`+"\n"+"and a ☕ for Ann, who waits at the counter near the door")
+	todo.AddText("DESCRIPTION", strings.Repeat("é", 80))
+	todo.Add("DTSTART", "20230410T090000", "TZID", "Europe/Berlin")
+	todo.Add("DUE", FormatDateTime(time.Date(2023, 4, 12, 17, 30, 0, 0, time.UTC)))
+	todo.Add("CATEGORIES", EscapeText("a,b")+","+EscapeText("c;d"))
+	todo.Add("RELATED-TO", "todo-0@golang-todo-api", "RELTYPE", "PARENT")
+	cal.Components = append(cal.Components, todo)
+
+	event := &Component{Name: "VEVENT"}
+	event.Add("UID", "todo-2@golang-todo-api")
+	event.AddText("SUMMARY", strings.Repeat("x", 74))
+	// Exactly maxLine octets, so not folded.
+	event.AddText("DESCRIPTION", strings.Repeat("y", maxLine-len("DESCRIPTION:")))
+	event.Add("DTSTART", FormatDate(time.Date(2023, 4, 13, 0, 0, 0, 0, time.UTC)), "VALUE", "DATE")
+	event.Add("X-LOCATION", "Room 1", "X-ALTREP", "http://example.com/a;b")
+	cal.Components = append(cal.Components, event)
+	return cal
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, calendar()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "testdata/calendar.ics", buf.Bytes())
+
+	for i, l := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(l) > maxLine {
+			t.Errorf("line %d is %d octets long: %q", i+1, len(l), l)
+		}
+		if !utf8.ValidString(l) {
+			t.Errorf("line %d splits a UTF-8 sequence: %q", i+1, l)
+		}
+	}
+}
+
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(name, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	f, err := os.Open("testdata/calendar.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := calendar(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+// TestDecodeForeign reads a calendar written by another client: LF line
+// endings, a BOM, lower case names, folds with tabs and in the middle of
+// escapes and UTF-8 sequences, and dates with and without TZID.
+func TestDecodeForeign(t *testing.T) {
+	f, err := os.Open("testdata/foreign.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cal, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cal.Name != "VCALENDAR" || len(cal.Children("VTODO")) != 2 {
+		t.Fatalf("Decode = %+v, want a VCALENDAR with two VTODOs", cal)
+	}
+	if got, want := UnescapeText(cal.Get("X-WR-CALNAME").Value), "Work; the team"; got != want {
+		t.Errorf("X-WR-CALNAME = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		summary    string
+		categories []string
+		dtstart    string
+		due        string
+	}{
+		{"Ship v2, then \"celebrate\"\nwith cake ☕ and a \\ backslash", []string{"work", "a,b"}, "2023-04-10T07:00:00Z", "2023-04-12T17:30:00Z"},
+		{"Floating", []string{"x;y"}, "2023-01-15T14:00:00Z", "2023-01-20T00:00:00Z"},
+	}
+	for i, c := range cal.Children("VTODO") {
+		tt := tests[i]
+		if got := UnescapeText(c.Get("SUMMARY").Value); got != tt.summary {
+			t.Errorf("VTODO %d: SUMMARY = %q, want %q", i+1, got, tt.summary)
+		}
+		if got := SplitText(c.Get("CATEGORIES").Value); !reflect.DeepEqual(got, tt.categories) {
+			t.Errorf("VTODO %d: CATEGORIES = %q, want %q", i+1, got, tt.categories)
+		}
+		for _, d := range []struct{ name, want string }{{"DTSTART", tt.dtstart}, {"DUE", tt.due}} {
+			got, err := c.Get(d.name).Time()
+			if err != nil {
+				t.Errorf("VTODO %d: %s error = %v", i+1, d.name, err)
+				continue
+			}
+			if got.Format(time.RFC3339) != d.want {
+				t.Errorf("VTODO %d: %s = %v, want %s", i+1, d.name, got, d.want)
+			}
+		}
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"VERSION:2.0\r\n",
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VTODO\r\n",
+		"BEGIN:VCALENDAR\r\nnot a content line\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nDTSTART;TZID:20230101\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nX-A;B=\"open:1\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+	}
+	for _, s := range tests {
+		if c, err := Decode(strings.NewReader(s)); err == nil {
+			t.Errorf("Decode(%q) = %+v, want an error", s, c)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		text    string
+		escaped string
+	}{
+		{"plain", "plain"},
+		{"a,b;c", `a\,b\;c`},
+		{"back\\slash", `back\\slash`},
+		{"two\nlines", `two\nlines`},
+		{"crlf\r\nline", `crlf\nline`},
+		{`\n is not a line break`, `\\n is not a line break`},
+		{"café ☕", "café ☕"},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.text); got != tt.escaped {
+			t.Errorf("EscapeText(%q) = %q, want %q", tt.text, got, tt.escaped)
+		}
+		want := strings.ReplaceAll(tt.text, "\r\n", "\n")
+		if got := UnescapeText(tt.escaped); got != want {
+			t.Errorf("UnescapeText(%q) = %q, want %q", tt.escaped, got, want)
+		}
+	}
+	if got := UnescapeText(`upper\Ncase`); got != "upper\ncase" {
+		t.Errorf(`UnescapeText("upper\\Ncase") = %q`, got)
+	}
+	if got, want := SplitText(`a\,b,c\;d,,e\\,`), []string{"a,b", "c;d", "", `e\`, ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitText = %q, want %q", got, want)
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		value  string
+		params []string
+		want   string // empty for an error
+	}{
+		{"20230410T090000Z", nil, "2023-04-10T09:00:00Z"},
+		{"20230410T090000", nil, "2023-04-10T09:00:00Z"},
+		{"20230410T090000", []string{"TZID", "Europe/Berlin"}, "2023-04-10T07:00:00Z"},
+		{"20230110T090000", []string{"TZID", "Europe/Berlin"}, "2023-01-10T08:00:00Z"},
+		{"20230410T090000", []string{"TZID", "America/New_York"}, "2023-04-10T13:00:00Z"},
+		{"20230410T090000Z", []string{"TZID", "Europe/Berlin"}, "2023-04-10T09:00:00Z"},
+		{"20230410", nil, "2023-04-10T00:00:00Z"},
+		{"20230410", []string{"VALUE", "DATE"}, "2023-04-10T00:00:00Z"},
+		{"20230410", []string{"VALUE", "DATE", "TZID", "Europe/Berlin"}, "2023-04-10T00:00:00Z"},
+		{"20230410T090000", []string{"TZID", "Mars/Olympus"}, ""},
+		{"2023-04-10", nil, ""},
+		{"20230410T0900", nil, ""},
+		{"20231310", []string{"VALUE", "DATE"}, ""},
+	}
+	for _, tt := range tests {
+		c := &Component{}
+		c.Add("DUE", tt.value, tt.params...)
+		got, err := c.Get("DUE").Time()
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Time(%q, %q) = %v, want an error", tt.value, tt.params, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Time(%q, %q) error = %v", tt.value, tt.params, err)
+			continue
+		}
+		if got.Format(time.RFC3339) != tt.want || got.Location() != time.UTC {
+			t.Errorf("Time(%q, %q) = %v, want %s", tt.value, tt.params, got, tt.want)
+		}
+	}
+}
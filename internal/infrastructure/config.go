@@ -5,18 +5,35 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	StorageDriverMySQL  = "mysql"
+	StorageDriverSQLite = "sqlite"
+	StorageDriverMemory = "memory"
+)
+
 type Config struct {
-	MysqlPoolMin           int    `mapstructure:"MYSQL_POOL_MIN"`
-	MysqlPoolMax           int    `mapstructure:"MYSQL_POOL_MAX"`
-	MysqlIdleMax           int    `mapstructure:"MYSQL_IDLE_MAX"`
-	MysqlMaxIdleTimeMinute int    `mapstructure:"MYSQL_MAX_IDLE_TIME_MINUTE"`
-	MysqlMaxLifeTimeMinute int    `mapstructure:"MYSQL_MAX_LIFE_TIME_MINUTE"`
-	MysqlHost              string `mapstructure:"MYSQL_HOST"`
-	MysqlPort              int    `mapstructure:"MYSQL_PORT"`
-	MysqlUser              string `mapstructure:"MYSQL_USER"`
-	MysqlPassword          string `mapstructure:"MYSQL_PASSWORD"`
-	MysqlDBName            string `mapstructure:"MYSQL_DBNAME"`
-	MigrationSource        string `mapstructure:"MIGRATION_SOURCE"`
+	StorageDriver              string `mapstructure:"STORAGE_DRIVER"`
+	RequestTimeoutSecond       int    `mapstructure:"REQUEST_TIMEOUT_SECOND"`
+	MysqlPoolMin               int    `mapstructure:"MYSQL_POOL_MIN"`
+	MysqlPoolMax               int    `mapstructure:"MYSQL_POOL_MAX"`
+	MysqlIdleMax               int    `mapstructure:"MYSQL_IDLE_MAX"`
+	MysqlMaxIdleTimeMinute     int    `mapstructure:"MYSQL_MAX_IDLE_TIME_MINUTE"`
+	MysqlMaxLifeTimeMinute     int    `mapstructure:"MYSQL_MAX_LIFE_TIME_MINUTE"`
+	MysqlHost                  string `mapstructure:"MYSQL_HOST"`
+	MysqlPort                  int    `mapstructure:"MYSQL_PORT"`
+	MysqlUser                  string `mapstructure:"MYSQL_USER"`
+	MysqlPassword              string `mapstructure:"MYSQL_PASSWORD"`
+	MysqlDBName                string `mapstructure:"MYSQL_DBNAME"`
+	MigrationSource            string `mapstructure:"MIGRATION_SOURCE"`
+	SqlitePath                 string `mapstructure:"SQLITE_PATH"`
+	SqliteMigrationSource      string `mapstructure:"SQLITE_MIGRATION_SOURCE"`
+	TrashRetentionDay          int    `mapstructure:"TRASH_RETENTION_DAY"`
+	TrashPurgeIntervalMinute   int    `mapstructure:"TRASH_PURGE_INTERVAL_MINUTE"`
+	JWTSecret                  string `mapstructure:"JWT_SECRET"`
+	JWTAccessTTLMinute         int    `mapstructure:"JWT_ACCESS_TTL_MINUTE"`
+	JWTRefreshTTLHour          int    `mapstructure:"JWT_REFRESH_TTL_HOUR"`
+	WorkspaceMaxActivityGroups int64  `mapstructure:"WORKSPACE_MAX_ACTIVITY_GROUPS"`
+	WorkspaceMaxTodos          int64  `mapstructure:"WORKSPACE_MAX_TODOS"`
 }
 
 func NewConfig(configName string) *Config {
@@ -25,6 +42,15 @@ func NewConfig(configName string) *Config {
 	viper.SetConfigName(configName)
 	viper.SetConfigType("env")
 
+	viper.SetDefault("STORAGE_DRIVER", StorageDriverMySQL)
+	viper.SetDefault("REQUEST_TIMEOUT_SECOND", 10)
+	viper.SetDefault("SQLITE_PATH", "todo.db")
+	viper.SetDefault("SQLITE_MIGRATION_SOURCE", "file://migrations/sqlite")
+	viper.SetDefault("TRASH_RETENTION_DAY", 30)
+	viper.SetDefault("TRASH_PURGE_INTERVAL_MINUTE", 60)
+	viper.SetDefault("JWT_ACCESS_TTL_MINUTE", 15)
+	viper.SetDefault("JWT_REFRESH_TTL_HOUR", 720)
+
 	viper.AutomaticEnv()
 
 	err := viper.ReadInConfig()
@@ -37,3 +63,14 @@ func NewConfig(configName string) *Config {
 	}
 	return config
 }
+
+// WorkspaceQuotas returns the quotas of new workspaces, nil for unlimited.
+func (cfg *Config) WorkspaceQuotas() (maxActivityGroups, maxTodos *int64) {
+	quota := func(n int64) *int64 {
+		if n <= 0 {
+			return nil
+		}
+		return &n
+	}
+	return quota(cfg.WorkspaceMaxActivityGroups), quota(cfg.WorkspaceMaxTodos)
+}
@@ -0,0 +1,23 @@
+package infrastructure
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewDatabase opens the SQL database selected by STORAGE_DRIVER. The memory
+// driver does not use SQL at all, so it gets a nil *sql.DB.
+func NewDatabase(cfg *Config) *sql.DB {
+	switch cfg.StorageDriver {
+	case StorageDriverMySQL:
+		return NewMySQLDatabase(cfg)
+	case StorageDriverSQLite:
+		return NewSQLiteDatabase(cfg)
+	case StorageDriverMemory:
+		return nil
+	default:
+		logrus.Fatalf("unknown STORAGE_DRIVER %q", cfg.StorageDriver)
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+// MemoryDatabase is the backing store of the memory storage driver. Rows are
+// kept by value so repositories never hand out pointers into the store.
+type MemoryDatabase struct {
+	mu               sync.RWMutex
+	sequences        map[string]int64
+	Activities       map[int64]entity.Activity
+	Todos            map[int64]entity.Todo
+	ActivityEvents   map[int64]entity.ActivityEvent
+	TodoEvents       map[int64]entity.TodoEvent
+	TodoTransitions  map[int64]entity.TodoTransition
+	Tags             map[int64]entity.Tag
+	TodoTags         map[entity.TodoTag]struct{}
+	TodoWatchers     map[entity.WatcherKey]entity.TodoWatcher
+	TodoComments     map[int64]entity.TodoComment
+	CalendarFeeds    map[int64]entity.CalendarFeed
+	Users            map[int64]entity.User
+	APIKeys          map[int64]entity.APIKey
+	ActivityMembers  map[entity.MemberKey]entity.ActivityMember
+	Workspaces       map[int64]entity.Workspace
+	WorkspaceMembers map[entity.WorkspaceMemberKey]entity.WorkspaceMember
+}
+
+type memoryTxKey struct{}
+
+// NewMemoryDatabase returns an empty store holding only the default
+// workspace, as the migrations leave a new database.
+func NewMemoryDatabase() *MemoryDatabase {
+	db := &MemoryDatabase{
+		sequences:        make(map[string]int64),
+		Activities:       make(map[int64]entity.Activity),
+		Todos:            make(map[int64]entity.Todo),
+		ActivityEvents:   make(map[int64]entity.ActivityEvent),
+		TodoEvents:       make(map[int64]entity.TodoEvent),
+		TodoTransitions:  make(map[int64]entity.TodoTransition),
+		Tags:             make(map[int64]entity.Tag),
+		TodoTags:         make(map[entity.TodoTag]struct{}),
+		TodoWatchers:     make(map[entity.WatcherKey]entity.TodoWatcher),
+		TodoComments:     make(map[int64]entity.TodoComment),
+		CalendarFeeds:    make(map[int64]entity.CalendarFeed),
+		Users:            make(map[int64]entity.User),
+		APIKeys:          make(map[int64]entity.APIKey),
+		ActivityMembers:  make(map[entity.MemberKey]entity.ActivityMember),
+		Workspaces:       make(map[int64]entity.Workspace),
+		WorkspaceMembers: make(map[entity.WorkspaceMemberKey]entity.WorkspaceMember),
+	}
+
+	now := db.Now()
+	w := entity.Workspace{Name: "Default", Slug: "default", CreatedAt: now, UpdatedAt: now}
+	w.ID = db.NextID(w.TableName())
+	db.Workspaces[w.ID] = w
+	return db
+}
+
+// Lock takes the write lock and returns the function releasing it. Inside a
+// transaction the lock is already held, so both are no-ops.
+func (db *MemoryDatabase) Lock(ctx context.Context) func() {
+	if db.inTransaction(ctx) {
+		return func() {}
+	}
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// RLock is the read-only counterpart of Lock.
+func (db *MemoryDatabase) RLock(ctx context.Context) func() {
+	if db.inTransaction(ctx) {
+		return func() {}
+	}
+	db.mu.RLock()
+	return db.mu.RUnlock
+}
+
+// Transaction runs fn while holding the write lock and restores the previous
+// contents of every table when fn fails or panics.
+func (db *MemoryDatabase) Transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if db.inTransaction(ctx) {
+		return fn(ctx)
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	snapshot := db.snapshot()
+	defer func() {
+		if p := recover(); p != nil {
+			db.restore(snapshot)
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, memoryTxKey{}, db)); err != nil {
+		db.restore(snapshot)
+	}
+	return err
+}
+
+// NextID returns the next auto increment value for table. Callers must hold
+// the write lock.
+func (db *MemoryDatabase) NextID(table string) int64 {
+	db.sequences[table]++
+	return db.sequences[table]
+}
+
+// Now returns the current time at the DATETIME precision of the SQL drivers.
+func (db *MemoryDatabase) Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (db *MemoryDatabase) inTransaction(ctx context.Context) bool {
+	tx, ok := ctx.Value(memoryTxKey{}).(*MemoryDatabase)
+	return ok && tx == db
+}
+
+// snapshot copies the tables so a failed transaction can be rolled back.
+// Values are never modified in place, so shallow copies are enough.
+func (db *MemoryDatabase) snapshot() *MemoryDatabase {
+	return &MemoryDatabase{
+		sequences:        cloneMap(db.sequences),
+		Activities:       cloneMap(db.Activities),
+		Todos:            cloneMap(db.Todos),
+		ActivityEvents:   cloneMap(db.ActivityEvents),
+		TodoEvents:       cloneMap(db.TodoEvents),
+		TodoTransitions:  cloneMap(db.TodoTransitions),
+		Tags:             cloneMap(db.Tags),
+		TodoTags:         cloneMap(db.TodoTags),
+		TodoWatchers:     cloneMap(db.TodoWatchers),
+		TodoComments:     cloneMap(db.TodoComments),
+		CalendarFeeds:    cloneMap(db.CalendarFeeds),
+		Users:            cloneMap(db.Users),
+		APIKeys:          cloneMap(db.APIKeys),
+		ActivityMembers:  cloneMap(db.ActivityMembers),
+		Workspaces:       cloneMap(db.Workspaces),
+		WorkspaceMembers: cloneMap(db.WorkspaceMembers),
+	}
+}
+
+func (db *MemoryDatabase) restore(snapshot *MemoryDatabase) {
+	db.sequences = snapshot.sequences
+	db.Activities = snapshot.Activities
+	db.Todos = snapshot.Todos
+	db.ActivityEvents = snapshot.ActivityEvents
+	db.TodoEvents = snapshot.TodoEvents
+	db.TodoTransitions = snapshot.TodoTransitions
+	db.Tags = snapshot.Tags
+	db.TodoTags = snapshot.TodoTags
+	db.TodoWatchers = snapshot.TodoWatchers
+	db.TodoComments = snapshot.TodoComments
+	db.CalendarFeeds = snapshot.CalendarFeeds
+	db.Users = snapshot.Users
+	db.APIKeys = snapshot.APIKeys
+	db.ActivityMembers = snapshot.ActivityMembers
+	db.Workspaces = snapshot.Workspaces
+	db.WorkspaceMembers = snapshot.WorkspaceMembers
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	clone := make(map[K]V, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
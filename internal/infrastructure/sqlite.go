@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+)
+
+func NewSQLiteDatabase(cfg *Config) *sql.DB {
+	ctx, cancel := NewMySQLContext()
+	defer cancel()
+
+	sqlDB, err := sql.Open("sqlite", cfg.SqlitePath)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	// SQLite serializes writers, so a single connection avoids SQLITE_BUSY
+	// errors and keeps the per-connection pragmas below in effect.
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+
+	_, err = sqlDB.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	return sqlDB
+}
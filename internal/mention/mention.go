@@ -0,0 +1,42 @@
+// Package mention finds the users a text mentions. A mention is an email
+// address prefixed with @, as in "review with @ann@example.com", starting
+// the text or following a character that cannot be part of an address.
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+var pattern = regexp.MustCompile(`(?:^|[^\w.%+@-])@([\w.%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`)
+
+// Emails returns the addresses text mentions, lowercased, in the order they
+// first appear and without duplicates.
+func Emails(text string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		email := strings.ToLower(m[1])
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// Added returns the addresses after mentions that before does not, so
+// editing a text only acts on the mentions the edit adds.
+func Added(before, after string) []string {
+	old := make(map[string]bool)
+	for _, email := range Emails(before) {
+		old[email] = true
+	}
+	var added []string
+	for _, email := range Emails(after) {
+		if !old[email] {
+			added = append(added, email)
+		}
+	}
+	return added
+}
@@ -0,0 +1,67 @@
+package mention
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmails(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"@ann@example.com", []string{"ann@example.com"}},
+		{"review with @ann@example.com", []string{"ann@example.com"}},
+		{"@Ann.Lee+todo@Example.CO.uk", []string{"ann.lee+todo@example.co.uk"}},
+
+		// Trailing punctuation ends the address.
+		{"ask @ann@example.com.", []string{"ann@example.com"}},
+		{"ask @ann@example.com, then @bob@example.org;", []string{"ann@example.com", "bob@example.org"}},
+		{"(@ann@example.com)", []string{"ann@example.com"}},
+		{"ping @ann@example.com! or @bob@example.org?", []string{"ann@example.com", "bob@example.org"}},
+		{"@ann@example.com's review", []string{"ann@example.com"}},
+		{`"@ann@example.com":`, []string{"ann@example.com"}},
+		{"@ann@example.com-", []string{"ann@example.com"}},
+		{"@ann@example.com...", []string{"ann@example.com"}},
+
+		// Addresses inside words or without the @ are no mentions.
+		{"ann@example.com", nil},
+		{"mail bob@ann@example.com", nil},
+		{"x.@ann@example.com", nil},
+		{"a-@ann@example.com", nil},
+		{"a+@ann@example.com", nil},
+		{"@@ann@example.com", nil},
+		{"@ann@example", nil},
+		{"@ann@example.c", nil},
+		{"@ann", nil},
+
+		// Each address once, in the order it first appears.
+		{"@bob@example.org @ann@example.com @bob@example.org", []string{"bob@example.org", "ann@example.com"}},
+		{"@ann@example.com and @ANN@EXAMPLE.COM", []string{"ann@example.com"}},
+		{"@ann@example.com,@bob@example.org\n@ann@example.com", []string{"ann@example.com", "bob@example.org"}},
+	}
+	for _, tt := range tests {
+		if got := Emails(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Emails(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAdded(t *testing.T) {
+	tests := []struct {
+		before, after string
+		want          []string
+	}{
+		{"", "@ann@example.com", []string{"ann@example.com"}},
+		{"@ann@example.com", "@ann@example.com again", nil},
+		{"@ann@example.com", "@ANN@example.com and @bob@example.org", []string{"bob@example.org"}},
+		{"@ann@example.com @bob@example.org", "@bob@example.org", nil},
+		{"ann@example.com", "@ann@example.com", []string{"ann@example.com"}},
+	}
+	for _, tt := range tests {
+		if got := Added(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Added(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
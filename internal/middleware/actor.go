@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+// HeaderActor names the client supplied header identifying who makes the
+// request, recorded as the actor of every history event.
+const HeaderActor = "X-Actor"
+
+// Actor stores the request actor in the user context. It must run after
+// RequestContext, which replaces the user context.
+func Actor() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Fiber reuses the header buffer after the request, and the actor
+		// may outlive it in the memory store.
+		if actor := strings.TrimSpace(utils.CopyString(c.Get(HeaderActor))); actor != "" {
+			c.SetUserContext(model.WithActor(c.UserContext(), actor))
+		}
+		return c.Next()
+	}
+}
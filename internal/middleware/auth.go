@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/auth"
+)
+
+// HeaderAPIKey carries an API key, for clients that cannot send it as a
+// bearer token.
+const HeaderAPIKey = "X-API-Key"
+
+// HeaderWorkspace picks the workspace of a request by slug or id, among the
+// workspaces of its user.
+const HeaderWorkspace = "X-Workspace"
+
+// Auth rejects requests without a valid access token or API key, and scopes
+// the user context of the others to the data of their user and workspace.
+// The user also becomes the actor of the request, whatever X-Actor says. It
+// must run after RequestContext and Actor.
+func Auth(authUC auth.AuthUC) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		credentials := web.Credentials{
+			APIKey:    strings.TrimSpace(c.Get(HeaderAPIKey)),
+			Workspace: strings.TrimSpace(c.Get(HeaderWorkspace)),
+		}
+		if scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			credentials.BearerToken = strings.TrimSpace(token)
+		}
+
+		identity, err := authUC.Authenticate(c.UserContext(), credentials)
+		if err != nil {
+			if apperror.KindOf(err) == apperror.KindUnauthorized {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="golang-todo-api"`)
+			}
+			return err
+		}
+
+		ctx := model.WithUser(c.UserContext(), identity.User.ID)
+		ctx = model.WithWorkspace(ctx, identity.WorkspaceID)
+		c.SetUserContext(model.WithActor(ctx, identity.User.Email))
+		return c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestContext bounds every request with a deadline and stores the derived
+// context as the Fiber user context, so handlers pass c.UserContext() down to
+// the repositories. fasthttp does not report client disconnects, so the
+// deadline is what bounds abandoned requests; the context is also canceled
+// when the server shuts down.
+func RequestContext(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		// fasthttp recycles the RequestCtx once the handler returns, so the
+		// shutdown channel has to be read before the goroutine starts.
+		shutdown := c.Context().Done()
+		go func() {
+			select {
+			case <-shutdown:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
@@ -12,6 +12,25 @@ type Activity struct {
 	Email     string
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
+	DeletedAt *time.Time
+	Version   int64 `gorm:"not null;default:1"`
+	Workflow  *Workflow
+	// WorkspaceID is set by the repository from the context.
+	WorkspaceID int64
+}
+
+// ActivityHit is TodoHit for activity groups.
+type ActivityHit struct {
+	Activity *Activity
+	Score    int64
+}
+
+// WorkflowOrDefault returns the workflow todos of the group follow.
+func (a Activity) WorkflowOrDefault() *Workflow {
+	if a.Workflow == nil {
+		return DefaultWorkflow()
+	}
+	return a.Workflow
 }
 
 func (Activity) TableName() string {
@@ -19,11 +38,16 @@ func (Activity) TableName() string {
 }
 
 func (a Activity) ToDTO() *web.ActivityDTO {
-	return &web.ActivityDTO{
+	dto := &web.ActivityDTO{
 		ID:        a.ID,
 		Title:     a.Title,
 		Email:     a.Email,
 		CreatedAt: a.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: a.UpdatedAt.Format(time.RFC3339),
+		Version:   a.Version,
+	}
+	if a.DeletedAt != nil {
+		dto.DeletedAt = a.DeletedAt.Format(time.RFC3339)
 	}
+	return dto
 }
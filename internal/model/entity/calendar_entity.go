@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// CalendarFeed grants read access to the calendar of an activity group, or
+// of all groups of UserID when ActivityGroupID is nil. The token itself is
+// only shown when the feed is created.
+type CalendarFeed struct {
+	ID              int64 `gorm:"column:feed_id;primaryKey"`
+	ActivityGroupID *int64
+	UserID          *int64
+	TokenHash       string
+	CreatedAt       time.Time `gorm:"not null"`
+	// WorkspaceID is set by the repository from the context.
+	WorkspaceID int64
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+func (f CalendarFeed) ToDTO(token, url string) *web.CalendarFeedDTO {
+	return &web.CalendarFeedDTO{
+		ActivityGroupID: f.ActivityGroupID,
+		Token:           token,
+		URL:             url,
+		CreatedAt:       f.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// TodoComment is a Markdown comment on a todo. Email and Name are those of
+// the author, filled in when comments are read.
+type TodoComment struct {
+	ID        int64 `gorm:"column:comment_id;primaryKey"`
+	TodoID    int64
+	UserID    int64
+	Body      string
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+	Email     string    `gorm:"-"`
+	Name      string    `gorm:"-"`
+}
+
+func (TodoComment) TableName() string {
+	return "todo_comments"
+}
+
+func (c TodoComment) ToDTO() *web.CommentDTO {
+	return &web.CommentDTO{
+		ID:        c.ID,
+		TodoID:    c.TodoID,
+		UserID:    c.UserID,
+		Email:     c.Email,
+		Name:      c.Name,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
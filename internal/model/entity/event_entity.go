@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+const (
+	EventActionCreate  = "create"
+	EventActionUpdate  = "update"
+	EventActionDelete  = "delete"
+	EventActionRestore = "restore"
+)
+
+// FieldChange holds the value of a single field before and after a write. A
+// nil From marks a field set on create.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type Changes map[string]FieldChange
+
+type TodoEvent struct {
+	ID        int64 `gorm:"column:event_id;primaryKey"`
+	TodoID    int64
+	Action    string
+	Changes   Changes
+	Actor     string
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (TodoEvent) TableName() string {
+	return "todo_events"
+}
+
+func NewTodoEvent(action string, before, after *Todo, actor string) TodoEvent {
+	e := TodoEvent{
+		Action:  action,
+		Changes: diff(todoFields(before), todoFields(after)),
+		Actor:   actor,
+	}
+	if after != nil {
+		e.TodoID = after.ID
+	} else {
+		e.TodoID = before.ID
+	}
+	return e
+}
+
+// NewTodoTagEvent records a change to the tags of a todo, listing the tag
+// names before and after it.
+func NewTodoTagEvent(todoID int64, before, after []string, actor string) TodoEvent {
+	return newTodoListEvent(todoID, "tags", before, after, actor)
+}
+
+// NewTodoWatcherEvent records a change to the watchers of a todo, listing
+// their emails before and after it.
+func NewTodoWatcherEvent(todoID int64, before, after []string, actor string) TodoEvent {
+	return newTodoListEvent(todoID, "watchers", before, after, actor)
+}
+
+// NewTodoCommentEvent records a comment written, edited or deleted on a
+// todo. A nil before marks a new comment, a nil after a deleted one.
+func NewTodoCommentEvent(todoID int64, before, after *TodoComment, actor string) TodoEvent {
+	return TodoEvent{
+		TodoID:  todoID,
+		Action:  EventActionUpdate,
+		Changes: Changes{"comment": {From: commentFields(before), To: commentFields(after)}},
+		Actor:   actor,
+	}
+}
+
+func newTodoListEvent(todoID int64, field string, before, after []string, actor string) TodoEvent {
+	return TodoEvent{
+		TodoID: todoID,
+		Action: EventActionUpdate,
+		Changes: Changes{
+			field: {From: strings.Join(before, ","), To: strings.Join(after, ",")},
+		},
+		Actor: actor,
+	}
+}
+
+func (e TodoEvent) ToDTO() *web.EventDTO {
+	return newEventDTO(e.ID, e.Action, e.Changes, e.Actor, e.CreatedAt)
+}
+
+type ActivityEvent struct {
+	ID         int64 `gorm:"column:event_id;primaryKey"`
+	ActivityID int64
+	Action     string
+	Changes    Changes
+	Actor      string
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (ActivityEvent) TableName() string {
+	return "activity_events"
+}
+
+func NewActivityEvent(action string, before, after *Activity, actor string) ActivityEvent {
+	e := ActivityEvent{
+		Action:  action,
+		Changes: diff(activityFields(before), activityFields(after)),
+		Actor:   actor,
+	}
+	if after != nil {
+		e.ActivityID = after.ID
+	} else {
+		e.ActivityID = before.ID
+	}
+	return e
+}
+
+func (e ActivityEvent) ToDTO() *web.EventDTO {
+	return newEventDTO(e.ID, e.Action, e.Changes, e.Actor, e.CreatedAt)
+}
+
+// todoFields lists the user visible fields tracked in the history. Values
+// must be comparable with ==.
+func todoFields(t *Todo) map[string]interface{} {
+	if t == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"title":             t.Title,
+		"activity_group_id": t.ActivityGroupID,
+		"is_active":         t.IsActive,
+		"priority":          t.Priority,
+		"status":            t.Status,
+		"start_at":          formatOptionalTime(t.StartAt),
+		"due_at":            formatOptionalTime(t.DueAt),
+		"recurrence":        formatOptional(t.Recurrence),
+		"series_id":         formatOptional(t.SeriesID),
+		"parent_todo_id":    formatOptional(t.ParentTodoID),
+		"auto_complete":     t.AutoComplete,
+		"position":          t.Position,
+		"assignee_id":       formatOptional(t.AssigneeID),
+		"deleted_at":        formatOptionalTime(t.DeletedAt),
+	}
+}
+
+func commentFields(c *TodoComment) interface{} {
+	if c == nil {
+		return nil
+	}
+	return map[string]interface{}{"id": c.ID, "body": c.Body}
+}
+
+func activityFields(a *Activity) map[string]interface{} {
+	if a == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"title":      a.Title,
+		"email":      a.Email,
+		"deleted_at": formatOptionalTime(a.DeletedAt),
+		"workflow":   formatWorkflow(a.Workflow),
+	}
+}
+
+func diff(before, after map[string]interface{}) Changes {
+	changes := make(Changes)
+	for field, to := range after {
+		if from := before[field]; from != to {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	return changes
+}
+
+// formatWorkflow renders a workflow as JSON so it can be compared with ==.
+func formatWorkflow(w *Workflow) interface{} {
+	if w == nil {
+		return nil
+	}
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil
+	}
+	return string(b)
+}
+
+// formatOptional dereferences p so the value compares with ==.
+func formatOptional[T any](p *T) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func formatOptionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func newEventDTO(id int64, action string, changes Changes, actor string, createdAt time.Time) *web.EventDTO {
+	dto := &web.EventDTO{
+		ID:        id,
+		Action:    action,
+		Changes:   make(map[string]web.FieldChangeDTO, len(changes)),
+		Actor:     actor,
+		CreatedAt: createdAt,
+	}
+	for field, c := range changes {
+		dto.Changes[field] = web.FieldChangeDTO{From: c.From, To: c.To}
+	}
+	return dto
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+const (
+	MemberRoleOwner  = "owner"
+	MemberRoleEditor = "editor"
+	MemberRoleViewer = "viewer"
+)
+
+// memberRoleRanks orders the roles; each one may do everything the roles
+// below it may.
+var memberRoleRanks = map[string]int{
+	MemberRoleViewer: 1,
+	MemberRoleEditor: 2,
+	MemberRoleOwner:  3,
+}
+
+// ActivityMember gives a user access to an activity group. Email and Name
+// are those of the user, filled in when members are listed.
+type ActivityMember struct {
+	ActivityID int64 `gorm:"primaryKey"`
+	UserID     int64 `gorm:"primaryKey"`
+	Role       string
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+	Email      string    `gorm:"-"`
+	Name       string    `gorm:"-"`
+}
+
+// MemberKey identifies a member in the memory store.
+type MemberKey struct {
+	ActivityID int64
+	UserID     int64
+}
+
+func (ActivityMember) TableName() string {
+	return "activity_members"
+}
+
+func (m ActivityMember) Key() MemberKey {
+	return MemberKey{ActivityID: m.ActivityID, UserID: m.UserID}
+}
+
+// Can reports whether the role of m is at least role.
+func (m ActivityMember) Can(role string) bool {
+	return memberRoleRanks[m.Role] >= memberRoleRanks[role]
+}
+
+func (m ActivityMember) ToDTO() *web.MemberDTO {
+	return &web.MemberDTO{
+		UserID:    m.UserID,
+		Email:     m.Email,
+		Name:      m.Name,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+}
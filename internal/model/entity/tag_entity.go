@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type Tag struct {
+	ID        int64 `gorm:"column:tag_id;primaryKey"`
+	Name      string
+	Color     *string
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+	// WorkspaceID is set by the repository from the context.
+	WorkspaceID int64
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (t Tag) ToDTO() *web.TagDTO {
+	return &web.TagDTO{
+		ID:        t.ID,
+		Name:      t.Name,
+		Color:     t.Color,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// TodoTag links a todo to one of its tags.
+type TodoTag struct {
+	TodoID int64
+	TagID  int64
+}
+
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
@@ -6,6 +6,22 @@ import (
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
 )
 
+// DefaultPriority is the priority of todos created without one.
+const DefaultPriority = "very-high"
+
+// Priorities lists the todo priorities from least to most urgent.
+var Priorities = []string{"very-low", "low", "normal", "high", "very-high"}
+
+// PriorityRank orders priorities by urgency; unknown values rank lowest.
+func PriorityRank(priority string) int64 {
+	for i, p := range Priorities {
+		if p == priority {
+			return int64(i + 1)
+		}
+	}
+	return 0
+}
+
 type Todo struct {
 	ID              int64 `gorm:"column:todo_id;primaryKey"`
 	ActivityGroupID int64
@@ -14,6 +30,49 @@ type Todo struct {
 	Priority        string    `gorm:"default:very-high"`
 	CreatedAt       time.Time `gorm:"not null"`
 	UpdatedAt       time.Time `gorm:"not null"`
+	DeletedAt       *time.Time
+	Version         int64  `gorm:"not null;default:1"`
+	Status          string `gorm:"not null;default:todo"`
+	StartAt         *time.Time
+	DueAt           *time.Time
+	// Recurrence is the RRULE of a recurring todo. Only the open occurrence
+	// of a series carries it; completing it hands the rule to the next one.
+	Recurrence *string
+	// SeriesID is the ID of the first occurrence, set once a series has a
+	// second one.
+	SeriesID *int64
+	// ParentTodoID makes the todo a subtask. Subtasks cannot have subtasks
+	// of their own.
+	ParentTodoID *int64
+	// AutoComplete completes the todo once its last open subtask is done.
+	AutoComplete bool
+	// Position is the rank ordering the todo within its activity group.
+	Position string
+	// AssigneeID is the member of the activity group responsible for the
+	// todo, if any.
+	AssigneeID *int64
+	// WorkspaceID is set by the repository from the context.
+	WorkspaceID int64
+}
+
+// TodoHit is a todo matching a search. Score is how well it matches, in
+// units of search.ScoreScale: the higher, the better.
+type TodoHit struct {
+	Todo  *Todo
+	Score int64
+}
+
+// TodoProgress counts the live subtasks of a todo.
+type TodoProgress struct {
+	Completed int64
+	Total     int64
+}
+
+func (p TodoProgress) ToDTO() *web.TodoProgressDTO {
+	return &web.TodoProgressDTO{
+		Completed: p.Completed,
+		Total:     p.Total,
+	}
 }
 
 func (Todo) TableName() string {
@@ -29,5 +88,16 @@ func (t Todo) ToDTO() *web.TodoDTO {
 		Priority:        t.Priority,
 		CreatedAt:       t.CreatedAt,
 		UpdatedAt:       t.UpdatedAt,
+		DeletedAt:       t.DeletedAt,
+		Version:         t.Version,
+		Status:          t.Status,
+		StartAt:         t.StartAt,
+		DueAt:           t.DueAt,
+		Recurrence:      t.Recurrence,
+		SeriesID:        t.SeriesID,
+		ParentTodoID:    t.ParentTodoID,
+		AutoComplete:    t.AutoComplete,
+		Position:        t.Position,
+		AssigneeID:      t.AssigneeID,
 	}
 }
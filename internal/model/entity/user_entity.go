@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type User struct {
+	ID           int64 `gorm:"column:user_id;primaryKey"`
+	Email        string
+	Name         string
+	PasswordHash string
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+func (u User) ToDTO() *web.UserDTO {
+	return &web.UserDTO{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// APIKey lets scripts act as a user without a password. The key itself is
+// only shown when it is created; Prefix, its first characters, tells keys
+// apart.
+type APIKey struct {
+	ID         int64 `gorm:"column:api_key_id;primaryKey"`
+	UserID     int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"not null"`
+	// WorkspaceID binds the key to a workspace.
+	WorkspaceID *int64
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func (k APIKey) ToDTO() *web.APIKeyDTO {
+	return &web.APIKeyDTO{
+		ID:          k.ID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		LastUsedAt:  k.LastUsedAt,
+		CreatedAt:   k.CreatedAt,
+		WorkspaceID: k.WorkspaceID,
+	}
+}
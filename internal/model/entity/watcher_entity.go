@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// TodoWatcher has a user follow a todo. Email and Name are those of the
+// user, filled in when watchers are listed.
+type TodoWatcher struct {
+	TodoID    int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+	Email     string    `gorm:"-"`
+	Name      string    `gorm:"-"`
+}
+
+// WatcherKey identifies a watcher in the memory store.
+type WatcherKey struct {
+	TodoID int64
+	UserID int64
+}
+
+func (TodoWatcher) TableName() string {
+	return "todo_watchers"
+}
+
+func (w TodoWatcher) Key() WatcherKey {
+	return WatcherKey{TodoID: w.TodoID, UserID: w.UserID}
+}
+
+func (w TodoWatcher) ToDTO() *web.WatcherDTO {
+	return &web.WatcherDTO{
+		UserID: w.UserID,
+		Email:  w.Email,
+		Name:   w.Name,
+	}
+}
@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusBlocked    = "blocked"
+	StatusDone       = "done"
+	StatusArchived   = "archived"
+)
+
+var statusPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// Workflow is the todo state machine of an activity group. Transitions holds
+// every status as a key, mapped to the statuses it may move to. Todos in a
+// Closed status are inactive; setting is_active moves a todo to Initial or
+// Done, so legacy clients keep working.
+type Workflow struct {
+	Initial     string              `json:"initial"`
+	Done        string              `json:"done"`
+	Closed      []string            `json:"closed"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// DefaultWorkflow is used by activity groups without a workflow of their own.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Initial: StatusTodo,
+		Done:    StatusDone,
+		Closed:  []string{StatusDone, StatusArchived},
+		Transitions: map[string][]string{
+			StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusArchived},
+			StatusInProgress: {StatusTodo, StatusBlocked, StatusDone},
+			StatusBlocked:    {StatusTodo, StatusInProgress, StatusDone},
+			StatusDone:       {StatusTodo, StatusInProgress, StatusArchived},
+			StatusArchived:   {StatusTodo},
+		},
+	}
+}
+
+// Validate reports the first inconsistency in the workflow definition.
+func (w *Workflow) Validate() error {
+	if len(w.Transitions) == 0 {
+		return fmt.Errorf("transitions must list at least one status")
+	}
+	for _, from := range w.Statuses() {
+		targets := w.Transitions[from]
+		if !statusPattern.MatchString(from) {
+			return fmt.Errorf("status %q must be 1 to 32 lowercase letters, digits, '_' or '-'", from)
+		}
+		for _, to := range targets {
+			if !w.Has(to) {
+				return fmt.Errorf("status %q moves to undefined status %q", from, to)
+			}
+		}
+	}
+	for _, status := range w.Closed {
+		if !w.Has(status) {
+			return fmt.Errorf("closed status %q is not defined", status)
+		}
+	}
+	switch {
+	case !w.Has(w.Initial):
+		return fmt.Errorf("initial status %q is not defined", w.Initial)
+	case w.IsClosed(w.Initial):
+		return fmt.Errorf("initial status %q cannot be closed", w.Initial)
+	case !w.Has(w.Done):
+		return fmt.Errorf("done status %q is not defined", w.Done)
+	case !w.IsClosed(w.Done):
+		return fmt.Errorf("done status %q must be closed", w.Done)
+	}
+	return nil
+}
+
+func (w *Workflow) Has(status string) bool {
+	_, ok := w.Transitions[status]
+	return ok
+}
+
+func (w *Workflow) IsClosed(status string) bool {
+	for _, s := range w.Closed {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) CanTransition(from, to string) bool {
+	for _, s := range w.Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses lists the defined statuses in a stable order.
+func (w *Workflow) Statuses() []string {
+	statuses := make([]string, 0, len(w.Transitions))
+	for status := range w.Transitions {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// StatusFor returns the status matching an is_active flag: the current one
+// when it already agrees, otherwise Initial or Done.
+func (w *Workflow) StatusFor(current string, isActive bool) string {
+	if w.Has(current) && w.IsClosed(current) != isActive {
+		return current
+	}
+	if isActive {
+		return w.Initial
+	}
+	return w.Done
+}
+
+func (w *Workflow) ToDTO() *web.WorkflowDTO {
+	return &web.WorkflowDTO{
+		Initial:     w.Initial,
+		Done:        w.Done,
+		Closed:      w.Closed,
+		Transitions: w.Transitions,
+	}
+}
+
+type TodoTransition struct {
+	ID         int64 `gorm:"column:transition_id;primaryKey"`
+	TodoID     int64
+	FromStatus *string
+	ToStatus   string
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (TodoTransition) TableName() string {
+	return "todo_status_transitions"
+}
+
+func (t TodoTransition) ToDTO() *web.TodoTransitionDTO {
+	return &web.TodoTransitionDTO{
+		ID:        t.ID,
+		From:      t.FromStatus,
+		To:        t.ToStatus,
+		CreatedAt: t.CreatedAt,
+	}
+}
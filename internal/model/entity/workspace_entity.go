@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// PersonalWorkspaceSlugPrefix starts the slug of the workspace every user
+// gets when signing up, followed by their id.
+const PersonalWorkspaceSlugPrefix = "personal-"
+
+// Workspace holds the data of one team. A nil quota is unlimited.
+type Workspace struct {
+	ID                int64 `gorm:"column:workspace_id;primaryKey"`
+	Name              string
+	Slug              string
+	MaxActivityGroups *int64
+	MaxTodos          *int64
+	CreatedAt         time.Time `gorm:"not null"`
+	UpdatedAt         time.Time `gorm:"not null"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceMember lets a user work in a workspace.
+type WorkspaceMember struct {
+	WorkspaceID int64     `gorm:"primaryKey"`
+	UserID      int64     `gorm:"primaryKey"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// WorkspaceMemberKey identifies a workspace member in the memory store.
+type WorkspaceMemberKey struct {
+	WorkspaceID int64
+	UserID      int64
+}
+
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+func (m WorkspaceMember) Key() WorkspaceMemberKey {
+	return WorkspaceMemberKey{WorkspaceID: m.WorkspaceID, UserID: m.UserID}
+}
+
+// WorkspaceUsage counts the rows a workspace holds against its quotas,
+// trashed ones included.
+type WorkspaceUsage struct {
+	ActivityGroups int64
+	Todos          int64
+}
+
+func (w Workspace) ToDTO(usage WorkspaceUsage) *web.WorkspaceDTO {
+	return &web.WorkspaceDTO{
+		ID:   w.ID,
+		Name: w.Name,
+		Slug: w.Slug,
+		ActivityGroups: web.QuotaDTO{
+			Used:  usage.ActivityGroups,
+			Limit: w.MaxActivityGroups,
+		},
+		Todos: web.QuotaDTO{
+			Used:  usage.Todos,
+			Limit: w.MaxTodos,
+		},
+		CreatedAt: w.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: w.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -1,8 +1,89 @@
 package model
 
-import "errors"
+import "github.com/vnnyx/golang-todo-api/internal/apperror"
 
 var (
-	ErrTitleCannotBeNull           = errors.New("title cannot be null")
-	ErrActivityGroupIDCannotBeNull = errors.New("activity_group_id cannot be null")
+	ErrValidationFailed       = apperror.Unprocessable("validation_failed", "request failed validation")
+	ErrActivityGroupNotFound  = apperror.Unprocessable("unknown_activity_group", "activity_group_id does not reference an existing activity group")
+	ErrActivityGroupNotEmpty  = apperror.Conflict("activity_group_not_empty", "activity group still contains todos")
+	ErrActivityGroupTrashed   = apperror.Conflict("activity_group_trashed", "activity group is in the trash, restore it first")
+	ErrInvalidDeleteMode      = apperror.InvalidField("invalid_delete_mode", "mode", "mode must be one of cascade, restrict or move")
+	ErrMoveTargetCannotBeNull = apperror.InvalidField("move_target_required", "target", "target cannot be null when mode is move")
+	ErrMoveTargetNotFound     = apperror.Unprocessable("unknown_move_target", "target does not reference an existing activity group")
+	ErrMoveTargetIsSameGroup  = apperror.InvalidField("move_target_same_group", "target", "target must be a different activity group")
+	ErrInvalidSort            = apperror.InvalidField("invalid_sort", "sort", "sort is not supported")
+	ErrInvalidOrder           = apperror.InvalidField("invalid_order", "order", "order must be asc or desc")
+	ErrInvalidLimit           = apperror.InvalidField("invalid_limit", "limit", "limit must be between 1 and 100")
+	ErrInvalidOffset          = apperror.InvalidField("invalid_offset", "offset", "offset cannot be negative")
+	ErrInvalidCursor          = apperror.InvalidField("invalid_cursor", "cursor", "cursor is invalid")
+	ErrInvalidDate            = apperror.Validation("invalid_date", "date filters must be RFC 3339 timestamps or YYYY-MM-DD dates")
+	ErrInvalidTimezone        = apperror.InvalidField("invalid_timezone", "tz", "tz must be an IANA timezone name such as Europe/Berlin")
+	ErrInvalidDays            = apperror.InvalidField("invalid_days", "days", "days must be between 1 and 365")
+	ErrStartAfterDue          = apperror.InvalidField("start_after_due", "start_at", "start_at cannot be later than due_at")
+	ErrInvalidRecurrence      = apperror.InvalidField("invalid_recurrence", "recurrence", "recurrence is not a supported RRULE")
+	ErrParentTodoNotFound     = apperror.Unprocessable("unknown_parent_todo", "parent_todo_id does not reference an existing todo")
+	ErrParentTodoTrashed      = apperror.Conflict("parent_todo_trashed", "parent todo is in the trash, restore it first")
+	ErrNestedSubtask          = apperror.InvalidField("nested_subtask", "parent_todo_id", "subtasks cannot have subtasks of their own")
+	ErrParentGroupMismatch    = apperror.InvalidField("parent_group_mismatch", "parent_todo_id", "a subtask must be in the activity group of its parent")
+	ErrInvalidLayout          = apperror.InvalidField("invalid_layout", "layout", "layout must be flat or tree")
+	ErrMoveNeighbourNotFound  = apperror.Unprocessable("unknown_move_neighbour", "before_id and after_id must reference todos of the activity group the todo moves to")
+	ErrMoveNeighbourIsSelf    = apperror.Validation("move_neighbour_self", "a todo cannot be moved next to itself")
+	ErrMoveNeighbourOrder     = apperror.Unprocessable("move_neighbour_order", "after_id must come before before_id")
+	ErrInvalidOperationCount  = apperror.InvalidField("invalid_operation_count", "operations", "operations must hold between 1 and 100 operations")
+	ErrInvalidOperation       = apperror.InvalidField("invalid_operation", "op", "op must be one of create, update, delete or move")
+	ErrInvalidTarget          = apperror.Validation("invalid_target", "an operation must target the todos it applies to")
+	ErrEmptyFilter            = apperror.InvalidField("empty_filter", "filter", "filter must have at least one condition")
+	ErrTooManyTodos           = apperror.Unprocessable("too_many_todos", "filter matches more than 1000 todos")
+	ErrInvalidFormat          = apperror.InvalidField("invalid_format", "format", "format must be one of json, csv, todotxt or markdown")
+	ErrInvalidImportFormat    = apperror.InvalidField("invalid_format", "format", "format must be one of json, csv, todotxt, markdown or ics")
+	ErrInvalidImport          = apperror.Unprocessable("invalid_import", "the import could not be read")
+	ErrImportTitleRequired    = apperror.InvalidField("import_title_required", "title", "title is required when the import does not carry one")
+	ErrImportTooLarge         = apperror.Unprocessable("import_too_large", "an import can create at most 1000 todos")
+	ErrInvalidComponent       = apperror.InvalidField("invalid_component", "component", "component must be vtodo or vevent")
+	ErrInvalidTagMode         = apperror.InvalidField("invalid_tag_mode", "tag_mode", "tag_mode must be any or all")
+	ErrSearchQueryRequired    = apperror.InvalidField("search_query_required", "q", "q must contain at least one word")
+	ErrUnknownInvitee         = apperror.Unprocessable("unknown_invitee", "email does not belong to an account in the workspace")
+	ErrUnknownAssignee        = apperror.Unprocessable("unknown_assignee", "assignee_id does not reference a member of the activity group")
+	ErrUnknownWatcher         = apperror.Unprocessable("unknown_watcher", "only members of the activity group can watch its todos")
+	ErrUnknownReference       = apperror.Unprocessable("unknown_reference", "the request references a resource that does not exist")
+	ErrInvalidAssignee        = apperror.InvalidField("invalid_assignee", "assignee", "assignee must be me, none or a user id")
+	ErrInvalidSearchType      = apperror.InvalidField("invalid_search_type", "type", "type must be todo or activity_group")
+	ErrTagNameTaken           = apperror.Conflict("tag_name_taken", "a tag with the same name already exists")
+	ErrEmailTaken             = apperror.Conflict("email_taken", "an account with this email already exists")
+	ErrAlreadyMember          = apperror.Conflict("already_member", "the user is already a member of the activity group")
+	ErrSlugTaken              = apperror.Conflict("slug_taken", "a workspace with the same slug already exists")
+	ErrAlreadyWorkspaceMember = apperror.Conflict("already_workspace_member", "the user is already a member of the workspace")
+	ErrOwnerRole              = apperror.Conflict("owner_role", "the role of the owner can only change by transferring ownership")
+	ErrVersionMismatch        = apperror.PreconditionFailed("version_mismatch", "version does not match the current version of the resource")
+	ErrInvalidPriority        = apperror.InvalidField("invalid_priority", "priority", "priority must be one of very-high, high, normal, low or very-low")
+	ErrInvalidStatus          = apperror.InvalidField("invalid_status", "status", "status is not defined in the workflow of the activity group")
+	ErrStatusConflict         = apperror.InvalidField("status_conflict", "is_active", "is_active does not match the status")
+	ErrInvalidTransition      = apperror.Conflict("invalid_transition", "the workflow of the activity group does not allow this status change")
+	ErrInvalidWorkflow        = apperror.Unprocessable("invalid_workflow", "workflow is invalid")
+	ErrWorkflowStatusInUse    = apperror.Conflict("workflow_status_in_use", "todos of the activity group still use a status the workflow removes")
+	ErrInvalidID              = apperror.InvalidField("invalid_id", "id", "id must be a positive integer")
+	ErrInvalidBody            = apperror.BadRequest("invalid_body", "request body could not be parsed")
+	ErrInvalidQuery           = apperror.BadRequest("invalid_query", "query parameters could not be parsed")
+	ErrUnauthenticated        = apperror.Unauthorized("unauthenticated", "a bearer access token or an X-API-Key header is required")
+	ErrInvalidCredentials     = apperror.Unauthorized("invalid_credentials", "email or password is incorrect")
+	ErrInvalidToken           = apperror.Unauthorized("invalid_token", "token is invalid or expired")
+	ErrInvalidAPIKey          = apperror.Unauthorized("invalid_api_key", "API key is invalid or revoked")
+	ErrInsufficientRole       = apperror.Forbidden("insufficient_role", "your role in the activity group does not allow this")
+	ErrNotCommentAuthor       = apperror.Forbidden("not_comment_author", "only the author of a comment can change it")
+	ErrWorkspaceForbidden     = apperror.Forbidden("workspace_forbidden", "you are not a member of the workspace or your credentials are bound to another one")
+	ErrNoWorkspaceMembership  = apperror.Forbidden("no_workspace", "you are not a member of any workspace")
+	ErrActivityQuotaExceeded  = apperror.Forbidden("activity_group_quota_exceeded", "the workspace has reached its quota of activity groups")
+	ErrTodoQuotaExceeded      = apperror.Forbidden("todo_quota_exceeded", "the workspace has reached its quota of todos")
+	ErrNoWorkspace            = apperror.New(apperror.KindInternal, "no_workspace_in_context", "no workspace was resolved for the request")
+	ErrTodoNotFound           = apperror.NotFound("todo_not_found", "todo not found")
+	ErrTodoNotInTrash         = apperror.NotFound("todo_not_in_trash", "todo not found in trash")
+	ErrActivityNotFound       = apperror.NotFound("activity_group_not_found", "activity group not found")
+	ErrActivityNotInTrash     = apperror.NotFound("activity_group_not_in_trash", "activity group not found in trash")
+	ErrTagNotFound            = apperror.NotFound("tag_not_found", "tag not found")
+	ErrCalendarFeedNotFound   = apperror.NotFound("calendar_feed_not_found", "calendar feed not found")
+	ErrUserNotFound           = apperror.NotFound("user_not_found", "user not found")
+	ErrAPIKeyNotFound         = apperror.NotFound("api_key_not_found", "API key not found")
+	ErrMemberNotFound         = apperror.NotFound("member_not_found", "member not found")
+	ErrCommentNotFound        = apperror.NotFound("comment_not_found", "comment not found")
+	ErrWorkspaceNotFound      = apperror.NotFound("workspace_not_found", "workspace not found")
 )
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"time"
+
+	// Embedded so timezone names resolve on hosts without zoneinfo.
+	_ "time/tzdata"
+)
+
+const MaxLimit = 100
+
+// Pagination selects a page of a listing. A non-empty Cursor takes precedence
+// over Offset and carries its own sort order. A zero Limit returns every row.
+type Pagination struct {
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+type PageInfo struct {
+	Total int64
+	Next  string
+	Prev  string
+}
+
+type TodoFilter struct {
+	ActivityGroupID int64
+	IsActive        *bool
+	Priorities      []string
+	Statuses        []string
+	Title           string
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	UpdatedAfter    *time.Time
+	UpdatedBefore   *time.Time
+	DueAfter        *time.Time
+	DueBefore       *time.Time
+	Overdue         *bool
+	SeriesID        int64
+	ParentTodoID    int64
+	// TopLevel leaves out subtasks.
+	TopLevel bool
+	// Scheduled leaves out todos without a due date.
+	Scheduled bool
+	// Tags matches todos carrying any of the named tags, or all of them
+	// with TagMatchAll.
+	Tags        []string
+	TagMatchAll bool
+	// AssigneeID matches the todos assigned to a user; Unassigned those
+	// assigned to no one.
+	AssigneeID int64
+	Unassigned bool
+}
+
+type ActivityFilter struct {
+	Title         string
+	Email         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+// NewPagination validates the listing parameters of a request. sorts lists
+// the sort keys the listing supports.
+func NewPagination(sort, order string, limit, offset int, cursor string, sorts ...string) (Pagination, error) {
+	p := Pagination{
+		Sort:   sort,
+		Limit:  limit,
+		Offset: offset,
+		Cursor: cursor,
+	}
+	if sort != "" {
+		supported := false
+		for _, s := range sorts {
+			supported = supported || s == sort
+		}
+		if !supported {
+			return p, ErrInvalidSort
+		}
+	}
+	switch order {
+	case "", "asc":
+	case "desc":
+		p.Desc = true
+	default:
+		return p, ErrInvalidOrder
+	}
+	if limit < 0 || limit > MaxLimit {
+		return p, ErrInvalidLimit
+	}
+	if offset < 0 {
+		return p, ErrInvalidOffset
+	}
+	return p, nil
+}
+
+// ParseTime parses a date filter given either as an RFC 3339 timestamp or as
+// a YYYY-MM-DD date in UTC. An empty string yields nil.
+func ParseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, ErrInvalidDate
+}
+
+// LoadLocation resolves an IANA timezone name, defaulting to UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// StartOfDay returns midnight of the day t falls on in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,27 @@
+package model
+
+import "context"
+
+type userKey struct{}
+
+// WithUser scopes ctx to the data of a user. Repositories then treat the
+// groups and todos of other users as missing.
+func WithUser(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext returns the user stored by WithUser. Contexts without one,
+// as in the command line and background workers, see every user's data.
+func UserFromContext(ctx context.Context) (userID int64, ok bool) {
+	userID, ok = ctx.Value(userKey{}).(int64)
+	return userID, ok
+}
+
+// OwnerFromContext returns the user of ctx as the owner of data it creates,
+// or nil.
+func OwnerFromContext(ctx context.Context) *int64 {
+	if userID, ok := UserFromContext(ctx); ok {
+		return &userID
+	}
+	return nil
+}
@@ -6,14 +6,67 @@ type ActivityDTO struct {
 	Email     string `json:"email"`
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
+	DeletedAt string `json:"deletedAt,omitempty"`
+	Version   int64  `json:"version"`
+	// Members is left out where groups are listed as part of something
+	// else, such as the trash.
+	Members []*MemberDTO `json:"members,omitempty"`
 }
 
 type ActivityCreateRequest struct {
-	Title string `json:"title"`
-	Email string `json:"email"`
+	Title string `json:"title" validate:"required,max=255"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type ActivityListRequest struct {
+	PageRequest
+	Title         string `query:"title"`
+	Email         string `query:"email"`
+	CreatedAfter  string `query:"created_after"`
+	CreatedBefore string `query:"created_before"`
+	UpdatedAfter  string `query:"updated_after"`
+	UpdatedBefore string `query:"updated_before"`
 }
 
 type ActivityUpdateRequest struct {
-	ID    int64
-	Title string `json:"title"`
+	ID      int64  `json:"-"`
+	Title   string `json:"title" validate:"required,max=255"`
+	Version *int64 `json:"version" validate:"min=1"`
+}
+
+const (
+	ActivityDeleteModeCascade  = "cascade"
+	ActivityDeleteModeRestrict = "restrict"
+	ActivityDeleteModeMove     = "move"
+)
+
+type ActivityDeleteRequest struct {
+	ID     int64
+	Mode   string
+	Target int64
+}
+
+// MaxImportTodos caps the todos, subtasks included, a single import may
+// create.
+const MaxImportTodos = 1000
+
+type ActivityExportRequest struct {
+	ID     int64
+	Format string
+}
+
+type ActivityImportRequest struct {
+	Format string
+	// Title overrides the title carried by the data, if any.
+	Title string
+	Data  []byte
+}
+
+// ActivityImportTodo holds the fields of an imported todo that are checked
+// the way those of a created todo are.
+type ActivityImportTodo struct {
+	Title      string `json:"title" validate:"required,max=255"`
+	Priority   string `json:"priority" validate:"omitempty,oneof=very-high high normal low very-low"`
+	Status     string `json:"status" validate:"max=32"`
+	Recurrence string `json:"recurrence" validate:"max=255"`
 }
@@ -0,0 +1,29 @@
+package web
+
+import "time"
+
+// Calendar components todos are rendered as.
+const (
+	CalendarComponentVTodo  = "vtodo"
+	CalendarComponentVEvent = "vevent"
+)
+
+type CalendarFeedDTO struct {
+	ActivityGroupID *int64 `json:"activity_group_id"`
+	Token           string `json:"token"`
+	// URL is the address calendar apps subscribe to, token included.
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CalendarRequest reads a calendar feed. A nil ActivityGroupID asks for the
+// feed of all groups.
+type CalendarRequest struct {
+	ActivityGroupID *int64
+	Token           string `query:"token"`
+	Component       string `query:"component"`
+}
+
+// MaxCalendarTodos caps the todos of a feed. The most recently created
+// ones are left out.
+const MaxCalendarTodos = 5000
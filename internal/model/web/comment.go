@@ -0,0 +1,33 @@
+package web
+
+import "time"
+
+// CommentDTO is a comment on a todo. Body is Markdown, returned as written.
+type CommentDTO struct {
+	ID        int64     `json:"id"`
+	TodoID    int64     `json:"todo_id"`
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CommentCreateRequest struct {
+	TodoID int64  `json:"-"`
+	Body   string `json:"body" validate:"required,max=10000"`
+}
+
+type CommentUpdateRequest struct {
+	TodoID int64  `json:"-"`
+	ID     int64  `json:"-"`
+	Body   string `json:"body" validate:"required,max=10000"`
+}
+
+// CommentListRequest pages through the comments of a todo, oldest first
+// unless another order is requested.
+type CommentListRequest struct {
+	PageRequest
+	TodoID int64 `query:"-"`
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag renders a resource version as a strong entity tag.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseIfMatch extracts the version an If-Match header refers to. It returns
+// nil for an empty header or "*", and ok is false when the header does not
+// hold a single entity tag produced by ETag.
+func ParseIfMatch(header string) (version *int64, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	// Weak validators never match under If-Match.
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return nil, false
+	}
+	v, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &v, true
+}
@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+type EventDTO struct {
+	ID        int64                     `json:"id"`
+	Action    string                    `json:"action"`
+	Changes   map[string]FieldChangeDTO `json:"changes"`
+	Actor     string                    `json:"actor"`
+	CreatedAt time.Time                 `json:"createdAt"`
+}
+
+type FieldChangeDTO struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
@@ -0,0 +1,31 @@
+package web
+
+type MemberDTO struct {
+	UserID    int64  `json:"user_id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// MemberInviteRequest adds the user with Email to an activity group. There
+// is only one owner, so invitees join as editors or viewers.
+type MemberInviteRequest struct {
+	ActivityGroupID int64  `json:"-"`
+	Email           string `json:"email" validate:"required,email,max=255"`
+	Role            string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type MemberUpdateRequest struct {
+	ActivityGroupID int64  `json:"-"`
+	UserID          int64  `json:"-"`
+	Role            string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+// OwnershipTransferRequest makes the member UserID the owner of an activity
+// group; the previous owner stays on as an editor.
+type OwnershipTransferRequest struct {
+	ActivityGroupID int64 `json:"-"`
+	UserID          int64 `json:"user_id" validate:"required,min=1"`
+}
@@ -0,0 +1,28 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// NullInt64 is an optional reference in a PATCH body. Like NullTime, Set
+// reports whether the key was sent and a null value clears the reference.
+type NullInt64 struct {
+	Set   bool
+	Int64 *int64
+}
+
+func (n *NullInt64) UnmarshalJSON(b []byte) error {
+	n.Set = true
+	if bytes.Equal(b, []byte("null")) {
+		n.Int64 = nil
+		return nil
+	}
+
+	var v int64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	n.Int64 = &v
+	return nil
+}
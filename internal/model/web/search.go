@@ -0,0 +1,40 @@
+package web
+
+// Kinds of search hits.
+const (
+	SearchTypeTodo          = "todo"
+	SearchTypeActivityGroup = "activity_group"
+)
+
+const (
+	DefaultSearchLimit = 20
+	// SearchHighlightWidth is how much of a long title a highlight shows.
+	SearchHighlightWidth = 160
+)
+
+// SearchRequest searches todo and activity group titles. Type narrows the
+// hits to one kind. The todo filters leave activity groups out, except for
+// ActivityGroupID which keeps that group alone.
+type SearchRequest struct {
+	Q               string   `query:"q"`
+	Type            string   `query:"type"`
+	ActivityGroupID int64    `query:"activity_group_id"`
+	IsActive        *bool    `query:"is_active"`
+	Priority        string   `query:"priority"`
+	Status          string   `query:"status"`
+	Tags            []string `query:"tag"`
+	Limit           int      `query:"limit"`
+	Offset          int      `query:"offset"`
+	Cursor          string   `query:"cursor"`
+}
+
+// SearchHitDTO is a todo or an activity group matching a search, best
+// first. Highlight is the matched title as HTML, with matches in <mark>.
+type SearchHitDTO struct {
+	Type          string       `json:"type"`
+	ID            int64        `json:"id"`
+	Score         float64      `json:"score"`
+	Highlight     string       `json:"highlight"`
+	Todo          *TodoDTO     `json:"todo,omitempty"`
+	ActivityGroup *ActivityDTO `json:"activity_group,omitempty"`
+}
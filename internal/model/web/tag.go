@@ -0,0 +1,34 @@
+package web
+
+import "time"
+
+type TagDTO struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type TagCreateRequest struct {
+	Name  string `json:"name" validate:"required,max=64"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+type TagUpdateRequest struct {
+	ID   int64  `json:"-"`
+	Name string `json:"name" validate:"max=64"`
+	// Color replaces the color of the tag; an empty string removes it.
+	Color *string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
+
+// TodoTagRequest adds a tag to or removes it from a todo.
+type TodoTagRequest struct {
+	TodoID int64
+	TagID  int64
+}
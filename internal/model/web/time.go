@@ -0,0 +1,30 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// NullTime is an optional timestamp in a PATCH body. Set reports whether the
+// key was sent at all; a null value sets it with a nil Time, which clears the
+// stored timestamp.
+type NullTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (t *NullTime) UnmarshalJSON(b []byte) error {
+	t.Set = true
+	if bytes.Equal(b, []byte("null")) {
+		t.Time = nil
+		return nil
+	}
+
+	var v time.Time
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	t.Time = &v
+	return nil
+}
@@ -1,27 +1,209 @@
 package web
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type TodoDTO struct {
-	ID              int64     `json:"id"`
-	Title           string    `json:"title"`
-	ActivityGroupID int64     `json:"activity_group_id"`
-	IsActive        bool      `json:"is_active"`
-	Priority        string    `json:"priority"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
+	ID              int64            `json:"id"`
+	Title           string           `json:"title"`
+	ActivityGroupID int64            `json:"activity_group_id"`
+	IsActive        bool             `json:"is_active"`
+	Priority        string           `json:"priority"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+	DeletedAt       *time.Time       `json:"deletedAt,omitempty"`
+	Version         int64            `json:"version"`
+	Status          string           `json:"status"`
+	StartAt         *time.Time       `json:"start_at"`
+	DueAt           *time.Time       `json:"due_at"`
+	Recurrence      *string          `json:"recurrence"`
+	SeriesID        *int64           `json:"series_id"`
+	ParentTodoID    *int64           `json:"parent_todo_id"`
+	AutoComplete    bool             `json:"auto_complete"`
+	Position        string           `json:"position"`
+	AssigneeID      *int64           `json:"assignee_id"`
+	Watchers        []*WatcherDTO    `json:"watchers,omitempty"`
+	CommentCount    int64            `json:"comment_count"`
+	Progress        *TodoProgressDTO `json:"progress,omitempty"`
+	Subtasks        []*TodoDTO       `json:"subtasks,omitempty"`
+	Tags            []*TagDTO        `json:"tags,omitempty"`
+}
+
+type TodoProgressDTO struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
 }
 
 type TodoCreateRequest struct {
-	Title           string `json:"title"`
-	ActivityGroupID int64  `json:"activity_group_id"`
-	IsActive        *bool  `json:"is_active"`
+	Title           string     `json:"title" validate:"required,max=255"`
+	ActivityGroupID int64      `json:"activity_group_id" validate:"required,min=1"`
+	IsActive        *bool      `json:"is_active"`
+	Status          string     `json:"status" validate:"max=32"`
+	StartAt         *time.Time `json:"start_at"`
+	DueAt           *time.Time `json:"due_at"`
+	Recurrence      string     `json:"recurrence" validate:"max=255"`
+	ParentTodoID    *int64     `json:"parent_todo_id" validate:"min=1"`
+	AutoComplete    bool       `json:"auto_complete"`
+	AssigneeID      *int64     `json:"assignee_id" validate:"min=1"`
+}
+
+// SubtaskCreateRequest creates a subtask in the activity group of its
+// parent.
+type SubtaskCreateRequest struct {
+	ParentTodoID int64      `json:"-"`
+	Title        string     `json:"title" validate:"required,max=255"`
+	IsActive     *bool      `json:"is_active"`
+	Status       string     `json:"status" validate:"max=32"`
+	StartAt      *time.Time `json:"start_at"`
+	DueAt        *time.Time `json:"due_at"`
+}
+
+type TodoListRequest struct {
+	PageRequest
+	ActivityGroupID int64    `query:"activity_group_id"`
+	IsActive        *bool    `query:"is_active"`
+	Priority        string   `query:"priority"`
+	Status          string   `query:"status"`
+	Title           string   `query:"title"`
+	CreatedAfter    string   `query:"created_after"`
+	CreatedBefore   string   `query:"created_before"`
+	UpdatedAfter    string   `query:"updated_after"`
+	UpdatedBefore   string   `query:"updated_before"`
+	DueAfter        string   `query:"due_after"`
+	DueBefore       string   `query:"due_before"`
+	Overdue         *bool    `query:"overdue"`
+	SeriesID        int64    `query:"series_id"`
+	ParentTodoID    int64    `query:"parent_todo_id"`
+	Tags            []string `query:"tag"`
+	// TagMode is any, matching todos with at least one of the tags, or all.
+	TagMode string `query:"tag_mode"`
+	// Layout is flat, listing every todo, or tree, listing top level todos
+	// with their subtasks nested.
+	Layout string `query:"layout"`
+	// Assignee is me, none or the id of a user.
+	Assignee string `query:"assignee"`
+}
+
+const (
+	TodoLayoutFlat = "flat"
+	TodoLayoutTree = "tree"
+)
+
+const (
+	AssigneeMe   = "me"
+	AssigneeNone = "none"
+)
+
+const (
+	TodoViewToday    = "today"
+	TodoViewUpcoming = "upcoming"
+	TodoViewOverdue  = "overdue"
+	TodoViewMine     = "mine"
+
+	DefaultUpcomingDays = 7
+	MaxUpcomingDays     = 365
+)
+
+// TodoViewRequest lists the open todos of a view across all activity
+// groups. Timezone is an IANA name deciding where a day starts and
+// Days bounds the upcoming view.
+type TodoViewRequest struct {
+	TodoListRequest
+	Timezone string `query:"tz"`
+	Days     int    `query:"days"`
+}
+
+// TodoMoveRequest places a todo right after AfterID, right before BeforeID,
+// or at the end of its activity group when neither is given.
+// ActivityGroupID moves it to another group on the way.
+type TodoMoveRequest struct {
+	ID              int64  `json:"-"`
+	AfterID         *int64 `json:"after_id" validate:"min=1"`
+	BeforeID        *int64 `json:"before_id" validate:"min=1"`
+	ActivityGroupID *int64 `json:"activity_group_id" validate:"min=1"`
+	Version         *int64 `json:"version" validate:"min=1"`
+}
+
+const (
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+	BulkOpMove   = "move"
+
+	MaxBulkOperations = 100
+	// MaxBulkTodos bounds the todos a single operation applies to.
+	MaxBulkTodos = 1000
+)
+
+// TodoBulkRequest runs Operations in order within one transaction: either
+// all of them are applied or none is.
+type TodoBulkRequest struct {
+	Operations []TodoBulkOperation `json:"operations"`
+}
+
+// TodoBulkOperation is a single operation of a bulk request. Update and
+// delete apply to the todo ID, the todos IDs or every todo matching Filter;
+// move applies to the todo ID and create to none. Data holds the body the
+// single todo endpoint takes: a TodoCreateRequest, TodoUpdateRequest or
+// TodoMoveRequest.
+type TodoBulkOperation struct {
+	Op     string          `json:"op"`
+	ID     int64           `json:"id"`
+	IDs    []int64         `json:"ids"`
+	Filter *TodoBulkFilter `json:"filter"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// TodoBulkFilter selects todos the way the query parameters of the todo
+// listing do.
+type TodoBulkFilter struct {
+	ActivityGroupID int64    `json:"activity_group_id"`
+	IsActive        *bool    `json:"is_active"`
+	Priority        string   `json:"priority"`
+	Status          string   `json:"status"`
+	Title           string   `json:"title"`
+	DueAfter        string   `json:"due_after"`
+	DueBefore       string   `json:"due_before"`
+	Overdue         *bool    `json:"overdue"`
+	SeriesID        int64    `json:"series_id"`
+	ParentTodoID    int64    `json:"parent_todo_id"`
+	Tags            []string `json:"tags"`
+	TagMode         string   `json:"tag_mode"`
+	Assignee        string   `json:"assignee"`
+}
+
+// TodoBulkResult reports what an operation did. IDs lists the todos it
+// applied to, which Todos holds unless they were deleted.
+type TodoBulkResult struct {
+	Index int        `json:"index"`
+	Op    string     `json:"op"`
+	IDs   []int64    `json:"ids"`
+	Todos []*TodoDTO `json:"todos,omitempty"`
 }
 
 type TodoUpdateRequest struct {
-	ID       int64
-	Title    string `json:"title"`
-	Priority string `json:"priority"`
-	IsActive *bool  `json:"is_active"`
-	Status   string `json:"status"`
+	ID       int64    `json:"-"`
+	Title    string   `json:"title" validate:"max=255"`
+	Priority string   `json:"priority" validate:"omitempty,oneof=very-high high normal low very-low"`
+	IsActive *bool    `json:"is_active"`
+	Status   string   `json:"status" validate:"max=32"`
+	StartAt  NullTime `json:"start_at"`
+	DueAt    NullTime `json:"due_at"`
+	// Recurrence replaces the rule of the series; an empty string stops it.
+	Recurrence   *string `json:"recurrence" validate:"max=255"`
+	AutoComplete *bool   `json:"auto_complete"`
+	// AssigneeID assigns the todo to a member of its group; null unassigns
+	// it.
+	AssigneeID NullInt64 `json:"assignee_id"`
+	Version    *int64    `json:"version" validate:"min=1"`
+}
+
+// TodoImportRequest adds the todos of an export to an existing activity
+// group.
+type TodoImportRequest struct {
+	ActivityGroupID int64
+	Format          string
+	Data            []byte
 }
@@ -0,0 +1,18 @@
+package web
+
+// TrashRequest pages through the trash, most recently deleted first.
+type TrashRequest struct {
+	Limit  int    `query:"limit"`
+	Cursor string `query:"cursor"`
+}
+
+// TrashDTO is a page of the trash, split by kind.
+type TrashDTO struct {
+	ActivityGroups []*ActivityDTO `json:"activity_groups"`
+	TodoItems      []*TodoDTO     `json:"todo_items"`
+}
+
+type TrashPurgeDTO struct {
+	ActivityGroups int64 `json:"activity_groups"`
+	TodoItems      int64 `json:"todo_items"`
+}
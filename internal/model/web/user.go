@@ -0,0 +1,72 @@
+package web
+
+import "time"
+
+type UserDTO struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// bcrypt ignores everything past 72 bytes, so longer passwords are refused
+// rather than silently cut.
+type RegisterRequest struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Name     string `json:"name" validate:"max=255"`
+}
+
+// LoginRequest may bind the session to a workspace, given by slug or id;
+// its tokens then only work there.
+type LoginRequest struct {
+	Email     string `json:"email" validate:"required"`
+	Password  string `json:"password" validate:"required"`
+	Workspace string `json:"workspace"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// AuthDTO carries the tokens of a session. ExpiresIn is the lifetime of the
+// access token in seconds.
+type AuthDTO struct {
+	User         *UserDTO `json:"user"`
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int64    `json:"expires_in"`
+}
+
+type APIKeyDTO struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	// Key is only set when the key is created.
+	Key         string     `json:"key,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	WorkspaceID *int64     `json:"workspace_id"`
+}
+
+// APIKeyCreateRequest may bind the key to a workspace, given by slug or id.
+type APIKeyCreateRequest struct {
+	Name      string `json:"name" validate:"required,max=64"`
+	Workspace string `json:"workspace"`
+}
+
+// Credentials are what a request authenticates with: a bearer access token
+// or an API key, and the workspace it asks for, if any.
+type Credentials struct {
+	BearerToken string
+	APIKey      string
+	Workspace   string
+}
+
+// Identity is the user a request acts as and the workspace it works in.
+type Identity struct {
+	User        *UserDTO
+	WorkspaceID int64
+}
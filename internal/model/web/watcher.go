@@ -0,0 +1,14 @@
+package web
+
+// WatcherDTO is a user following a todo.
+type WatcherDTO struct {
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+}
+
+// TodoWatcherRequest adds a watcher to or removes it from a todo.
+type TodoWatcherRequest struct {
+	TodoID int64
+	UserID int64
+}
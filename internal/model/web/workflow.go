@@ -0,0 +1,26 @@
+package web
+
+import "time"
+
+type WorkflowDTO struct {
+	Initial     string              `json:"initial"`
+	Done        string              `json:"done"`
+	Closed      []string            `json:"closed"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+type WorkflowUpdateRequest struct {
+	ActivityID  int64               `json:"-"`
+	Initial     string              `json:"initial" validate:"required"`
+	Done        string              `json:"done" validate:"required"`
+	Closed      []string            `json:"closed"`
+	Transitions map[string][]string `json:"transitions" validate:"required"`
+	Version     *int64              `json:"version" validate:"min=1"`
+}
+
+type TodoTransitionDTO struct {
+	ID        int64     `json:"id"`
+	From      *string   `json:"from"`
+	To        string    `json:"to"`
+	CreatedAt time.Time `json:"createdAt"`
+}
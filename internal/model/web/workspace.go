@@ -0,0 +1,42 @@
+package web
+
+type WorkspaceDTO struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Slug           string   `json:"slug"`
+	ActivityGroups QuotaDTO `json:"activity_groups"`
+	Todos          QuotaDTO `json:"todos"`
+	CreatedAt      string   `json:"createdAt"`
+	UpdatedAt      string   `json:"updatedAt"`
+}
+
+// QuotaDTO is the use of a quota. A null limit is unlimited.
+type QuotaDTO struct {
+	Used  int64  `json:"used"`
+	Limit *int64 `json:"limit"`
+}
+
+// WorkspaceCreateRequest sets up a workspace for a team. Nil quotas take the
+// configured defaults and zero ones are unlimited.
+type WorkspaceCreateRequest struct {
+	Name              string `json:"name" validate:"required,max=255"`
+	Slug              string `json:"slug" validate:"required,slug,max=64"`
+	MaxActivityGroups *int64 `json:"max_activity_groups" validate:"omitempty,min=0"`
+	MaxTodos          *int64 `json:"max_todos" validate:"omitempty,min=0"`
+}
+
+// WorkspaceUpdateRequest changes the fields that are not nil. Zero quotas
+// are unlimited.
+type WorkspaceUpdateRequest struct {
+	Workspace         string  `json:"workspace" validate:"required"`
+	Name              *string `json:"name" validate:"min=1,max=255"`
+	MaxActivityGroups *int64  `json:"max_activity_groups" validate:"omitempty,min=0"`
+	MaxTodos          *int64  `json:"max_todos" validate:"omitempty,min=0"`
+}
+
+// WorkspaceMemberRequest adds or removes the user with Email. Workspace is
+// the slug or the id of the workspace.
+type WorkspaceMemberRequest struct {
+	Workspace string `json:"workspace" validate:"required"`
+	Email     string `json:"email" validate:"required,email,max=255"`
+}
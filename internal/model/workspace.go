@@ -0,0 +1,36 @@
+package model
+
+import "context"
+
+type (
+	workspaceKey     struct{}
+	allWorkspacesKey struct{}
+)
+
+// WithWorkspace scopes ctx to the data of a workspace. Unlike users,
+// workspaces are not optional: repositories refuse contexts that have none,
+// and workspace ids start at 1, so a zero id counts as none.
+func WithWorkspace(ctx context.Context, workspaceID int64) context.Context {
+	ctx = context.WithValue(ctx, allWorkspacesKey{}, false)
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WithAllWorkspaces lets ctx see the data of every workspace, for jobs that
+// look after the whole database such as the trash purger.
+func WithAllWorkspaces(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allWorkspacesKey{}, true)
+}
+
+// WorkspaceFromContext returns the workspace stored by WithWorkspace. It
+// reports false for contexts without one, including ids below 1.
+func WorkspaceFromContext(ctx context.Context) (workspaceID int64, ok bool) {
+	workspaceID, ok = ctx.Value(workspaceKey{}).(int64)
+	return workspaceID, ok && workspaceID >= 1
+}
+
+// AllWorkspaces reports whether ctx was made by WithAllWorkspaces, and not
+// scoped to a single workspace since.
+func AllWorkspaces(ctx context.Context) bool {
+	all, _ := ctx.Value(allWorkspacesKey{}).(bool)
+	return all
+}
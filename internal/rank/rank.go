@@ -0,0 +1,128 @@
+// Package rank generates the lexicographic ranks that keep manually ordered
+// lists in order. A rank is a string of base 36 digits (0-9, a-z) compared
+// byte by byte, so a new rank always fits between two others at the cost of
+// a longer string:
+//
+//	Between("a", "b")  = "ai"
+//	Between("a", "")   = "b"   appending steps the first digit
+//	Between("", "1")   = "0i"
+//
+// Ranks never end in 0, as nothing would fit between "a" and "a0". Once
+// ranks grow past MaxLength the list should be rebalanced with Spread.
+package rank
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	base   = len(digits)
+
+	// MaxLength is the length past which a list should be rebalanced.
+	MaxLength = 24
+)
+
+// Valid reports whether r is a rank.
+func Valid(r string) bool {
+	if r == "" || r[len(r)-1] == '0' {
+		return false
+	}
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(digits, r[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a rank sorting after a and before b. An empty a stands for
+// the start of the list and an empty b for its end.
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", fmt.Errorf("rank: %q or %q is not a rank", a, b)
+	}
+	if b != "" && a >= b {
+		return "", fmt.Errorf("rank: %q does not sort before %q", a, b)
+	}
+	return midpoint(a, b), nil
+}
+
+func midpoint(a, b string) string {
+	if a == "" && b == "" {
+		return digits[base/2 : base/2+1]
+	}
+	if b != "" {
+		// Digits a and b share are kept; a counts as padded with zeros.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	lo := strings.IndexByte(digits, digitAt(a, 0))
+	if b == "" {
+		if lo+1 < base {
+			return digits[lo+1 : lo+2]
+		}
+		return digits[lo:lo+1] + midpoint(tail(a), "")
+	}
+	hi := strings.IndexByte(digits, b[0])
+	if hi-lo > 1 {
+		mid := (lo + hi) / 2
+		return digits[mid : mid+1]
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return digits[lo:lo+1] + midpoint(tail(a), "")
+}
+
+// Spread returns n evenly spaced ranks, as short as the gaps between them
+// allow.
+func Spread(n int) []string {
+	width, space := 1, int64(base)
+	for space/int64(n+1) < int64(base) {
+		width++
+		space *= int64(base)
+	}
+	step := space / int64(n+1)
+
+	ranks := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		ranks = append(ranks, encode(int64(i)*step, width))
+	}
+	return ranks
+}
+
+// encode renders v with width digits, dropping trailing zeros.
+func encode(v int64, width int) string {
+	b := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		b[i] = digits[v%int64(base)]
+		v /= int64(base)
+	}
+	return strings.TrimRight(string(b), "0")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
+
+func tail(s string) string {
+	if len(s) > 1 {
+		return s[1:]
+	}
+	return ""
+}
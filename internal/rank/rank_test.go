@@ -0,0 +1,165 @@
+package rank
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string // empty to only check the order
+	}{
+		{"", "", "i"},
+		{"a", "b", "ai"},
+		{"a", "", "b"},
+		{"", "1", "0i"},
+		{"", "01", ""},
+		{"", "001", ""},
+		{"z", "", ""},
+		{"zzz", "", ""},
+		{"1", "2", ""},
+		{"a", "a1", ""},
+		{"a", "a01", ""},
+		{"az", "b", ""},
+		{"azz", "b", ""},
+		{"ay", "b1", ""},
+		{"0i", "1", ""},
+		{"1", "z", ""},
+		{"a1", "a2", ""},
+		{"abc", "abd", ""},
+		{"abc", "abc1", ""},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("Between(%q, %q) error = %v", tt.a, tt.b, err)
+			continue
+		}
+		if tt.want != "" && got != tt.want {
+			t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		checkBetween(t, tt.a, got, tt.b)
+	}
+}
+
+func checkBetween(t *testing.T, a, got, b string) {
+	t.Helper()
+	if !Valid(got) {
+		t.Errorf("Between(%q, %q) = %q, which is not a rank", a, b, got)
+	}
+	if got <= a || (b != "" && got >= b) {
+		t.Errorf("Between(%q, %q) = %q, which does not sort between them", a, b, got)
+	}
+}
+
+func TestBetweenRejects(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"b", "a"},
+		{"a", "a"},
+		{"a0", "b"},
+		{"a", "B"},
+		{"a-", ""},
+		{"", "0"},
+	}
+	for _, tt := range tests {
+		if got, err := Between(tt.a, tt.b); err == nil {
+			t.Errorf("Between(%q, %q) = %q, want an error", tt.a, tt.b, got)
+		}
+	}
+}
+
+// TestBetweenRepeatedly inserts at the same place over and over, the worst
+// case for the length of ranks, until they grow past MaxLength and the list
+// needs rebalancing.
+func TestBetweenRepeatedly(t *testing.T) {
+	for _, afterA := range []bool{true, false} {
+		a, b := "a", "b"
+		inserts := 0
+		for ; ; inserts++ {
+			r := mustBetween(t, a, b)
+			checkBetween(t, a, r, b)
+			if len(r) > MaxLength {
+				break
+			}
+			if afterA {
+				b = r
+			} else {
+				a = r
+			}
+		}
+		if inserts < 2*MaxLength {
+			t.Errorf("afterA=%v: ranks outgrew MaxLength after %d inserts", afterA, inserts)
+		}
+	}
+}
+
+func mustBetween(t *testing.T, a, b string) string {
+	t.Helper()
+	r, err := Between(a, b)
+	if err != nil {
+		t.Fatalf("Between(%q, %q) error = %v", a, b, err)
+	}
+	return r
+}
+
+func TestBetweenKeepsListsOrdered(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	list := []string{}
+	for i := 0; i < 2000; i++ {
+		at := rng.Intn(len(list) + 1)
+		a, b := "", ""
+		if at > 0 {
+			a = list[at-1]
+		}
+		if at < len(list) {
+			b = list[at]
+		}
+		r := mustBetween(t, a, b)
+		checkBetween(t, a, r, b)
+		list = append(list[:at], append([]string{r}, list[at:]...)...)
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1] >= list[i] {
+			t.Fatalf("list[%d] = %q does not sort before list[%d] = %q", i-1, list[i-1], i, list[i])
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		n         int
+		maxLength int
+	}{
+		{1, 1},
+		{2, 1},
+		{34, 2},
+		{35, 2},
+		{36, 2},
+		{1000, 3},
+		{10000, 4},
+	}
+	for _, tt := range tests {
+		ranks := Spread(tt.n)
+		if len(ranks) != tt.n {
+			t.Fatalf("Spread(%d) returned %d ranks", tt.n, len(ranks))
+		}
+		for i, r := range ranks {
+			if !Valid(r) {
+				t.Fatalf("Spread(%d)[%d] = %q, which is not a rank", tt.n, i, r)
+			}
+			if len(r) > tt.maxLength {
+				t.Errorf("Spread(%d)[%d] = %q, longer than %d", tt.n, i, r, tt.maxLength)
+			}
+			if i > 0 && ranks[i-1] >= r {
+				t.Fatalf("Spread(%d)[%d] = %q does not sort after %q", tt.n, i, r, ranks[i-1])
+			}
+		}
+		// Rebalanced lists leave room on both ends and in every gap.
+		mustBetween(t, "", ranks[0])
+		mustBetween(t, ranks[len(ranks)-1], "")
+		for i := 1; i < len(ranks); i++ {
+			checkBetween(t, ranks[i-1], mustBetween(t, ranks[i-1], ranks[i]), ranks[i])
+		}
+	}
+}
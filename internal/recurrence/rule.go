@@ -0,0 +1,300 @@
+// Package recurrence parses and evaluates the subset of RFC 5545 RRULEs that
+// recurring todos support:
+//
+//	FREQ=DAILY|WEEKLY|MONTHLY|YEARLY  required
+//	INTERVAL=N                        every N periods, 1 to 1000
+//	BYDAY=MO,WE,FR                    weekdays of WEEKLY rules
+//	BYMONTHDAY=1,15,-1                days of MONTHLY rules, negative from the end
+//	COUNT=N                           occurrences left, including the current one
+//	UNTIL=20231231 or 20231231T235959Z
+//	X-ANCHOR=DUE|COMPLETION           count from the due date (default) or
+//	                                  from when the todo was completed
+//
+// Occurrences are computed in UTC and keep the time of day of their anchor.
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+
+	AnchorDue        = "DUE"
+	AnchorCompletion = "COMPLETION"
+
+	maxInterval = 1000
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+	Anchor     string
+}
+
+// Parse reads an RRULE value, with or without the "RRULE:" prefix.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	r := &Rule{Interval: 1, Anchor: AnchorDue}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		value = strings.ToUpper(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%q is not a NAME=VALUE pair", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = value
+			default:
+				return nil, fmt.Errorf("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+			}
+		case "INTERVAL":
+			if r.Interval, err = parseInt(value, 1, maxInterval); err != nil {
+				return nil, fmt.Errorf("INTERVAL %s", err)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("BYDAY %q is not one of MO, TU, WE, TH, FR, SA or SU", day)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				d, err := parseInt(day, -31, 31)
+				if err != nil || d == 0 {
+					return nil, fmt.Errorf("BYMONTHDAY %q must be between 1 and 31 or -31 and -1", day)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, d)
+			}
+		case "COUNT":
+			if r.Count, err = parseInt(value, 1, 1<<20); err != nil {
+				return nil, fmt.Errorf("COUNT %s", err)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		case "X-ANCHOR":
+			if value != AnchorDue && value != AnchorCompletion {
+				return nil, fmt.Errorf("X-ANCHOR must be DUE or COMPLETION")
+			}
+			r.Anchor = value
+		default:
+			return nil, fmt.Errorf("%s is not supported", name)
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return nil, fmt.Errorf("FREQ is required")
+	case len(r.ByDay) > 0 && r.Freq != FreqWeekly:
+		return nil, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	case len(r.ByMonthDay) > 0 && r.Freq != FreqMonthly:
+		return nil, fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	case r.Count > 0 && r.Until != nil:
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	sort.Slice(r.ByDay, func(i, j int) bool { return r.ByDay[i] < r.ByDay[j] })
+	sort.Ints(r.ByMonthDay)
+	return r, nil
+}
+
+// String renders the rule in a canonical form, so equal rules compare equal.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			days = append(days, strings.ToUpper(wd.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, d := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Anchor == AnchorCompletion {
+		parts = append(parts, "X-ANCHOR="+AnchorCompletion)
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after anchor and the rule the following
+// occurrence carries, with COUNT used up by one. ok is false once the series
+// is over.
+func (r *Rule) Next(anchor time.Time) (next time.Time, rest *Rule, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, nil, false
+	}
+
+	anchor = anchor.UTC()
+	switch r.Freq {
+	case FreqDaily:
+		next = anchor.AddDate(0, 0, r.Interval)
+	case FreqWeekly:
+		next = r.nextWeekly(anchor)
+	case FreqMonthly:
+		next = r.nextMonthly(anchor)
+	case FreqYearly:
+		next = r.nextYearly(anchor)
+	}
+	if next.IsZero() || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, nil, false
+	}
+
+	rest = r.clone()
+	if rest.Count > 0 {
+		rest.Count--
+	}
+	return next, rest, true
+}
+
+func (r *Rule) nextWeekly(anchor time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return anchor.AddDate(0, 0, 7*r.Interval)
+	}
+	week := startOfWeek(anchor)
+	for d := 1; d <= 7*r.Interval+7; d++ {
+		day := anchor.AddDate(0, 0, d)
+		weeks := int(startOfWeek(day).Sub(week).Hours()+12) / (7 * 24)
+		if weeks%r.Interval == 0 && r.hasWeekday(day.Weekday()) {
+			return day
+		}
+	}
+	return time.Time{}
+}
+
+func (r *Rule) nextMonthly(anchor time.Time) time.Time {
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{anchor.Day()}
+	}
+	y, m, _ := anchor.Date()
+	// Months without a matching day are skipped, so give up after a few
+	// years' worth of candidates.
+	for i := 0; i <= 48*r.Interval; i += r.Interval {
+		first := time.Date(y, m+time.Month(i), 1, anchor.Hour(), anchor.Minute(), anchor.Second(), 0, time.UTC)
+		last := first.AddDate(0, 1, -1).Day()
+		var candidates []int
+		for _, d := range days {
+			if d < 0 {
+				d = last + 1 + d
+			}
+			if d >= 1 && d <= last {
+				candidates = append(candidates, d)
+			}
+		}
+		sort.Ints(candidates)
+		for _, d := range candidates {
+			if day := first.AddDate(0, 0, d-1); day.After(anchor) {
+				return day
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (r *Rule) nextYearly(anchor time.Time) time.Time {
+	for i := r.Interval; i <= 8*r.Interval; i += r.Interval {
+		day := time.Date(anchor.Year()+i, anchor.Month(), anchor.Day(), anchor.Hour(), anchor.Minute(), anchor.Second(), 0, time.UTC)
+		// February 29th only exists in leap years.
+		if day.Day() == anchor.Day() {
+			return day
+		}
+	}
+	return time.Time{}
+}
+
+func (r *Rule) hasWeekday(wd time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) clone() *Rule {
+	c := *r
+	c.ByDay = append([]time.Weekday(nil), r.ByDay...)
+	c.ByMonthDay = append([]int(nil), r.ByMonthDay...)
+	return &c
+}
+
+// startOfWeek returns midnight of the Monday starting the week of t, the
+// RFC 5545 default for WKST.
+func startOfWeek(t time.Time) time.Time {
+	y, m, d := t.Date()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(y, m, d-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func parseInt(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("must be an integer between %d and %d", min, max)
+	}
+	return n, nil
+}
+
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", s); err == nil {
+		// A bare date includes the whole day.
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+}
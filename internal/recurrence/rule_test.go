@@ -0,0 +1,179 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=DAILY", "FREQ=DAILY"},
+		{"  freq=daily;interval=1 ", "FREQ=DAILY"},
+		{"FREQ=WEEKLY;BYDAY=FR,MO;INTERVAL=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=15,-1,1", "FREQ=MONTHLY;BYMONTHDAY=-1,1,15"},
+		{"FREQ=MONTHLY;COUNT=3", "FREQ=MONTHLY;COUNT=3"},
+		{"FREQ=YEARLY;UNTIL=20231231", "FREQ=YEARLY;UNTIL=20231231T235959Z"},
+		{"FREQ=YEARLY;UNTIL=20231231T120000Z", "FREQ=YEARLY;UNTIL=20231231T120000Z"},
+		{"FREQ=DAILY;X-ANCHOR=DUE", "FREQ=DAILY"},
+		{"FREQ=DAILY;X-ANCHOR=completion", "FREQ=DAILY;X-ANCHOR=COMPLETION"},
+		{"FREQ=DAILY;INTERVAL=1000", "FREQ=DAILY;INTERVAL=1000"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.rule, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+		}
+
+		again, err := Parse(r.String())
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", r.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(again, r) {
+			t.Errorf("Parse(%q) = %+v, want %+v", r.String(), again, r)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"RRULE:",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ",
+		"FREQ=",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=1001",
+		"FREQ=DAILY;INTERVAL=two",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-32",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20231231",
+		"FREQ=DAILY;UNTIL=2023-12-31",
+		"FREQ=DAILY;X-ANCHOR=START",
+		"FREQ=DAILY;BYHOUR=9",
+	}
+	for _, rule := range tests {
+		if r, err := Parse(rule); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", rule, r)
+		}
+	}
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		anchor string
+		want   string // empty when the series is over
+	}{
+		{"daily", "FREQ=DAILY;INTERVAL=3", "2023-01-30T09:00:00Z", "2023-02-02T09:00:00Z"},
+		{"in UTC", "FREQ=DAILY", "2023-03-25T23:30:00+02:00", "2023-03-26T21:30:00Z"},
+		{"weekly", "FREQ=WEEKLY", "2023-04-03T08:00:00Z", "2023-04-10T08:00:00Z"},
+		{"byday later in the week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2023-04-03T08:00:00Z", "2023-04-07T08:00:00Z"},
+		{"byday skips the off week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2023-04-07T08:00:00Z", "2023-04-17T08:00:00Z"},
+		{"byday counts from the week of the anchor", "FREQ=WEEKLY;INTERVAL=3;BYDAY=WE", "2023-04-09T08:00:00Z", "2023-04-26T08:00:00Z"},
+		{"monthly", "FREQ=MONTHLY", "2023-01-15T10:00:00Z", "2023-02-15T10:00:00Z"},
+		{"jan 31 skips february", "FREQ=MONTHLY", "2023-01-31T10:00:00Z", "2023-03-31T10:00:00Z"},
+		{"31st skips april", "FREQ=MONTHLY;BYMONTHDAY=31", "2023-03-31T10:00:00Z", "2023-05-31T10:00:00Z"},
+		{"last day of february", "FREQ=MONTHLY;BYMONTHDAY=-1", "2023-01-31T10:00:00Z", "2023-02-28T10:00:00Z"},
+		{"last day of leap february", "FREQ=MONTHLY;BYMONTHDAY=-1", "2024-01-31T10:00:00Z", "2024-02-29T10:00:00Z"},
+		{"next monthday", "FREQ=MONTHLY;BYMONTHDAY=1,15", "2023-01-15T10:00:00Z", "2023-02-01T10:00:00Z"},
+		{"monthday interval", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1", "2023-01-01T10:00:00Z", "2023-03-01T10:00:00Z"},
+		{"yearly", "FREQ=YEARLY", "2023-03-01T10:00:00Z", "2024-03-01T10:00:00Z"},
+		{"feb 29 waits for a leap year", "FREQ=YEARLY", "2024-02-29T10:00:00Z", "2028-02-29T10:00:00Z"},
+		{"feb 29 every 3 years", "FREQ=YEARLY;INTERVAL=3", "2024-02-29T10:00:00Z", "2036-02-29T10:00:00Z"},
+		{"count left", "FREQ=DAILY;COUNT=2", "2023-01-01T10:00:00Z", "2023-01-02T10:00:00Z"},
+		{"count ran out", "FREQ=DAILY;COUNT=1", "2023-01-01T10:00:00Z", ""},
+		{"until includes its day", "FREQ=DAILY;UNTIL=20230102", "2023-01-01T10:00:00Z", "2023-01-02T10:00:00Z"},
+		{"until reached", "FREQ=DAILY;UNTIL=20230102", "2023-01-02T10:00:00Z", ""},
+		{"until in the past", "FREQ=WEEKLY;UNTIL=20220101", "2023-01-01T10:00:00Z", ""},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("%s: Parse(%q) error = %v", tt.name, tt.rule, err)
+		}
+		next, rest, ok := r.Next(date(tt.anchor))
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: Next = %v, want the series to be over", tt.name, next)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: Next is over, want %s", tt.name, tt.want)
+			continue
+		}
+		if !next.Equal(date(tt.want)) || next.Location() != time.UTC {
+			t.Errorf("%s: Next = %v, want %s", tt.name, next, tt.want)
+		}
+		if rest == nil {
+			t.Errorf("%s: Next returned no rule for the following occurrence", tt.name)
+		}
+	}
+}
+
+func TestNextUsesUpCount(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=TU;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchor := date("2023-04-04T07:00:00Z")
+	var got []string
+	for {
+		next, rest, ok := r.Next(anchor)
+		if !ok {
+			break
+		}
+		got = append(got, next.Format("2006-01-02")+" "+rest.String())
+		anchor, r = next, rest
+	}
+	want := []string{
+		"2023-04-11 FREQ=WEEKLY;BYDAY=TU;COUNT=2",
+		"2023-04-18 FREQ=WEEKLY;BYDAY=TU;COUNT=1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("occurrences = %q, want %q", got, want)
+	}
+	if r.Count != 1 {
+		t.Errorf("last rule has COUNT=%d, want 1", r.Count)
+	}
+}
+
+func TestNextLeavesRuleUnchanged(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := r.String()
+	_, rest, _ := r.Next(date("2023-04-03T07:00:00Z"))
+	rest.ByDay[0] = time.Sunday
+	if got := r.String(); got != want {
+		t.Errorf("rule after Next = %q, want %q", got, want)
+	}
+}
@@ -1,13 +1,30 @@
 package activity
 
 import (
+	"context"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/search"
 )
 
 type ActivityRepository interface {
-	InsertActivity(activity entity.Activity) (*entity.Activity, error)
-	GetActivityByID(id int64) (activity *entity.Activity, err error)
-	GetAllActivity() (activities []*entity.Activity, err error)
-	UpdateActivity(activity entity.Activity) (*entity.Activity, error)
-	DeleteActivity(id int64) error
+	InsertActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error)
+	GetActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error)
+	GetActivityByIDs(ctx context.Context, ids []int64) (activities []*entity.Activity, err error)
+	// SearchActivity returns the activity groups matching q, only the one
+	// with id unless it is 0, past the cursor of pagination, best first, one
+	// more than its limit, and how many match in all.
+	SearchActivity(ctx context.Context, q search.Query, id int64, pagination model.Pagination) (hits []*entity.ActivityHit, total int64, err error)
+	GetAllActivity(ctx context.Context, filter model.ActivityFilter, pagination model.Pagination) (activities []*entity.Activity, page *model.PageInfo, err error)
+	UpdateActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error)
+	DeleteActivity(ctx context.Context, id int64, deletedAt time.Time) error
+	GetTrashedActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error)
+	// GetAllTrashedActivity returns the trashed activity groups past the
+	// cursor of pagination in trash order, one more than its limit, and how
+	// many are trashed in all.
+	GetAllTrashedActivity(ctx context.Context, pagination model.Pagination) (activities []*entity.Activity, total int64, err error)
+	RestoreActivity(ctx context.Context, id int64) (*entity.Activity, error)
+	PurgeActivity(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
 }
@@ -1,108 +1,251 @@
 package activity
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"encoding/json"
+	"time"
 
-	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/search"
 )
 
 type ActivityRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect query.Dialect
 }
 
-func NewActivityRepository(db *sql.DB) ActivityRepository {
-	return &ActivityRepositoryImpl{db: db}
+func NewActivityRepository(db *sql.DB, dialect query.Dialect) ActivityRepository {
+	return &ActivityRepositoryImpl{db: db, dialect: dialect}
 }
 
-func (repo *ActivityRepositoryImpl) InsertActivity(activity entity.Activity) (*entity.Activity, error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	query := "INSERT INTO activities(title, email) VALUES(?,?)"
+func (repo *ActivityRepositoryImpl) InsertActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error) {
+	workflow, err := marshalWorkflow(activity.Workflow)
+	if err != nil {
+		return nil, err
+	}
 
 	args := []interface{}{
 		activity.Title,
 		activity.Email,
+		workflow,
 	}
-	result, err := repo.db.ExecContext(ctx, query, args...)
+	executor := transaction.GetExecutor(ctx, repo.db)
+	id, err := query.InsertInWorkspace(ctx, executor, "activities", []string{"title", "email", "workflow"}, args,
+		&query.Quota{Column: "max_activity_groups", Err: model.ErrActivityQuotaExceeded})
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := result.LastInsertId()
+	// The group has no members yet, so it is read back regardless of the
+	// user of ctx.
+	b, err := query.Workspace(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	a, err := repo.GetActivityByID(id)
+	b.Where("activity_id=?", id)
+	rows, err := executor.QueryContext(ctx, "SELECT * FROM activities"+b.String(), b.Args()...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return a, nil
+	if rows.Next() {
+		return scanActivity(rows)
+	}
+	return nil, model.ErrActivityNotFound.WithMessage("Activity with ID %v Not Found", id)
 }
 
-func (repo *ActivityRepositoryImpl) GetActivityByID(id int64) (activity *entity.Activity, err error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	query := "SELECT * FROM activities WHERE activity_id=?"
-	rows, err := repo.db.QueryContext(ctx, query, id)
+func (repo *ActivityRepositoryImpl) GetActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_id=?", id)
+	b.Where("deleted_at IS NULL")
+	memberOf(ctx, &b)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM activities"+b.String(), b.Args()...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if rows.Next() {
-		var a entity.Activity
-		err = rows.Scan(&a.ID, &a.Title, &a.Email, &a.CreatedAt, &a.UpdatedAt)
+		return scanActivity(rows)
+	}
+	return nil, model.ErrActivityNotFound.WithMessage("Activity with ID %v Not Found", id)
+}
+
+// GetActivityByIDs returns the live activity groups among ids; unknown ids
+// are skipped.
+func (repo *ActivityRepositoryImpl) GetActivityByIDs(ctx context.Context, ids []int64) (activities []*entity.Activity, err error) {
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(ids) {
+		b, err := query.Workspace(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.WhereInIDs("activity_id", batch)
+		b.Where("deleted_at IS NULL")
+		memberOf(ctx, &b)
+		rows, err := executor.QueryContext(ctx, "SELECT * FROM activities"+b.String(), b.Args()...)
 		if err != nil {
 			return nil, err
 		}
-		return &a, nil
+		for rows.Next() {
+			a, err := scanActivity(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			activities = append(activities, a)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
 	}
-	return nil, fmt.Errorf("Activity with ID %v Not Found", id)
+	return activities, nil
 }
 
-func (repo *ActivityRepositoryImpl) GetAllActivity() (activities []*entity.Activity, err error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
+func (repo *ActivityRepositoryImpl) SearchActivity(ctx context.Context, q search.Query, id int64, pagination model.Pagination) (hits []*entity.ActivityHit, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.SearchSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	b.Where("deleted_at IS NULL")
+	memberOf(ctx, &b)
+	if id != 0 {
+		b.Where("activity_id=?", id)
+	}
+	join, joinArgs := repo.dialect.MatchTitle("activities", "activity_id", q.Terms())
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM activities"+join+b.String(), append(joinArgs, b.Args()...)...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	query := "SELECT * FROM activities"
-	rows, err := repo.db.QueryContext(ctx, query)
+	if condition, args := p.Keyset("fts.score", "activities."+query.MixedActivityID); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	args := append(append(joinArgs, b.Args()...), limitArgs...)
+	rows, err := executor.QueryContext(ctx, "SELECT activities.*, fts.score FROM activities"+join+b.String()+p.OrderBy("fts.score", "activities."+query.MixedActivityID)+limit, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var a entity.Activity
-		err = rows.Scan(&a.ID, &a.Title, &a.Email, &a.CreatedAt, &a.UpdatedAt)
+		var hit entity.ActivityHit
+		hit.Activity, err = scanActivity(rows, &hit.Score)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		activities = append(activities, &a)
+		hits = append(hits, &hit)
 	}
-	return activities, nil
+	return hits, total, rows.Err()
 }
 
-func (repo *ActivityRepositoryImpl) UpdateActivity(activity entity.Activity) (*entity.Activity, error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
+func (repo *ActivityRepositoryImpl) GetAllActivity(ctx context.Context, filter model.ActivityFilter, pagination model.Pagination) (activities []*entity.Activity, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortExpr, ok := activitySortColumns[p.Sort]
+	if !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
 
-	query := "UPDATE activities SET title=? WHERE activity_id=?"
-	args := []interface{}{
-		activity.Title,
-		activity.ID,
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.Where("deleted_at IS NULL")
+	memberOf(ctx, &b)
+	if filter.Title != "" {
+		b.Where("title LIKE ? ESCAPE '"+query.LikeEscape+"'", query.Contains(filter.Title))
+	}
+	if filter.Email != "" {
+		b.Where("email=?", filter.Email)
+	}
+	if filter.CreatedAfter != nil {
+		b.Where("created_at>=?", query.FormatTime(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		b.Where("created_at<?", query.FormatTime(*filter.CreatedBefore))
 	}
-	_, err := repo.db.ExecContext(ctx, query, args...)
+	if filter.UpdatedAfter != nil {
+		b.Where("updated_at>=?", query.FormatTime(*filter.UpdatedAfter))
+	}
+	if filter.UpdatedBefore != nil {
+		b.Where("updated_at<?", query.FormatTime(*filter.UpdatedBefore))
+	}
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+
+	var total int64
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM activities"+b.String(), b.Args()...).Scan(&total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if condition, args := p.Keyset(sortExpr, "activity_id"); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	rows, err := executor.QueryContext(ctx, "SELECT * FROM activities"+b.String()+p.OrderBy(sortExpr, "activity_id")+limit, append(b.Args(), limitArgs...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanActivity(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		activities = append(activities, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	activities, page = query.Result(p, activities, total, activitySortKey(p.Sort))
+	return activities, page, nil
+}
+
+func (repo *ActivityRepositoryImpl) UpdateActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error) {
+	workflow, err := marshalWorkflow(activity.Workflow)
 	if err != nil {
 		return nil, err
 	}
 
-	a, err := repo.GetActivityByID(activity.ID)
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_id=?", activity.ID)
+	b.Where("version=?", activity.Version)
+	b.Where("deleted_at IS NULL")
+	args := append([]interface{}{activity.Title, workflow}, b.Args()...)
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE activities SET title=?, workflow=?, version=version+1"+b.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return nil, model.ErrVersionMismatch
+	}
+
+	a, err := repo.GetActivityByID(ctx, activity.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -110,14 +253,148 @@ func (repo *ActivityRepositoryImpl) UpdateActivity(activity entity.Activity) (*e
 	return a, nil
 }
 
-func (repo *ActivityRepositoryImpl) DeleteActivity(id int64) error {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	query := "DELETE FROM activities WHERE activity_id=?"
-	_, err := repo.db.ExecContext(ctx, query, id)
+func (repo *ActivityRepositoryImpl) DeleteActivity(ctx context.Context, id int64, deletedAt time.Time) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	b.Where("activity_id=?", id)
+	b.Where("deleted_at IS NULL")
+	args := append([]interface{}{query.FormatTime(deletedAt)}, b.Args()...)
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE activities SET deleted_at=?, version=version+1"+b.String(), args...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (repo *ActivityRepositoryImpl) GetTrashedActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_id=?", id)
+	b.Where("deleted_at IS NOT NULL")
+	memberOf(ctx, &b)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM activities"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanActivity(rows)
+	}
+	return nil, model.ErrActivityNotInTrash.WithMessage("Activity with ID %v Not Found in Trash", id)
+}
+
+func (repo *ActivityRepositoryImpl) GetAllTrashedActivity(ctx context.Context, pagination model.Pagination) (activities []*entity.Activity, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.TrashSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	b.Where("deleted_at IS NOT NULL")
+	memberOf(ctx, &b)
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM activities"+b.String(), b.Args()...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if condition, args := p.Keyset(query.TrashSort, query.MixedActivityID); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	rows, err := executor.QueryContext(ctx, "SELECT * FROM activities"+b.String()+p.OrderBy(query.TrashSort, query.MixedActivityID)+limit, append(b.Args(), limitArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanActivity(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		activities = append(activities, a)
+	}
+	return activities, total, rows.Err()
+}
+
+func (repo *ActivityRepositoryImpl) RestoreActivity(ctx context.Context, id int64) (*entity.Activity, error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_id=?", id)
+	b.Where("deleted_at IS NOT NULL")
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE activities SET deleted_at=NULL, version=version+1"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return nil, model.ErrActivityNotInTrash.WithMessage("Activity with ID %v Not Found in Trash", id)
+	}
+
+	return repo.GetActivityByID(ctx, id)
+}
+
+// PurgeActivity permanently removes activity groups trashed before
+// deletedBefore. Todos still referencing them must be purged first.
+func (repo *ActivityRepositoryImpl) PurgeActivity(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	b.Where("deleted_at IS NOT NULL")
+	b.Where("deleted_at<?", query.FormatTime(deletedBefore))
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "DELETE FROM activities"+b.String(), b.Args()...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// memberOf limits b to the activity groups the user of ctx, if any, is a
+// member of.
+func memberOf(ctx context.Context, b *query.Builder) {
+	if userID, ok := model.UserFromContext(ctx); ok {
+		b.Where("activity_id IN (SELECT activity_id FROM activity_members WHERE user_id=?)", userID)
+	}
+}
+
+// scanActivity scans a row of activities, followed by the columns in extra
+// if the query selects more.
+func scanActivity(rows *sql.Rows, extra ...interface{}) (*entity.Activity, error) {
+	var a entity.Activity
+	var workflow sql.NullString
+	dest := []interface{}{&a.ID, &a.Title, &a.Email, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt, &a.Version, &workflow, &a.WorkspaceID}
+	err := rows.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	if workflow.Valid {
+		if err = json.Unmarshal([]byte(workflow.String), &a.Workflow); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+// marshalWorkflow encodes a workflow for the workflow column, where NULL
+// stands for the default workflow.
+func marshalWorkflow(w *entity.Workflow) (interface{}, error) {
+	if w == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,322 @@
+package activity
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/search"
+)
+
+type ActivityRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewActivityMemoryRepository(db *infrastructure.MemoryDatabase) ActivityRepository {
+	return &ActivityRepositoryMemoryImpl{db: db}
+}
+
+func (repo *ActivityRepositoryMemoryImpl) InsertActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error) {
+	workspaceID, err := query.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	w, ok := repo.db.Workspaces[workspaceID]
+	if !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	if w.MaxActivityGroups != nil {
+		var count int64
+		for _, a := range repo.db.Activities {
+			if a.WorkspaceID == workspaceID {
+				count++
+			}
+		}
+		if count >= *w.MaxActivityGroups {
+			return nil, model.ErrActivityQuotaExceeded
+		}
+	}
+
+	now := repo.db.Now()
+	activity.ID = repo.db.NextID(activity.TableName())
+	activity.WorkspaceID = workspaceID
+	activity.CreatedAt = now
+	activity.UpdatedAt = now
+	activity.Version = 1
+	repo.db.Activities[activity.ID] = activity
+
+	return &activity, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) GetActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	a, ok := repo.db.Activities[id]
+	if !ok || a.DeletedAt != nil || !inWorkspace(a.WorkspaceID) || !repo.memberOf(ctx, a.ID) {
+		return nil, model.ErrActivityNotFound.WithMessage("Activity with ID %v Not Found", id)
+	}
+	return &a, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) GetActivityByIDs(ctx context.Context, ids []int64) (activities []*entity.Activity, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, id := range ids {
+		if a, ok := repo.db.Activities[id]; ok && a.DeletedAt == nil && inWorkspace(a.WorkspaceID) && repo.memberOf(ctx, a.ID) {
+			a := a
+			activities = append(activities, &a)
+		}
+	}
+	return activities, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) GetAllActivity(ctx context.Context, filter model.ActivityFilter, pagination model.Pagination) (activities []*entity.Activity, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := activitySortColumns[p.Sort]; !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, a := range repo.db.Activities {
+		if a.DeletedAt != nil || !inWorkspace(a.WorkspaceID) || !repo.memberOf(ctx, a.ID) || !matchActivity(a, filter) {
+			continue
+		}
+		a := a
+		activities = append(activities, &a)
+	}
+
+	total := int64(len(activities))
+	key := activitySortKey(p.Sort)
+	activities, page = query.Result(p, query.Apply(p, activities, key), total, key)
+	return activities, page, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) SearchActivity(ctx context.Context, q search.Query, id int64, pagination model.Pagination) (hits []*entity.ActivityHit, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.SearchSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	var activities []*entity.Activity
+	var titles []string
+	for _, a := range repo.db.Activities {
+		if a.DeletedAt != nil || !inWorkspace(a.WorkspaceID) || !repo.memberOf(ctx, a.ID) || (id != 0 && a.ID != id) {
+			continue
+		}
+		a := a
+		activities = append(activities, &a)
+		titles = append(titles, a.Title)
+	}
+
+	for _, r := range search.Rank(q, titles) {
+		hits = append(hits, &entity.ActivityHit{Activity: activities[r.Index], Score: int64(math.Round(r.Score * search.ScoreScale))})
+	}
+	total = int64(len(hits))
+	return query.Apply(p, hits, func(hit *entity.ActivityHit) (interface{}, int64) {
+		return hit.Score, query.MixedActivityKey(hit.Activity.ID)
+	}), total, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) UpdateActivity(ctx context.Context, activity entity.Activity) (*entity.Activity, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	a, ok := repo.db.Activities[activity.ID]
+	if !ok || a.DeletedAt != nil || !inWorkspace(a.WorkspaceID) {
+		return nil, model.ErrActivityNotFound.WithMessage("Activity with ID %v Not Found", activity.ID)
+	}
+	if a.Version != activity.Version {
+		return nil, model.ErrVersionMismatch
+	}
+	a.Title = activity.Title
+	a.Workflow = activity.Workflow
+	a.UpdatedAt = repo.db.Now()
+	a.Version++
+	repo.db.Activities[a.ID] = a
+
+	return &a, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) DeleteActivity(ctx context.Context, id int64, deletedAt time.Time) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	a, ok := repo.db.Activities[id]
+	if !ok || a.DeletedAt != nil || !inWorkspace(a.WorkspaceID) {
+		return nil
+	}
+	for _, t := range repo.db.Todos {
+		if t.ActivityGroupID == id && t.DeletedAt == nil {
+			return model.ErrActivityGroupNotEmpty
+		}
+	}
+	a.DeletedAt = &deletedAt
+	a.Version++
+	repo.db.Activities[id] = a
+	return nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) GetTrashedActivityByID(ctx context.Context, id int64) (activity *entity.Activity, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	a, ok := repo.db.Activities[id]
+	if !ok || a.DeletedAt == nil || !inWorkspace(a.WorkspaceID) || !repo.memberOf(ctx, a.ID) {
+		return nil, model.ErrActivityNotInTrash.WithMessage("Activity with ID %v Not Found in Trash", id)
+	}
+	return &a, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) GetAllTrashedActivity(ctx context.Context, pagination model.Pagination) (activities []*entity.Activity, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.TrashSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, a := range repo.db.Activities {
+		if a.DeletedAt == nil || !inWorkspace(a.WorkspaceID) || !repo.memberOf(ctx, a.ID) {
+			continue
+		}
+		a := a
+		activities = append(activities, &a)
+	}
+	total = int64(len(activities))
+	return query.Apply(p, activities, func(a *entity.Activity) (interface{}, int64) {
+		return query.TrashActivityKey(a.ID, *a.DeletedAt)
+	}), total, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) RestoreActivity(ctx context.Context, id int64) (*entity.Activity, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	a, ok := repo.db.Activities[id]
+	if !ok || a.DeletedAt == nil || !inWorkspace(a.WorkspaceID) {
+		return nil, model.ErrActivityNotInTrash.WithMessage("Activity with ID %v Not Found in Trash", id)
+	}
+	a.DeletedAt = nil
+	a.Version++
+	repo.db.Activities[id] = a
+	return &a, nil
+}
+
+func (repo *ActivityRepositoryMemoryImpl) PurgeActivity(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, a := range repo.db.Activities {
+		if a.DeletedAt != nil && a.DeletedAt.Before(deletedBefore) && inWorkspace(a.WorkspaceID) {
+			delete(repo.db.Activities, id)
+			for key := range repo.db.ActivityMembers {
+				if key.ActivityID == id {
+					delete(repo.db.ActivityMembers, key)
+				}
+			}
+			for feedID, f := range repo.db.CalendarFeeds {
+				if f.ActivityGroupID != nil && *f.ActivityGroupID == id {
+					delete(repo.db.CalendarFeeds, feedID)
+				}
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// memberOf reports whether the user of ctx, if any, is a member of the
+// activity group id.
+func (repo *ActivityRepositoryMemoryImpl) memberOf(ctx context.Context, id int64) bool {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return true
+	}
+	_, ok = repo.db.ActivityMembers[entity.MemberKey{ActivityID: id, UserID: userID}]
+	return ok
+}
+
+func matchActivity(a entity.Activity, filter model.ActivityFilter) bool {
+	switch {
+	case filter.Title != "" && !strings.Contains(strings.ToLower(a.Title), strings.ToLower(filter.Title)):
+		return false
+	case filter.Email != "" && a.Email != filter.Email:
+		return false
+	case filter.CreatedAfter != nil && a.CreatedAt.Before(*filter.CreatedAfter):
+		return false
+	case filter.CreatedBefore != nil && !a.CreatedAt.Before(*filter.CreatedBefore):
+		return false
+	case filter.UpdatedAfter != nil && a.UpdatedAt.Before(*filter.UpdatedAfter):
+		return false
+	case filter.UpdatedBefore != nil && !a.UpdatedAt.Before(*filter.UpdatedBefore):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package activity
+
+import (
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+// activitySortColumns maps the sort keys accepted by GetAllActivity to SQL
+// expressions.
+var activitySortColumns = map[string]string{
+	"id":         "activity_id",
+	"title":      "title",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// activitySortKey returns the value of a that activitySortColumns[sort]
+// evaluates to.
+func activitySortKey(sort string) func(a *entity.Activity) (interface{}, int64) {
+	return func(a *entity.Activity) (interface{}, int64) {
+		switch sort {
+		case "title":
+			return a.Title, a.ID
+		case "created_at":
+			return query.FormatTime(a.CreatedAt), a.ID
+		case "updated_at":
+			return query.FormatTime(a.UpdatedAt), a.ID
+		default:
+			return a.ID, a.ID
+		}
+	}
+}
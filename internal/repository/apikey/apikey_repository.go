@@ -0,0 +1,16 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type APIKeyRepository interface {
+	InsertAPIKey(ctx context.Context, key entity.APIKey) (*entity.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (key *entity.APIKey, err error)
+	GetAPIKeyByUserID(ctx context.Context, userID int64) (keys []*entity.APIKey, err error)
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+	DeleteAPIKey(ctx context.Context, userID, id int64) error
+}
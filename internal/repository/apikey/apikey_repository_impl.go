@@ -0,0 +1,95 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+const apiKeyColumns = "api_key_id, user_id, workspace_id, name, prefix, key_hash, last_used_at, created_at"
+
+type APIKeyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+func (repo *APIKeyRepositoryImpl) InsertAPIKey(ctx context.Context, key entity.APIKey) (*entity.APIKey, error) {
+	query := "INSERT INTO api_keys(user_id, workspace_id, name, prefix, key_hash) VALUES(?,?,?,?,?)"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, key.UserID, key.WorkspaceID, key.Name, key.Prefix, key.KeyHash)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetAPIKeyByHash(ctx, key.KeyHash)
+}
+
+func (repo *APIKeyRepositoryImpl) GetAPIKeyByHash(ctx context.Context, keyHash string) (key *entity.APIKey, err error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash=?"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanAPIKey(rows)
+	}
+	return nil, model.ErrAPIKeyNotFound
+}
+
+func (repo *APIKeyRepositoryImpl) GetAPIKeyByUserID(ctx context.Context, userID int64) (keys []*entity.APIKey, err error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id=? ORDER BY api_key_id"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// TouchAPIKey records when a key was last used.
+func (repo *APIKeyRepositoryImpl) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	args := []interface{}{
+		query.FormatTime(usedAt),
+		id,
+	}
+	query := "UPDATE api_keys SET last_used_at=? WHERE api_key_id=?"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (repo *APIKeyRepositoryImpl) DeleteAPIKey(ctx context.Context, userID, id int64) error {
+	query := "DELETE FROM api_keys WHERE api_key_id=? AND user_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrAPIKeyNotFound.WithMessage("API key with ID %v Not Found", id)
+	}
+	return nil
+}
+
+func scanAPIKey(rows *sql.Rows) (*entity.APIKey, error) {
+	var k entity.APIKey
+	err := rows.Scan(&k.ID, &k.UserID, &k.WorkspaceID, &k.Name, &k.Prefix, &k.KeyHash, &k.LastUsedAt, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
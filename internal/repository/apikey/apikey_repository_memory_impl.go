@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type APIKeyRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewAPIKeyMemoryRepository(db *infrastructure.MemoryDatabase) APIKeyRepository {
+	return &APIKeyRepositoryMemoryImpl{db: db}
+}
+
+func (repo *APIKeyRepositoryMemoryImpl) InsertAPIKey(ctx context.Context, key entity.APIKey) (*entity.APIKey, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	key.ID = repo.db.NextID(key.TableName())
+	key.CreatedAt = repo.db.Now()
+	repo.db.APIKeys[key.ID] = key
+
+	return &key, nil
+}
+
+func (repo *APIKeyRepositoryMemoryImpl) GetAPIKeyByHash(ctx context.Context, keyHash string) (key *entity.APIKey, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, k := range repo.db.APIKeys {
+		if k.KeyHash == keyHash {
+			return &k, nil
+		}
+	}
+	return nil, model.ErrAPIKeyNotFound
+}
+
+func (repo *APIKeyRepositoryMemoryImpl) GetAPIKeyByUserID(ctx context.Context, userID int64) (keys []*entity.APIKey, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, k := range repo.db.APIKeys {
+		if k.UserID == userID {
+			k := k
+			keys = append(keys, &k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (repo *APIKeyRepositoryMemoryImpl) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if k, ok := repo.db.APIKeys[id]; ok {
+		usedAt = usedAt.UTC().Truncate(time.Second)
+		k.LastUsedAt = &usedAt
+		repo.db.APIKeys[id] = k
+	}
+	return nil
+}
+
+func (repo *APIKeyRepositoryMemoryImpl) DeleteAPIKey(ctx context.Context, userID, id int64) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	k, ok := repo.db.APIKeys[id]
+	if !ok || k.UserID != userID {
+		return model.ErrAPIKeyNotFound.WithMessage("API key with ID %v Not Found", id)
+	}
+	delete(repo.db.APIKeys, id)
+	return nil
+}
@@ -0,0 +1,13 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type CalendarRepository interface {
+	InsertCalendarFeed(ctx context.Context, feed entity.CalendarFeed) (*entity.CalendarFeed, error)
+	GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (feed *entity.CalendarFeed, err error)
+	DeleteCalendarFeed(ctx context.Context, activityGroupID *int64) error
+}
@@ -0,0 +1,83 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type CalendarRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCalendarRepository(db *sql.DB) CalendarRepository {
+	return &CalendarRepositoryImpl{db: db}
+}
+
+func (repo *CalendarRepositoryImpl) InsertCalendarFeed(ctx context.Context, feed entity.CalendarFeed) (*entity.CalendarFeed, error) {
+	args := []interface{}{
+		feed.ActivityGroupID,
+		feed.UserID,
+		feed.TokenHash,
+	}
+	_, err := query.InsertInWorkspace(ctx, transaction.GetExecutor(ctx, repo.db), "calendar_feeds", []string{"activity_group_id", "user_id", "token_hash"}, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCalendarFeedByTokenHash(ctx, feed.TokenHash)
+}
+
+// GetCalendarFeedByTokenHash looks a feed up by its token. Feed requests
+// only learn their workspace from the feed, so they look it up with a
+// context seeing every workspace.
+func (repo *CalendarRepositoryImpl) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (feed *entity.CalendarFeed, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("token_hash=?", tokenHash)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT feed_id, activity_group_id, user_id, token_hash, created_at, workspace_id FROM calendar_feeds"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var f entity.CalendarFeed
+		if err = rows.Scan(&f.ID, &f.ActivityGroupID, &f.UserID, &f.TokenHash, &f.CreatedAt, &f.WorkspaceID); err != nil {
+			return nil, err
+		}
+		return &f, nil
+	}
+	return nil, model.ErrCalendarFeedNotFound
+}
+
+// DeleteCalendarFeed removes the feed of an activity group, or the feed of
+// all groups when activityGroupID is nil. Only feeds of the user of ctx are
+// considered.
+func (repo *CalendarRepositoryImpl) DeleteCalendarFeed(ctx context.Context, activityGroupID *int64) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	if activityGroupID != nil {
+		b.Where("activity_group_id=?", *activityGroupID)
+	} else {
+		b.Where("activity_group_id IS NULL")
+	}
+	if userID, ok := model.UserFromContext(ctx); ok {
+		b.Where("user_id=?", userID)
+	}
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "DELETE FROM calendar_feeds"+b.String(), b.Args()...)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrCalendarFeedNotFound
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+type CalendarRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewCalendarMemoryRepository(db *infrastructure.MemoryDatabase) CalendarRepository {
+	return &CalendarRepositoryMemoryImpl{db: db}
+}
+
+func (repo *CalendarRepositoryMemoryImpl) InsertCalendarFeed(ctx context.Context, feed entity.CalendarFeed) (*entity.CalendarFeed, error) {
+	workspaceID, err := query.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if _, ok := repo.db.Workspaces[workspaceID]; !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	feed.WorkspaceID = workspaceID
+	feed.ID = repo.db.NextID(feed.TableName())
+	feed.CreatedAt = repo.db.Now()
+	repo.db.CalendarFeeds[feed.ID] = feed
+
+	return &feed, nil
+}
+
+func (repo *CalendarRepositoryMemoryImpl) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (feed *entity.CalendarFeed, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, f := range repo.db.CalendarFeeds {
+		if f.TokenHash == tokenHash && inWorkspace(f.WorkspaceID) {
+			return &f, nil
+		}
+	}
+	return nil, model.ErrCalendarFeedNotFound
+}
+
+func (repo *CalendarRepositoryMemoryImpl) DeleteCalendarFeed(ctx context.Context, activityGroupID *int64) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	userID, scoped := model.UserFromContext(ctx)
+	deleted := false
+	for id, f := range repo.db.CalendarFeeds {
+		if !inWorkspace(f.WorkspaceID) {
+			continue
+		}
+		if scoped && (f.UserID == nil || *f.UserID != userID) {
+			continue
+		}
+		if sameGroup(f.ActivityGroupID, activityGroupID) {
+			delete(repo.db.CalendarFeeds, id)
+			deleted = true
+		}
+	}
+	if !deleted {
+		return model.ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+func sameGroup(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
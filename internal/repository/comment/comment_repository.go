@@ -0,0 +1,18 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type CommentRepository interface {
+	InsertComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error)
+	GetCommentByID(ctx context.Context, id int64) (comment *entity.TodoComment, err error)
+	GetCommentByTodoID(ctx context.Context, todoID int64, pagination model.Pagination) (comments []*entity.TodoComment, page *model.PageInfo, err error)
+	GetCommentByTodoIDs(ctx context.Context, todoIDs []int64) (comments map[int64][]*entity.TodoComment, err error)
+	CountCommentByTodoIDs(ctx context.Context, todoIDs []int64) (counts map[int64]int64, err error)
+	UpdateComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error)
+	DeleteComment(ctx context.Context, id int64) error
+}
@@ -0,0 +1,182 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+const commentColumns = "c.comment_id, c.todo_id, c.user_id, c.body, c.created_at, c.updated_at, u.email, u.name"
+
+type CommentRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &CommentRepositoryImpl{db: db}
+}
+
+func (repo *CommentRepositoryImpl) InsertComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error) {
+	query := "INSERT INTO todo_comments(todo_id, user_id, body) VALUES(?,?,?)"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, comment.TodoID, comment.UserID, comment.Body)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCommentByID(ctx, id)
+}
+
+func (repo *CommentRepositoryImpl) GetCommentByID(ctx context.Context, id int64) (comment *entity.TodoComment, err error) {
+	query := "SELECT " + commentColumns + " FROM todo_comments c JOIN users u ON u.user_id=c.user_id WHERE c.comment_id=?"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanComment(rows)
+	}
+	return nil, model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", id)
+}
+
+func (repo *CommentRepositoryImpl) GetCommentByTodoID(ctx context.Context, todoID int64, pagination model.Pagination) (comments []*entity.TodoComment, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortExpr, ok := commentSortColumns[p.Sort]
+	if !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
+
+	var b query.Builder
+	b.Where("c.todo_id=?", todoID)
+	executor := transaction.GetExecutor(ctx, repo.db)
+
+	var total int64
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM todo_comments c"+b.String(), b.Args()...).Scan(&total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if condition, args := p.Keyset(sortExpr, "c.comment_id"); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	rows, err := executor.QueryContext(ctx,
+		"SELECT "+commentColumns+" FROM todo_comments c JOIN users u ON u.user_id=c.user_id"+
+			b.String()+p.OrderBy(sortExpr, "c.comment_id")+limit, append(b.Args(), limitArgs...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		comments = append(comments, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	comments, page = query.Result(p, comments, total, commentSortKey(p.Sort))
+	return comments, page, nil
+}
+
+// GetCommentByTodoIDs returns the comments of the given todos oldest first,
+// keyed by todo id.
+func (repo *CommentRepositoryImpl) GetCommentByTodoIDs(ctx context.Context, todoIDs []int64) (comments map[int64][]*entity.TodoComment, err error) {
+	comments = make(map[int64][]*entity.TodoComment)
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(todoIDs) {
+		var b query.Builder
+		b.WhereInIDs("c.todo_id", batch)
+		rows, err := executor.QueryContext(ctx,
+			"SELECT "+commentColumns+" FROM todo_comments c JOIN users u ON u.user_id=c.user_id"+
+				b.String()+" ORDER BY c.comment_id", b.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			c, err := scanComment(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			comments[c.TodoID] = append(comments[c.TodoID], c)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return comments, nil
+}
+
+func (repo *CommentRepositoryImpl) CountCommentByTodoIDs(ctx context.Context, todoIDs []int64) (counts map[int64]int64, err error) {
+	counts = make(map[int64]int64)
+	if len(todoIDs) == 0 {
+		return counts, nil
+	}
+	var b query.Builder
+	b.WhereInIDs("todo_id", todoIDs)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+		"SELECT todo_id, COUNT(*) FROM todo_comments"+b.String()+" GROUP BY todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var todoID, count int64
+		if err = rows.Scan(&todoID, &count); err != nil {
+			return nil, err
+		}
+		counts[todoID] = count
+	}
+	return counts, rows.Err()
+}
+
+func (repo *CommentRepositoryImpl) UpdateComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error) {
+	query := "UPDATE todo_comments SET body=? WHERE comment_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, comment.Body, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", comment.ID)
+	}
+	return repo.GetCommentByID(ctx, comment.ID)
+}
+
+func (repo *CommentRepositoryImpl) DeleteComment(ctx context.Context, id int64) error {
+	query := "DELETE FROM todo_comments WHERE comment_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", id)
+	}
+	return nil
+}
+
+func scanComment(rows *sql.Rows) (*entity.TodoComment, error) {
+	var c entity.TodoComment
+	err := rows.Scan(&c.ID, &c.TodoID, &c.UserID, &c.Body, &c.CreatedAt, &c.UpdatedAt, &c.Email, &c.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
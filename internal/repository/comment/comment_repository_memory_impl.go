@@ -0,0 +1,137 @@
+package comment
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+type CommentRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewCommentMemoryRepository(db *infrastructure.MemoryDatabase) CommentRepository {
+	return &CommentRepositoryMemoryImpl{db: db}
+}
+
+func (repo *CommentRepositoryMemoryImpl) InsertComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	comment.ID = repo.db.NextID(comment.TableName())
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+	comment.Email, comment.Name = "", ""
+	repo.db.TodoComments[comment.ID] = comment
+	return repo.withAuthor(comment), nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) GetCommentByID(ctx context.Context, id int64) (comment *entity.TodoComment, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	c, ok := repo.db.TodoComments[id]
+	if !ok {
+		return nil, model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", id)
+	}
+	return repo.withAuthor(c), nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) GetCommentByTodoID(ctx context.Context, todoID int64, pagination model.Pagination) (comments []*entity.TodoComment, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := commentSortColumns[p.Sort]; !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, c := range repo.db.TodoComments {
+		if c.TodoID == todoID {
+			comments = append(comments, repo.withAuthor(c))
+		}
+	}
+
+	total := int64(len(comments))
+	key := commentSortKey(p.Sort)
+	comments, page = query.Result(p, query.Apply(p, comments, key), total, key)
+	return comments, page, nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) GetCommentByTodoIDs(ctx context.Context, todoIDs []int64) (comments map[int64][]*entity.TodoComment, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	wanted := make(map[int64]bool, len(todoIDs))
+	for _, id := range todoIDs {
+		wanted[id] = true
+	}
+	comments = make(map[int64][]*entity.TodoComment)
+	for _, c := range repo.db.TodoComments {
+		if wanted[c.TodoID] {
+			comments[c.TodoID] = append(comments[c.TodoID], repo.withAuthor(c))
+		}
+	}
+	for _, cs := range comments {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
+	}
+	return comments, nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) CountCommentByTodoIDs(ctx context.Context, todoIDs []int64) (counts map[int64]int64, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	wanted := make(map[int64]bool, len(todoIDs))
+	for _, id := range todoIDs {
+		wanted[id] = true
+	}
+	counts = make(map[int64]int64)
+	for _, c := range repo.db.TodoComments {
+		if wanted[c.TodoID] {
+			counts[c.TodoID]++
+		}
+	}
+	return counts, nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) UpdateComment(ctx context.Context, comment entity.TodoComment) (*entity.TodoComment, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	c, ok := repo.db.TodoComments[comment.ID]
+	if !ok {
+		return nil, model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", comment.ID)
+	}
+	c.Body = comment.Body
+	c.UpdatedAt = repo.db.Now()
+	repo.db.TodoComments[c.ID] = c
+	return repo.withAuthor(c), nil
+}
+
+func (repo *CommentRepositoryMemoryImpl) DeleteComment(ctx context.Context, id int64) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if _, ok := repo.db.TodoComments[id]; !ok {
+		return model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", id)
+	}
+	delete(repo.db.TodoComments, id)
+	return nil
+}
+
+// withAuthor returns a copy of c with the author filled in. Callers must
+// hold a lock.
+func (repo *CommentRepositoryMemoryImpl) withAuthor(c entity.TodoComment) *entity.TodoComment {
+	u := repo.db.Users[c.UserID]
+	c.Email, c.Name = u.Email, u.Name
+	return &c
+}
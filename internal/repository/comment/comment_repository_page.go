@@ -0,0 +1,26 @@
+package comment
+
+import (
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+// commentSortColumns maps the sort keys accepted by GetCommentByTodoID to
+// SQL expressions.
+var commentSortColumns = map[string]string{
+	"id":         "c.comment_id",
+	"created_at": "c.created_at",
+}
+
+// commentSortKey returns the value of c that commentSortColumns[sort]
+// evaluates to.
+func commentSortKey(sort string) func(c *entity.TodoComment) (interface{}, int64) {
+	return func(c *entity.TodoComment) (interface{}, int64) {
+		switch sort {
+		case "created_at":
+			return query.FormatTime(c.CreatedAt), c.ID
+		default:
+			return c.ID, c.ID
+		}
+	}
+}
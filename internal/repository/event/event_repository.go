@@ -0,0 +1,15 @@
+package event
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type EventRepository interface {
+	InsertTodoEvent(ctx context.Context, event entity.TodoEvent) error
+	InsertTodoEvents(ctx context.Context, events []entity.TodoEvent) error
+	GetTodoEventByTodoID(ctx context.Context, todoID int64) (events []*entity.TodoEvent, err error)
+	InsertActivityEvent(ctx context.Context, event entity.ActivityEvent) error
+	GetActivityEventByActivityID(ctx context.Context, activityID int64) (events []*entity.ActivityEvent, err error)
+}
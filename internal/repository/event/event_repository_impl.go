@@ -0,0 +1,127 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type EventRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewEventRepository(db *sql.DB) EventRepository {
+	return &EventRepositoryImpl{
+		db: db,
+	}
+}
+
+func (repo *EventRepositoryImpl) InsertTodoEvent(ctx context.Context, event entity.TodoEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO todo_events(todo_id, action, changes, actor) VALUES(?,?,?,?)"
+	args := []interface{}{
+		event.TodoID,
+		event.Action,
+		string(changes),
+		event.Actor,
+	}
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// InsertTodoEvents records several events with one statement per batch.
+func (repo *EventRepositoryImpl) InsertTodoEvents(ctx context.Context, events []entity.TodoEvent) error {
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(events) {
+		args := make([]interface{}, 0, 4*len(batch))
+		for _, event := range batch {
+			changes, err := json.Marshal(event.Changes)
+			if err != nil {
+				return err
+			}
+			args = append(args, event.TodoID, event.Action, string(changes), event.Actor)
+		}
+		_, err := executor.ExecContext(ctx, "INSERT INTO todo_events(todo_id, action, changes, actor) VALUES"+query.Values(len(batch), 4), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *EventRepositoryImpl) GetTodoEventByTodoID(ctx context.Context, todoID int64) (events []*entity.TodoEvent, err error) {
+	query := "SELECT event_id, todo_id, action, changes, actor, created_at FROM todo_events WHERE todo_id=? ORDER BY event_id"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e entity.TodoEvent
+		var changes string
+		err = rows.Scan(&e.ID, &e.TodoID, &e.Action, &changes, &e.Actor, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+func (repo *EventRepositoryImpl) InsertActivityEvent(ctx context.Context, event entity.ActivityEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO activity_events(activity_id, action, changes, actor) VALUES(?,?,?,?)"
+	args := []interface{}{
+		event.ActivityID,
+		event.Action,
+		string(changes),
+		event.Actor,
+	}
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *EventRepositoryImpl) GetActivityEventByActivityID(ctx context.Context, activityID int64) (events []*entity.ActivityEvent, err error) {
+	query := "SELECT event_id, activity_id, action, changes, actor, created_at FROM activity_events WHERE activity_id=? ORDER BY event_id"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e entity.ActivityEvent
+		var changes string
+		err = rows.Scan(&e.ID, &e.ActivityID, &e.Action, &changes, &e.Actor, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,86 @@
+package event
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type EventRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewEventMemoryRepository(db *infrastructure.MemoryDatabase) EventRepository {
+	return &EventRepositoryMemoryImpl{
+		db: db,
+	}
+}
+
+func (repo *EventRepositoryMemoryImpl) InsertTodoEvent(ctx context.Context, event entity.TodoEvent) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	event.ID = repo.db.NextID(event.TableName())
+	event.CreatedAt = repo.db.Now()
+	repo.db.TodoEvents[event.ID] = event
+	return nil
+}
+
+func (repo *EventRepositoryMemoryImpl) InsertTodoEvents(ctx context.Context, events []entity.TodoEvent) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	for _, event := range events {
+		event.ID = repo.db.NextID(event.TableName())
+		event.CreatedAt = now
+		repo.db.TodoEvents[event.ID] = event
+	}
+	return nil
+}
+
+func (repo *EventRepositoryMemoryImpl) GetTodoEventByTodoID(ctx context.Context, todoID int64) (events []*entity.TodoEvent, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, e := range repo.db.TodoEvents {
+		if e.TodoID != todoID {
+			continue
+		}
+		e := e
+		events = append(events, &e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (repo *EventRepositoryMemoryImpl) InsertActivityEvent(ctx context.Context, event entity.ActivityEvent) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	event.ID = repo.db.NextID(event.TableName())
+	event.CreatedAt = repo.db.Now()
+	repo.db.ActivityEvents[event.ID] = event
+	return nil
+}
+
+func (repo *EventRepositoryMemoryImpl) GetActivityEventByActivityID(ctx context.Context, activityID int64) (events []*entity.ActivityEvent, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, e := range repo.db.ActivityEvents {
+		if e.ActivityID != activityID {
+			continue
+		}
+		e := e
+		events = append(events, &e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
@@ -0,0 +1,15 @@
+package member
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type MemberRepository interface {
+	InsertMember(ctx context.Context, member entity.ActivityMember) (*entity.ActivityMember, error)
+	GetMember(ctx context.Context, activityID, userID int64) (member *entity.ActivityMember, err error)
+	GetMemberByActivityIDs(ctx context.Context, activityIDs []int64) (members map[int64][]*entity.ActivityMember, err error)
+	UpdateMemberRole(ctx context.Context, activityID, userID int64, role string) (*entity.ActivityMember, error)
+	DeleteMember(ctx context.Context, activityID, userID int64) error
+}
@@ -0,0 +1,104 @@
+package member
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+const memberColumns = "m.activity_id, m.user_id, m.role, m.created_at, m.updated_at, u.email, u.name"
+
+type MemberRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewMemberRepository(db *sql.DB) MemberRepository {
+	return &MemberRepositoryImpl{db: db}
+}
+
+func (repo *MemberRepositoryImpl) InsertMember(ctx context.Context, member entity.ActivityMember) (*entity.ActivityMember, error) {
+	query := "INSERT INTO activity_members(activity_id, user_id, role) VALUES(?,?,?)"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, member.ActivityID, member.UserID, member.Role)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetMember(ctx, member.ActivityID, member.UserID)
+}
+
+func (repo *MemberRepositoryImpl) GetMember(ctx context.Context, activityID, userID int64) (member *entity.ActivityMember, err error) {
+	query := "SELECT " + memberColumns + " FROM activity_members m JOIN users u ON u.user_id=m.user_id WHERE m.activity_id=? AND m.user_id=?"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, activityID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanMember(rows)
+	}
+	return nil, model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+}
+
+// GetMemberByActivityIDs returns the members of each activity group, the
+// owner first and the others in the order they joined.
+func (repo *MemberRepositoryImpl) GetMemberByActivityIDs(ctx context.Context, activityIDs []int64) (members map[int64][]*entity.ActivityMember, err error) {
+	members = make(map[int64][]*entity.ActivityMember)
+	if len(activityIDs) == 0 {
+		return members, nil
+	}
+	var b query.Builder
+	b.WhereInIDs("m.activity_id", activityIDs)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+		"SELECT "+memberColumns+" FROM activity_members m JOIN users u ON u.user_id=m.user_id"+
+			b.String()+" ORDER BY m.role='"+entity.MemberRoleOwner+"' DESC, m.created_at, m.user_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members[m.ActivityID] = append(members[m.ActivityID], m)
+	}
+	return members, rows.Err()
+}
+
+func (repo *MemberRepositoryImpl) UpdateMemberRole(ctx context.Context, activityID, userID int64, role string) (*entity.ActivityMember, error) {
+	query := "UPDATE activity_members SET role=? WHERE activity_id=? AND user_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, role, activityID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	return repo.GetMember(ctx, activityID, userID)
+}
+
+func (repo *MemberRepositoryImpl) DeleteMember(ctx context.Context, activityID, userID int64) error {
+	query := "DELETE FROM activity_members WHERE activity_id=? AND user_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, activityID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	return nil
+}
+
+func scanMember(rows *sql.Rows) (*entity.ActivityMember, error) {
+	var m entity.ActivityMember
+	err := rows.Scan(&m.ActivityID, &m.UserID, &m.Role, &m.CreatedAt, &m.UpdatedAt, &m.Email, &m.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,107 @@
+package member
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type MemberRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewMemberMemoryRepository(db *infrastructure.MemoryDatabase) MemberRepository {
+	return &MemberRepositoryMemoryImpl{db: db}
+}
+
+func (repo *MemberRepositoryMemoryImpl) InsertMember(ctx context.Context, member entity.ActivityMember) (*entity.ActivityMember, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+	member.Email, member.Name = "", ""
+	repo.db.ActivityMembers[member.Key()] = member
+
+	return repo.withUser(member), nil
+}
+
+func (repo *MemberRepositoryMemoryImpl) GetMember(ctx context.Context, activityID, userID int64) (member *entity.ActivityMember, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	m, ok := repo.db.ActivityMembers[entity.MemberKey{ActivityID: activityID, UserID: userID}]
+	if !ok {
+		return nil, model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	return repo.withUser(m), nil
+}
+
+func (repo *MemberRepositoryMemoryImpl) GetMemberByActivityIDs(ctx context.Context, activityIDs []int64) (members map[int64][]*entity.ActivityMember, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	wanted := make(map[int64]bool, len(activityIDs))
+	for _, id := range activityIDs {
+		wanted[id] = true
+	}
+	members = make(map[int64][]*entity.ActivityMember)
+	for key, m := range repo.db.ActivityMembers {
+		if wanted[key.ActivityID] {
+			members[key.ActivityID] = append(members[key.ActivityID], repo.withUser(m))
+		}
+	}
+	for _, ms := range members {
+		sort.Slice(ms, func(i, j int) bool {
+			a, b := ms[i], ms[j]
+			if (a.Role == entity.MemberRoleOwner) != (b.Role == entity.MemberRoleOwner) {
+				return a.Role == entity.MemberRoleOwner
+			}
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.UserID < b.UserID
+		})
+	}
+	return members, nil
+}
+
+func (repo *MemberRepositoryMemoryImpl) UpdateMemberRole(ctx context.Context, activityID, userID int64, role string) (*entity.ActivityMember, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	key := entity.MemberKey{ActivityID: activityID, UserID: userID}
+	m, ok := repo.db.ActivityMembers[key]
+	if !ok {
+		return nil, model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	m.Role = role
+	m.UpdatedAt = repo.db.Now()
+	repo.db.ActivityMembers[key] = m
+
+	return repo.withUser(m), nil
+}
+
+func (repo *MemberRepositoryMemoryImpl) DeleteMember(ctx context.Context, activityID, userID int64) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	key := entity.MemberKey{ActivityID: activityID, UserID: userID}
+	if _, ok := repo.db.ActivityMembers[key]; !ok {
+		return model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	delete(repo.db.ActivityMembers, key)
+	return nil
+}
+
+// withUser fills in the account details the SQL driver joins in. Callers
+// must hold a lock.
+func (repo *MemberRepositoryMemoryImpl) withUser(m entity.ActivityMember) *entity.ActivityMember {
+	u := repo.db.Users[m.UserID]
+	m.Email, m.Name = u.Email, u.Name
+	return &m
+}
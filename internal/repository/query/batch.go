@@ -0,0 +1,27 @@
+package query
+
+import "strings"
+
+// BatchSize bounds the rows a single multi-row statement touches, keeping
+// the number of placeholders well under the limits of both drivers.
+const BatchSize = 100
+
+// Batches splits s into consecutive slices of at most BatchSize elements.
+func Batches[T any](s []T) [][]T {
+	var batches [][]T
+	for len(s) > BatchSize {
+		batches = append(batches, s[:BatchSize])
+		s = s[BatchSize:]
+	}
+	if len(s) > 0 {
+		batches = append(batches, s)
+	}
+	return batches
+}
+
+// Values renders the placeholders of a multi-row INSERT, such as
+// "(?,?),(?,?)" for two rows of two columns.
+func Values(rows, columns int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?,", columns), ",") + ")"
+	return strings.TrimSuffix(strings.Repeat(row+",", rows), ",")
+}
@@ -0,0 +1,82 @@
+package query
+
+import (
+	"strings"
+	"time"
+)
+
+// Builder collects the conditions of a WHERE clause together with their
+// arguments, so filters never have to be spliced into SQL strings.
+type Builder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (b *Builder) Where(condition string, args ...interface{}) {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+}
+
+// WhereIn adds "column IN (...)" with one placeholder per value.
+func (b *Builder) WhereIn(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+	b.Where(column+" IN ("+placeholders+")", args...)
+}
+
+// WhereInIDs is WhereIn for integer keys.
+func (b *Builder) WhereInIDs(column string, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	b.Where(column+" IN ("+placeholders+")", args...)
+}
+
+// String renders the WHERE clause, or an empty string without conditions.
+func (b *Builder) String() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+func (b *Builder) Args() []interface{} {
+	return b.args
+}
+
+// LikeEscape is the escape character used with Contains. A backslash would
+// need different quoting in MySQL and SQLite.
+const LikeEscape = "!"
+
+var likeReplacer = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Contains returns a LIKE pattern matching s anywhere, to be used as
+// "column LIKE ? ESCAPE '!'".
+func Contains(s string) string {
+	return "%" + likeReplacer.Replace(s) + "%"
+}
+
+// FormatTime renders t the way DATETIME columns are stored, so comparisons
+// behave the same on MySQL and SQLite.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// FormatNullTime is FormatTime for nullable columns; nil becomes NULL.
+func FormatNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return FormatTime(*t)
+}
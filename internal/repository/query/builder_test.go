@@ -0,0 +1,67 @@
+package query
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"milk", "%milk%"},
+		{"100%", "%100!%%"},
+		{"snake_case", "%snake!_case%"},
+		{"wow!", "%wow!!%"},
+		{"!%_", "%!!!%!_%"},
+		{`back\slash`, `%back\slash%`},
+	}
+	for _, tt := range tests {
+		if got := Contains(tt.s); got != tt.want {
+			t.Errorf("Contains(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestContainsMatchesLiterally runs the patterns through SQLite, so %, _ and
+// the escape character itself only ever match themselves.
+func TestContainsMatchesLiterally(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		title, s string
+		want     bool
+	}{
+		{"Buy milk", "milk", true},
+		{"Buy MILK", "milk", true},
+		{"100% juice", "100%", true},
+		{"1000 juice", "100%", false},
+		{"100 juice", "%", false},
+		{"snake_case", "snake_case", true},
+		{"snakeXcase", "snake_case", false},
+		{"snake_case", "_", true},
+		{"no underscore", "_", false},
+		{"Wow! Deals", "wow!", true},
+		{"Wow Deals", "wow!", false},
+		{"a!%b", "!%", true},
+		{"a%b", "!%", false},
+		{`back\slash`, `\`, true},
+	}
+	for _, tt := range tests {
+		var got bool
+		err := db.QueryRow("SELECT ? LIKE ? ESCAPE '"+LikeEscape+"'", tt.title, Contains(tt.s)).Scan(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%q LIKE Contains(%q) = %v, want %v", tt.title, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vnnyx/golang-todo-api/internal/search"
+)
+
+// Dialect tells apart the SQL drivers where their SQL differs, as it does for
+// full-text search. Its values are those of STORAGE_DRIVER.
+type Dialect string
+
+const (
+	MySQL  Dialect = "mysql"
+	SQLite Dialect = "sqlite"
+)
+
+// MatchTitle returns a join of table to the rows whose title holds every one
+// of terms, as a word or the start of one. The join is aliased fts and has
+// their relevance in units of search.ScoreScale, higher is better, as
+// fts.score. MySQL matches through the FULLTEXT index on title, SQLite
+// through the FTS5 table named after table with an _fts suffix, which
+// triggers keep in sync.
+//
+// Terms are made of letters and digits only, as search.Query has them, so
+// none can carry an operator of either syntax.
+func (d Dialect) MatchTitle(table, idColumn string, terms []string) (string, []interface{}) {
+	scale := strconv.Itoa(search.ScoreScale)
+	if d == SQLite {
+		quoted := make([]string, 0, len(terms))
+		for _, term := range terms {
+			quoted = append(quoted, `"`+term+`"*`)
+		}
+		fts := table + "_fts"
+		join := " JOIN (SELECT rowid AS match_id, CAST(ROUND(-bm25(" + fts + ")*" + scale + ") AS INTEGER) AS score" +
+			" FROM " + fts + " WHERE " + fts + " MATCH ?) fts ON fts.match_id=" + table + "." + idColumn
+		return join, []interface{}{strings.Join(quoted, " ")}
+	}
+
+	required := make([]string, 0, len(terms))
+	for _, term := range terms {
+		required = append(required, "+"+term+"*")
+	}
+	against := strings.Join(required, " ")
+	join := " JOIN (SELECT " + idColumn + " AS match_id, CAST(ROUND(MATCH(title) AGAINST(? IN BOOLEAN MODE)*" + scale + ") AS SIGNED) AS score" +
+		" FROM " + table + " WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)) fts ON fts.match_id=" + table + "." + idColumn
+	return join, []interface{}{against, against}
+}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// TestMatchTitleSQLite runs MatchTitle against the FTS5 tables of the SQLite
+// migration, whose triggers have to follow every write to the titles.
+func TestMatchTitleSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migration, err := os.ReadFile("../../../migrations/sqlite/20230417090000_add_title_fulltext.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"CREATE TABLE todos(todo_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, title VARCHAR(255) NOT NULL)",
+		"CREATE TABLE activities(activity_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, title VARCHAR(255) NOT NULL)",
+		"INSERT INTO todos(title) VALUES ('Buy milk'), ('Buy oat milk'), ('Café au lait')",
+		string(migration),
+		"INSERT INTO todos(title) VALUES ('Milkshake'), ('Walk the dog')",
+		"UPDATE todos SET title='Buy bread' WHERE todo_id=1",
+		"DELETE FROM todos WHERE todo_id=2",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		terms []string
+		want  []int64
+	}{
+		{[]string{"milk"}, []int64{4}},
+		{[]string{"bread"}, []int64{1}},
+		{[]string{"buy"}, []int64{1}},
+		{[]string{"cafe"}, []int64{3}},
+		{[]string{"wa"}, []int64{5}},
+		{[]string{"walk", "dog"}, []int64{5}},
+		{[]string{"walk", "cat"}, nil},
+		{[]string{"oat"}, nil},
+	}
+	for _, tt := range tests {
+		join, args := SQLite.MatchTitle("todos", "todo_id", tt.terms)
+		rows, err := db.Query("SELECT todos.todo_id, fts.score FROM todos"+join+" ORDER BY todos.todo_id", args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for rows.Next() {
+			var id, score int64
+			if err := rows.Scan(&id, &score); err != nil {
+				t.Fatal(err)
+			}
+			if score <= 0 {
+				t.Errorf("MatchTitle(%q) score of %d = %d, want more than 0", tt.terms, id, score)
+			}
+			got = append(got, id)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MatchTitle(%q) = %v, want %v", tt.terms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+// Listings mixing activity groups and todos, such as the trash and search
+// hits, number the two kinds apart: odd ids for activity groups and even
+// ones for todos, so that a cursor taken from either kind seeks through both
+// tables.
+const (
+	MixedActivityID = "activity_id*2+1"
+	MixedTodoID     = "todo_id*2"
+)
+
+// Sorts of the mixed listings.
+const (
+	// TrashSort lists the most recently deleted first.
+	TrashSort = "deleted_at"
+	// SearchSort lists the best matches first.
+	SearchSort = "score"
+)
+
+func MixedActivityKey(id int64) int64 {
+	return id*2 + 1
+}
+
+func MixedTodoKey(id int64) int64 {
+	return id * 2
+}
+
+// NewMixedPage is NewPage for a mixed listing, which is always sorted by
+// sort in descending order. Cursors of other listings are refused.
+func NewMixedPage(p model.Pagination, sort string) (*Page, error) {
+	p.Sort, p.Desc = sort, true
+	page, err := NewPage(p)
+	if err != nil {
+		return nil, err
+	}
+	if page.Sort != sort || !page.Desc {
+		return nil, model.ErrInvalidCursor
+	}
+	return page, nil
+}
+
+// TrashActivityKey returns the sort key of a trashed activity group.
+func TrashActivityKey(id int64, deletedAt time.Time) (interface{}, int64) {
+	return FormatTime(deletedAt), MixedActivityKey(id)
+}
+
+// TrashTodoKey returns the sort key of a trashed todo.
+func TrashTodoKey(id int64, deletedAt time.Time) (interface{}, int64) {
+	return FormatTime(deletedAt), MixedTodoKey(id)
+}
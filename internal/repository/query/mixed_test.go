@@ -0,0 +1,65 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+// trashRow is a trashed activity group or todo; both kinds share ids.
+type trashRow struct {
+	group     bool
+	id        int64
+	deletedAt time.Time
+}
+
+func (r trashRow) key() (interface{}, int64) {
+	if r.group {
+		return TrashActivityKey(r.id, r.deletedAt)
+	}
+	return TrashTodoKey(r.id, r.deletedAt)
+}
+
+func (r trashRow) String() string {
+	if r.group {
+		return fmt.Sprintf("group %d", r.id)
+	}
+	return fmt.Sprintf("todo %d", r.id)
+}
+
+func TestTrashPagesThroughBothKinds(t *testing.T) {
+	at := time.Date(2023, 4, 10, 9, 0, 0, 0, time.UTC)
+	// Group 1 went to the trash together with its todos 1 and 2.
+	groups := []trashRow{{true, 1, at}, {true, 2, at.Add(-time.Hour)}}
+	todos := []trashRow{{false, 1, at}, {false, 2, at}, {false, 3, at.Add(time.Hour)}, {false, 4, at.Add(-time.Hour)}}
+	want := []string{"todo 3", "todo 2", "group 1", "todo 1", "todo 4", "group 2"}
+
+	var got []string
+	cursor := ""
+	for i := 0; i < len(want); i++ {
+		p, err := NewMixedPage(model.Pagination{Limit: 2, Cursor: cursor}, TrashSort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Each kind is paged on its own, then the pages are merged.
+		rows := append(Apply(p, append([]trashRow(nil), groups...), trashRow.key), Apply(p, append([]trashRow(nil), todos...), trashRow.key)...)
+		rows, info := Result(p, Apply(p, rows, trashRow.key), int64(len(groups)+len(todos)), trashRow.key)
+		for _, r := range rows {
+			got = append(got, r.String())
+		}
+		if cursor = info.Next; cursor == "" {
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trash = %v, want %v", got, want)
+	}
+
+	other := EncodeCursor(Cursor{Sort: "title", Value: "b", ID: 2})
+	if _, err := NewMixedPage(model.Pagination{Cursor: other}, TrashSort); err != model.ErrInvalidCursor {
+		t.Errorf("NewMixedPage with the cursor of another listing error = %v, want ErrInvalidCursor", err)
+	}
+}
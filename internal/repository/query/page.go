@@ -0,0 +1,230 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+// DefaultLimit applies to cursor and offset requests that omit a limit.
+const DefaultLimit = 20
+
+// Cursor points just after (or, for Prev, just before) a row in a listing
+// sorted by Sort. Value is the row's sort value and ID breaks ties.
+type Cursor struct {
+	Sort  string      `json:"s"`
+	Desc  bool        `json:"d,omitempty"`
+	Value interface{} `json:"v"`
+	ID    int64       `json:"i"`
+	Prev  bool        `json:"p,omitempty"`
+}
+
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+
+	var c Cursor
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err = decoder.Decode(&c); err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+
+	switch v := c.Value.(type) {
+	case string:
+	case json.Number:
+		if c.Value, err = v.Int64(); err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+	default:
+		return nil, model.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Page is a Pagination resolved against its cursor.
+type Page struct {
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+	cursor *Cursor
+}
+
+func NewPage(p model.Pagination) (*Page, error) {
+	page := &Page{
+		Sort:   p.Sort,
+		Desc:   p.Desc,
+		Limit:  p.Limit,
+		Offset: p.Offset,
+	}
+	if p.Cursor != "" {
+		cursor, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		page.Sort, page.Desc, page.Offset, page.cursor = cursor.Sort, cursor.Desc, 0, cursor
+	}
+	if page.Sort == "" {
+		page.Sort = "id"
+	}
+	if page.Limit == 0 && (page.cursor != nil || page.Offset > 0) {
+		page.Limit = DefaultLimit
+	}
+	return page, nil
+}
+
+// backward reports whether the page was requested through a prev cursor, in
+// which case rows are fetched in reverse and flipped back by Result.
+func (p *Page) backward() bool {
+	return p.cursor != nil && p.cursor.Prev
+}
+
+func (p *Page) descending() bool {
+	return p.Desc != p.backward()
+}
+
+// Keyset returns the condition that seeks past the cursor, or an empty
+// string when the page is not cursor based.
+func (p *Page) Keyset(expr, idColumn string) (string, []interface{}) {
+	if p.cursor == nil {
+		return "", nil
+	}
+	op := ">"
+	if p.descending() {
+		op = "<"
+	}
+	condition := fmt.Sprintf("(%[1]s %[3]s ? OR (%[1]s = ? AND %[2]s %[3]s ?))", expr, idColumn, op)
+	return condition, []interface{}{p.cursor.Value, p.cursor.Value, p.cursor.ID}
+}
+
+func (p *Page) OrderBy(expr, idColumn string) string {
+	direction := "ASC"
+	if p.descending() {
+		direction = "DESC"
+	}
+	if expr == idColumn {
+		return fmt.Sprintf(" ORDER BY %s %s", idColumn, direction)
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", expr, direction, idColumn, direction)
+}
+
+// LimitOffset fetches one row more than requested so Result can tell whether
+// another page follows.
+func (p *Page) LimitOffset() (string, []interface{}) {
+	if p.Limit == 0 {
+		return "", nil
+	}
+	return " LIMIT ? OFFSET ?", []interface{}{p.Limit + 1, p.Offset}
+}
+
+// Apply does in memory what OrderBy, Keyset and LimitOffset do in SQL.
+func Apply[T any](p *Page, rows []T, key func(T) (interface{}, int64)) []T {
+	less := func(a, b T) bool {
+		av, aid := key(a)
+		bv, bid := key(b)
+		c := compare(av, bv)
+		if c == 0 {
+			c = compare(aid, bid)
+		}
+		if p.descending() {
+			return c > 0
+		}
+		return c < 0
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return less(rows[i], rows[j])
+	})
+
+	if p.cursor != nil {
+		seek := make([]T, 0, len(rows))
+		for _, row := range rows {
+			v, id := key(row)
+			c := compare(v, p.cursor.Value)
+			if c == 0 {
+				c = compare(id, p.cursor.ID)
+			}
+			if (p.descending() && c < 0) || (!p.descending() && c > 0) {
+				seek = append(seek, row)
+			}
+		}
+		rows = seek
+	}
+
+	if p.Limit == 0 {
+		return rows
+	}
+	if p.Offset >= len(rows) {
+		return rows[:0]
+	}
+	rows = rows[p.Offset:]
+	if len(rows) > p.Limit+1 {
+		rows = rows[:p.Limit+1]
+	}
+	return rows
+}
+
+// Result drops the look-ahead row, restores the requested order and builds
+// the cursors of the neighbouring pages.
+func Result[T any](p *Page, rows []T, total int64, key func(T) (interface{}, int64)) ([]T, *model.PageInfo) {
+	info := &model.PageInfo{Total: total}
+	if p.Limit == 0 {
+		return rows, info
+	}
+
+	hasMore := len(rows) > p.Limit
+	if hasMore {
+		rows = rows[:p.Limit]
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+
+	hasNext, hasPrev := hasMore, p.Offset > 0 || p.cursor != nil
+	if p.backward() {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		v, id := key(rows[len(rows)-1])
+		info.Next = EncodeCursor(Cursor{Sort: p.Sort, Desc: p.Desc, Value: v, ID: id})
+	}
+	if hasPrev {
+		v, id := key(rows[0])
+		info.Prev = EncodeCursor(Cursor{Sort: p.Sort, Desc: p.Desc, Value: v, ID: id, Prev: true})
+	}
+	return rows, info
+}
+
+func compare(a, b interface{}) int {
+	switch av := a.(type) {
+	case int64:
+		bv, _ := b.(int64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(av, bv)
+	}
+	return 0
+}
@@ -0,0 +1,189 @@
+package query
+
+import (
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{Sort: "id", Value: int64(42), ID: 42},
+		{Sort: "title", Desc: true, Value: "Buy milk, eggs", ID: 7},
+		{Sort: "due_at", Value: "2023-04-01T10:00:00Z", ID: 3, Prev: true},
+	}
+	for _, want := range tests {
+		got, err := DecodeCursor(EncodeCursor(want))
+		if err != nil {
+			t.Fatalf("DecodeCursor(EncodeCursor(%+v)) error = %v", want, err)
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("DecodeCursor(EncodeCursor(%+v)) = %+v", want, *got)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := map[string]string{
+		"not base64":     "!!!",
+		"padded base64":  base64.URLEncoding.EncodeToString([]byte(`{"s":"id","v":1,"i":1}`)),
+		"not json":       encode("hello"),
+		"truncated json": encode(`{"s":"id","v":1`),
+		"float value":    encode(`{"s":"id","v":1.5,"i":1}`),
+		"bool value":     encode(`{"s":"id","v":true,"i":1}`),
+		"null value":     encode(`{"s":"id","v":null,"i":1}`),
+		"object value":   encode(`{"s":"id","v":{"a":1},"i":1}`),
+		"string id":      encode(`{"s":"id","v":1,"i":"1"}`),
+	}
+	for name, cursor := range tests {
+		_, err := DecodeCursor(cursor)
+		if err != model.ErrInvalidCursor {
+			t.Errorf("%s: DecodeCursor(%q) error = %v, want ErrInvalidCursor", name, cursor, err)
+			continue
+		}
+		if status := apperror.KindOf(err).Status(); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", name, status, http.StatusBadRequest)
+		}
+	}
+
+	if _, err := NewPage(model.Pagination{Cursor: "!!!"}); err != model.ErrInvalidCursor {
+		t.Errorf("NewPage with a garbage cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestNewPageTakesOrderFromCursor(t *testing.T) {
+	cursor := EncodeCursor(Cursor{Sort: "title", Desc: true, Value: "b", ID: 2})
+	p, err := NewPage(model.Pagination{Sort: "id", Offset: 40, Cursor: cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Sort != "title" || !p.Desc || p.Offset != 0 || p.Limit != DefaultLimit {
+		t.Errorf("NewPage = %+v, want the sort and order of the cursor, no offset and the default limit", p)
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	tests := []struct {
+		name      string
+		desc      bool
+		prev      bool
+		condition string
+	}{
+		{"asc", false, false, "(t.title > ? OR (t.title = ? AND t.todo_id > ?))"},
+		{"desc", true, false, "(t.title < ? OR (t.title = ? AND t.todo_id < ?))"},
+		{"asc prev", false, true, "(t.title < ? OR (t.title = ? AND t.todo_id < ?))"},
+		{"desc prev", true, true, "(t.title > ? OR (t.title = ? AND t.todo_id > ?))"},
+	}
+	for _, tt := range tests {
+		cursor := EncodeCursor(Cursor{Sort: "title", Desc: tt.desc, Value: "milk", ID: 9, Prev: tt.prev})
+		p, err := NewPage(model.Pagination{Cursor: cursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		condition, args := p.Keyset("t.title", "t.todo_id")
+		if condition != tt.condition {
+			t.Errorf("%s: condition = %q, want %q", tt.name, condition, tt.condition)
+		}
+		if want := []interface{}{"milk", "milk", int64(9)}; !reflect.DeepEqual(args, want) {
+			t.Errorf("%s: args = %v, want %v", tt.name, args, want)
+		}
+	}
+
+	p, err := NewPage(model.Pagination{Sort: "title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition, args := p.Keyset("t.title", "t.todo_id"); condition != "" || args != nil {
+		t.Errorf("Keyset without a cursor = %q, %v, want nothing", condition, args)
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		desc    bool
+		expr    string
+		orderBy string
+	}{
+		{false, "t.title", " ORDER BY t.title ASC, t.todo_id ASC"},
+		{true, "t.title", " ORDER BY t.title DESC, t.todo_id DESC"},
+		{false, "t.todo_id", " ORDER BY t.todo_id ASC"},
+		{true, "t.todo_id", " ORDER BY t.todo_id DESC"},
+	}
+	for _, tt := range tests {
+		p := &Page{Desc: tt.desc}
+		if got := p.OrderBy(tt.expr, "t.todo_id"); got != tt.orderBy {
+			t.Errorf("OrderBy(%q) desc=%v = %q, want %q", tt.expr, tt.desc, got, tt.orderBy)
+		}
+	}
+}
+
+type row struct {
+	name string
+	id   int64
+}
+
+func rowKey(r row) (interface{}, int64) {
+	return r.name, r.id
+}
+
+// page lists rows the way the memory repositories do.
+func page(t *testing.T, rows []row, pagination model.Pagination) ([]int64, *model.PageInfo) {
+	t.Helper()
+	p, err := NewPage(pagination)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, info := Result(p, Apply(p, append([]row(nil), rows...), rowKey), int64(len(rows)), rowKey)
+	ids := make([]int64, 0, len(got))
+	for _, r := range got {
+		ids = append(ids, r.id)
+	}
+	return ids, info
+}
+
+func TestPagingThroughCursors(t *testing.T) {
+	// Ties on name are broken by id.
+	rows := []row{{"b", 4}, {"a", 2}, {"c", 5}, {"a", 1}, {"b", 3}}
+
+	tests := []struct {
+		desc  bool
+		pages [][]int64
+	}{
+		{false, [][]int64{{1, 2}, {3, 4}, {5}}},
+		{true, [][]int64{{5, 4}, {3, 2}, {1}}},
+	}
+	for _, tt := range tests {
+		var infos []*model.PageInfo
+		ids, info := page(t, rows, model.Pagination{Sort: "name", Desc: tt.desc, Limit: 2})
+		for i, want := range tt.pages {
+			if !reflect.DeepEqual(ids, want) {
+				t.Fatalf("desc=%v page %d = %v, want %v", tt.desc, i, ids, want)
+			}
+			infos = append(infos, info)
+			if i < len(tt.pages)-1 {
+				ids, info = page(t, rows, model.Pagination{Limit: 2, Cursor: info.Next})
+			}
+		}
+		if info.Next != "" {
+			t.Errorf("desc=%v last page has a next cursor", tt.desc)
+		}
+
+		// Walking back from the last page gives the same pages.
+		for i := len(tt.pages) - 2; i >= 0; i-- {
+			ids, info = page(t, rows, model.Pagination{Limit: 2, Cursor: infos[i+1].Prev})
+			if !reflect.DeepEqual(ids, tt.pages[i]) {
+				t.Fatalf("desc=%v prev to page %d = %v, want %v", tt.desc, i, ids, tt.pages[i])
+			}
+		}
+		if info.Prev != "" {
+			t.Errorf("desc=%v first page reached backwards has a prev cursor", tt.desc)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+// Workspace starts the conditions of a query on a table holding workspace
+// data with the workspace of ctx. Every such query starts from it, so none
+// can miss the filter: a context that never had a workspace set fails with
+// model.ErrNoWorkspace instead of reaching the rows of every workspace.
+func Workspace(ctx context.Context) (Builder, error) {
+	return WorkspaceOf(ctx, "")
+}
+
+// WorkspaceOf is Workspace for queries joining several tables, where the
+// column needs the alias of its table.
+func WorkspaceOf(ctx context.Context, alias string) (b Builder, err error) {
+	if model.AllWorkspaces(ctx) {
+		return b, nil
+	}
+	workspaceID, err := WorkspaceID(ctx)
+	if err != nil {
+		return b, err
+	}
+	column := "workspace_id"
+	if alias != "" {
+		column = alias + "." + column
+	}
+	b.Where(column+"=?", workspaceID)
+	return b, nil
+}
+
+// InWorkspace is Workspace for the memory driver: it returns whether a row
+// of a workspace is visible to ctx.
+func InWorkspace(ctx context.Context) (func(workspaceID int64) bool, error) {
+	if model.AllWorkspaces(ctx) {
+		return func(int64) bool { return true }, nil
+	}
+	id, err := WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(workspaceID int64) bool { return workspaceID == id }, nil
+}
+
+// WorkspaceID returns the workspace rows created with ctx belong to. A
+// context seeing all workspaces cannot create any.
+func WorkspaceID(ctx context.Context) (int64, error) {
+	workspaceID, ok := model.WorkspaceFromContext(ctx)
+	if !ok {
+		return 0, model.ErrNoWorkspace
+	}
+	return workspaceID, nil
+}
+
+// Quota names the column of workspaces limiting the rows of a table, and
+// the error reported once the limit is reached.
+type Quota struct {
+	Column string
+	Err    error
+}
+
+// InsertInWorkspace inserts a row into table in the workspace of ctx and
+// returns its id. The count of rows is checked against quota, if any, in
+// the same statement, so concurrent inserts cannot overrun it.
+func InsertInWorkspace(ctx context.Context, executor transaction.Executor, table string, columns []string, args []interface{}, quota *Quota) (int64, error) {
+	workspaceID, err := WorkspaceID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := "INSERT INTO " + table + "(" + strings.Join(columns, ", ") + ", workspace_id) SELECT " +
+		strings.Repeat("?,", len(columns)) + "workspace_id FROM workspaces WHERE workspace_id=?"
+	args = append(args, workspaceID)
+	if quota != nil {
+		stmt += " AND (" + quota.Column + " IS NULL OR " + quota.Column + ">(SELECT COUNT(*) FROM " + table + " WHERE workspace_id=?))"
+		args = append(args, workspaceID)
+	}
+	result, err := executor.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 1 {
+		return result.LastInsertId()
+	}
+
+	var exists bool
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*)>0 FROM workspaces WHERE workspace_id=?", workspaceID).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if !exists || quota == nil {
+		return 0, model.ErrWorkspaceNotFound
+	}
+	return 0, quota.Err
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+func TestWorkspace(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		where string
+		args  []interface{}
+		err   error
+	}{
+		{"no workspace", ctx, "", nil, model.ErrNoWorkspace},
+		{"zero id", model.WithWorkspace(ctx, 0), "", nil, model.ErrNoWorkspace},
+		{"negative id", model.WithWorkspace(ctx, -1), "", nil, model.ErrNoWorkspace},
+		{"workspace", model.WithWorkspace(ctx, 3), " WHERE workspace_id=?", []interface{}{int64(3)}, nil},
+		{"all workspaces", model.WithAllWorkspaces(ctx), "", nil, nil},
+		{"all then zero id", model.WithWorkspace(model.WithAllWorkspaces(ctx), 0), "", nil, model.ErrNoWorkspace},
+		{"all then workspace", model.WithWorkspace(model.WithAllWorkspaces(ctx), 2), " WHERE workspace_id=?", []interface{}{int64(2)}, nil},
+	}
+	for _, tt := range tests {
+		b, err := Workspace(tt.ctx)
+		if err != tt.err {
+			t.Errorf("%s: Workspace error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if b.String() != tt.where || !reflect.DeepEqual(b.Args(), tt.args) {
+			t.Errorf("%s: Workspace = %q %v, want %q %v", tt.name, b.String(), b.Args(), tt.where, tt.args)
+		}
+
+		inWorkspace, err := InWorkspace(tt.ctx)
+		if err != tt.err {
+			t.Errorf("%s: InWorkspace error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if inWorkspace != nil && tt.args != nil && (!inWorkspace(tt.args[0].(int64)) || inWorkspace(0)) {
+			t.Errorf("%s: InWorkspace does not match the workspace alone", tt.name)
+		}
+	}
+
+	if _, err := WorkspaceID(model.WithAllWorkspaces(ctx)); err != model.ErrNoWorkspace {
+		t.Errorf("WorkspaceID of all workspaces error = %v, want ErrNoWorkspace", err)
+	}
+}
@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type TagRepository interface {
+	InsertTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error)
+	GetTagByID(ctx context.Context, id int64) (tag *entity.Tag, err error)
+	GetTagByName(ctx context.Context, name string) (tag *entity.Tag, err error)
+	GetAllTag(ctx context.Context) (tags []*entity.Tag, err error)
+	UpdateTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error)
+	DeleteTag(ctx context.Context, id int64) error
+	AddTodoTag(ctx context.Context, todoTag entity.TodoTag) error
+	RemoveTodoTag(ctx context.Context, todoTag entity.TodoTag) (removed bool, err error)
+	GetTagByTodoIDs(ctx context.Context, todoIDs []int64) (tags map[int64][]*entity.Tag, err error)
+}
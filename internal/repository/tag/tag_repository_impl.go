@@ -0,0 +1,178 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type TagRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &TagRepositoryImpl{db: db}
+}
+
+func (repo *TagRepositoryImpl) InsertTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error) {
+	id, err := query.InsertInWorkspace(ctx, transaction.GetExecutor(ctx, repo.db), "tags", []string{"name", "color"}, []interface{}{tag.Name, tag.Color}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetTagByID(ctx, id)
+}
+
+func (repo *TagRepositoryImpl) GetTagByID(ctx context.Context, id int64) (tag *entity.Tag, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("tag_id=?", id)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT "+tagColumns+" FROM tags"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanTag(rows)
+	}
+	return nil, model.ErrTagNotFound.WithMessage("Tag with ID %v Not Found", id)
+}
+
+// GetTagByName looks a tag up by name, ignoring case.
+func (repo *TagRepositoryImpl) GetTagByName(ctx context.Context, name string) (tag *entity.Tag, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("name=?", name)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT "+tagColumns+" FROM tags"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanTag(rows)
+	}
+	return nil, model.ErrTagNotFound.WithMessage("Tag %q Not Found", name)
+}
+
+func (repo *TagRepositoryImpl) GetAllTag(ctx context.Context) (tags []*entity.Tag, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT "+tagColumns+" FROM tags"+b.String()+" ORDER BY name, tag_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (repo *TagRepositoryImpl) UpdateTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("tag_id=?", tag.ID)
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE tags SET name=?, color=?"+b.String(), append([]interface{}{tag.Name, tag.Color}, b.Args()...)...)
+	if err != nil {
+		return nil, err
+	}
+	// MySQL counts unchanged rows as unaffected, so a missing tag is left
+	// for the lookup to report.
+	return repo.GetTagByID(ctx, tag.ID)
+}
+
+// DeleteTag permanently removes a tag; the foreign key drops it from every
+// todo.
+func (repo *TagRepositoryImpl) DeleteTag(ctx context.Context, id int64) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	b.Where("tag_id=?", id)
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "DELETE FROM tags"+b.String(), b.Args()...)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return model.ErrTagNotFound.WithMessage("Tag with ID %v Not Found", id)
+	}
+	return nil
+}
+
+func (repo *TagRepositoryImpl) AddTodoTag(ctx context.Context, todoTag entity.TodoTag) error {
+	query := "INSERT INTO todo_tags(todo_id, tag_id) VALUES(?,?)"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, todoTag.TodoID, todoTag.TagID)
+	return err
+}
+
+func (repo *TagRepositoryImpl) RemoveTodoTag(ctx context.Context, todoTag entity.TodoTag) (removed bool, err error) {
+	query := "DELETE FROM todo_tags WHERE todo_id=? AND tag_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, todoTag.TodoID, todoTag.TagID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetTagByTodoIDs returns the tags of the given todos ordered by name, keyed
+// by todo id.
+func (repo *TagRepositoryImpl) GetTagByTodoIDs(ctx context.Context, todoIDs []int64) (tags map[int64][]*entity.Tag, err error) {
+	tags = make(map[int64][]*entity.Tag)
+	if len(todoIDs) == 0 {
+		return tags, nil
+	}
+	b, err := query.WorkspaceOf(ctx, "g")
+	if err != nil {
+		return nil, err
+	}
+	b.WhereInIDs("tt.todo_id", todoIDs)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+		"SELECT tt.todo_id, g.tag_id, g.name, g.color, g.created_at, g.updated_at, g.workspace_id FROM todo_tags tt JOIN tags g ON g.tag_id=tt.tag_id"+
+			b.String()+" ORDER BY g.name, g.tag_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var todoID int64
+		var t entity.Tag
+		if err = rows.Scan(&todoID, &t.ID, &t.Name, &t.Color, &t.CreatedAt, &t.UpdatedAt, &t.WorkspaceID); err != nil {
+			return nil, err
+		}
+		tags[todoID] = append(tags[todoID], &t)
+	}
+	return tags, rows.Err()
+}
+
+// tagColumns are the columns scanTag reads.
+const tagColumns = "tag_id, name, color, created_at, updated_at, workspace_id"
+
+func scanTag(rows *sql.Rows) (*entity.Tag, error) {
+	var t entity.Tag
+	err := rows.Scan(&t.ID, &t.Name, &t.Color, &t.CreatedAt, &t.UpdatedAt, &t.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,214 @@
+package tag
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+type TagRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewTagMemoryRepository(db *infrastructure.MemoryDatabase) TagRepository {
+	return &TagRepositoryMemoryImpl{db: db}
+}
+
+func (repo *TagRepositoryMemoryImpl) InsertTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error) {
+	workspaceID, err := query.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if _, ok := repo.db.Workspaces[workspaceID]; !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	tag.WorkspaceID = workspaceID
+	if repo.nameTaken(tag) {
+		return nil, model.ErrTagNameTaken
+	}
+	now := repo.db.Now()
+	tag.ID = repo.db.NextID(tag.TableName())
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+	repo.db.Tags[tag.ID] = tag
+
+	return &tag, nil
+}
+
+func (repo *TagRepositoryMemoryImpl) GetTagByID(ctx context.Context, id int64) (tag *entity.Tag, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Tags[id]
+	if !ok || !inWorkspace(t.WorkspaceID) {
+		return nil, model.ErrTagNotFound.WithMessage("Tag with ID %v Not Found", id)
+	}
+	return &t, nil
+}
+
+func (repo *TagRepositoryMemoryImpl) GetTagByName(ctx context.Context, name string) (tag *entity.Tag, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Tags {
+		if strings.EqualFold(t.Name, name) && inWorkspace(t.WorkspaceID) {
+			return &t, nil
+		}
+	}
+	return nil, model.ErrTagNotFound.WithMessage("Tag %q Not Found", name)
+}
+
+func (repo *TagRepositoryMemoryImpl) GetAllTag(ctx context.Context) (tags []*entity.Tag, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Tags {
+		if !inWorkspace(t.WorkspaceID) {
+			continue
+		}
+		t := t
+		tags = append(tags, &t)
+	}
+	sortTagByName(tags)
+	return tags, nil
+}
+
+func (repo *TagRepositoryMemoryImpl) UpdateTag(ctx context.Context, tag entity.Tag) (*entity.Tag, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Tags[tag.ID]
+	if !ok || !inWorkspace(t.WorkspaceID) {
+		return nil, model.ErrTagNotFound.WithMessage("Tag with ID %v Not Found", tag.ID)
+	}
+	tag.WorkspaceID = t.WorkspaceID
+	if repo.nameTaken(tag) {
+		return nil, model.ErrTagNameTaken
+	}
+	t.Name = tag.Name
+	t.Color = tag.Color
+	t.UpdatedAt = repo.db.Now()
+	repo.db.Tags[t.ID] = t
+
+	return &t, nil
+}
+
+func (repo *TagRepositoryMemoryImpl) DeleteTag(ctx context.Context, id int64) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if t, ok := repo.db.Tags[id]; !ok || !inWorkspace(t.WorkspaceID) {
+		return model.ErrTagNotFound.WithMessage("Tag with ID %v Not Found", id)
+	}
+	delete(repo.db.Tags, id)
+	for tt := range repo.db.TodoTags {
+		if tt.TagID == id {
+			delete(repo.db.TodoTags, tt)
+		}
+	}
+	return nil
+}
+
+func (repo *TagRepositoryMemoryImpl) AddTodoTag(ctx context.Context, todoTag entity.TodoTag) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	repo.db.TodoTags[todoTag] = struct{}{}
+	return nil
+}
+
+func (repo *TagRepositoryMemoryImpl) RemoveTodoTag(ctx context.Context, todoTag entity.TodoTag) (removed bool, err error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if _, ok := repo.db.TodoTags[todoTag]; !ok {
+		return false, nil
+	}
+	delete(repo.db.TodoTags, todoTag)
+	return true, nil
+}
+
+func (repo *TagRepositoryMemoryImpl) GetTagByTodoIDs(ctx context.Context, todoIDs []int64) (tags map[int64][]*entity.Tag, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	wanted := make(map[int64]bool, len(todoIDs))
+	for _, id := range todoIDs {
+		wanted[id] = true
+	}
+	tags = make(map[int64][]*entity.Tag)
+	for tt := range repo.db.TodoTags {
+		if !wanted[tt.TodoID] {
+			continue
+		}
+		t := repo.db.Tags[tt.TagID]
+		if !inWorkspace(t.WorkspaceID) {
+			continue
+		}
+		tags[tt.TodoID] = append(tags[tt.TodoID], &t)
+	}
+	for _, t := range tags {
+		sortTagByName(t)
+	}
+	return tags, nil
+}
+
+// nameTaken mirrors the case-insensitive unique index on the workspace and
+// name of tags.
+func (repo *TagRepositoryMemoryImpl) nameTaken(tag entity.Tag) bool {
+	for _, t := range repo.db.Tags {
+		if t.ID != tag.ID && t.WorkspaceID == tag.WorkspaceID && strings.EqualFold(t.Name, tag.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortTagByName(tags []*entity.Tag) {
+	sort.Slice(tags, func(i, j int) bool {
+		a, b := strings.ToLower(tags[i].Name), strings.ToLower(tags[j].Name)
+		if a != b {
+			return a < b
+		}
+		return tags[i].ID < tags[j].ID
+	})
+}
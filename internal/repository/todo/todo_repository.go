@@ -1,11 +1,45 @@
 package todo
 
-import "github.com/vnnyx/golang-todo-api/internal/model/entity"
+import (
+	"context"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/search"
+)
 
 type TodoRepository interface {
-	InsertTodo(todo entity.Todo) (*entity.Todo, error)
-	GetTodoByID(id int64) (todo *entity.Todo, err error)
-	GetAllTodo(activityGroupID int64) (todos []*entity.Todo, err error)
-	UpdateTodo(todo entity.Todo) (*entity.Todo, error)
-	DeleteTodo(id int64, title string) error
+	InsertTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error)
+	GetTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error)
+	GetTodoByIDs(ctx context.Context, ids []int64) (todos []*entity.Todo, err error)
+	GetTodoByFilter(ctx context.Context, filter model.TodoFilter, limit int) (todos []*entity.Todo, err error)
+	// SearchTodo returns the todos matching q and filter past the cursor of
+	// pagination, best first, one more than its limit, and how many match
+	// in all.
+	SearchTodo(ctx context.Context, q search.Query, filter model.TodoFilter, pagination model.Pagination) (hits []*entity.TodoHit, total int64, err error)
+	GetAllTodo(ctx context.Context, filter model.TodoFilter, pagination model.Pagination) (todos []*entity.Todo, page *model.PageInfo, err error)
+	UpdateTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error)
+	UpdateTodos(ctx context.Context, todos []entity.Todo) ([]*entity.Todo, error)
+	SetTodoPositions(ctx context.Context, positions map[int64]string) error
+	InsertTodoTransition(ctx context.Context, transition entity.TodoTransition) error
+	InsertTodoTransitions(ctx context.Context, transitions []entity.TodoTransition) error
+	GetTodoTransitionByTodoID(ctx context.Context, todoID int64) (transitions []*entity.TodoTransition, err error)
+	DeleteTodos(ctx context.Context, ids []int64, deletedAt time.Time) error
+	GetTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (todos []*entity.Todo, err error)
+	CountTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (count int64, err error)
+	DeleteTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error
+	GetTodoByParentIDs(ctx context.Context, parentIDs []int64) (todos []*entity.Todo, err error)
+	CountTodoByParentIDs(ctx context.Context, parentIDs []int64) (progress map[int64]entity.TodoProgress, err error)
+	GetTrashedTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) (todos []*entity.Todo, err error)
+	RestoreTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) error
+	GetTrashedTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error)
+	// GetAllTrashedTodo returns the trashed todos past the cursor of
+	// pagination in trash order, one more than its limit, and how many
+	// are trashed in all.
+	GetAllTrashedTodo(ctx context.Context, pagination model.Pagination) (todos []*entity.Todo, total int64, err error)
+	RestoreTodo(ctx context.Context, id int64) (*entity.Todo, error)
+	GetTrashedTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) (todos []*entity.Todo, err error)
+	RestoreTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error
+	PurgeTodo(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
 }
@@ -1,143 +1,811 @@
 package todo
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/search"
 )
 
 type TodoRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect query.Dialect
 }
 
-func NewTodoRepository(db *sql.DB) TodoRepository {
+func NewTodoRepository(db *sql.DB, dialect query.Dialect) TodoRepository {
 	return &TodoRepositoryImpl{
-		db: db,
+		db:      db,
+		dialect: dialect,
 	}
 }
 
-func (repo *TodoRepositoryImpl) InsertTodo(todo entity.Todo) (*entity.Todo, error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	query := "INSERT INTO todos(activity_group_id, title, is_active) VALUES(?,?,?)"
+func (repo *TodoRepositoryImpl) InsertTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error) {
+	if todo.Priority == "" {
+		todo.Priority = entity.DefaultPriority
+	}
 	args := []interface{}{
 		todo.ActivityGroupID,
 		todo.Title,
 		todo.IsActive,
+		todo.Priority,
+		todo.Status,
+		query.FormatNullTime(todo.StartAt),
+		query.FormatNullTime(todo.DueAt),
+		todo.Recurrence,
+		todo.SeriesID,
+		todo.ParentTodoID,
+		todo.AutoComplete,
+		todo.Position,
+		todo.AssigneeID,
 	}
-	result, err := repo.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	id, err := result.LastInsertId()
+	columns := []string{"activity_group_id", "title", "is_active", "priority", "status", "start_at", "due_at", "recurrence", "series_id", "parent_todo_id", "auto_complete", "position", "assignee_id"}
+	id, err := query.InsertInWorkspace(ctx, transaction.GetExecutor(ctx, repo.db), "todos", columns, args,
+		&query.Quota{Column: "max_todos", Err: model.ErrTodoQuotaExceeded})
 	if err != nil {
 		return nil, err
 	}
 
-	t, err := repo.GetTodoByID(id)
+	t, err := repo.GetTodoByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return t, nil
 }
 
-func (repo *TodoRepositoryImpl) GetTodoByID(id int64) (todo *entity.Todo, err error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	query := "SELECT * FROM todos WHERE todo_id=?"
-	rows, err := repo.db.QueryContext(ctx, query, id)
+func (repo *TodoRepositoryImpl) GetTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("todo_id=?", id)
+	b.Where("deleted_at IS NULL")
+	memberOf(ctx, &b)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String(), b.Args()...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if rows.Next() {
-		var t entity.Todo
-		err := rows.Scan(&t.ID, &t.ActivityGroupID, &t.Title, &t.IsActive, &t.Priority, &t.CreatedAt, &t.UpdatedAt)
+		return scanTodo(rows)
+	}
+	return nil, model.ErrTodoNotFound.WithMessage("Todo with ID %v Not Found", id)
+}
+
+// GetTodoByIDs returns the todos with the given ids in id order, leaving out
+// those that do not exist.
+func (repo *TodoRepositoryImpl) GetTodoByIDs(ctx context.Context, ids []int64) (todos []*entity.Todo, err error) {
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(ids) {
+		b, err := query.Workspace(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.WhereInIDs("todo_id", batch)
+		b.Where("deleted_at IS NULL")
+		memberOf(ctx, &b)
+		rows, err := executor.QueryContext(ctx, "SELECT * FROM todos"+b.String(), b.Args()...)
 		if err != nil {
 			return nil, err
 		}
-		return &t, nil
+		for rows.Next() {
+			t, err := scanTodo(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			todos = append(todos, t)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
 	}
-	return nil, fmt.Errorf("Todo with ID %v Not Found", id)
+	sortTodoByID(todos)
+	return todos, nil
 }
 
-func (repo *TodoRepositoryImpl) GetAllTodo(activityGroupID int64) (todos []*entity.Todo, err error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
-
-	var rows *sql.Rows
+// GetTodoByFilter returns up to limit todos matching filter in id order.
+func (repo *TodoRepositoryImpl) GetTodoByFilter(ctx context.Context, filter model.TodoFilter, limit int) (todos []*entity.Todo, err error) {
+	b, err := todoConditions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	memberOf(ctx, &b)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String()+" ORDER BY todo_id LIMIT ?", append(b.Args(), limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	switch {
-	case activityGroupID != 0:
-		query := "SELECT * FROM todos WHERE activity_group_id=?"
-		rows, err = repo.db.QueryContext(ctx, query, activityGroupID)
+	for rows.Next() {
+		t, err := scanTodo(rows)
 		if err != nil {
 			return nil, err
 		}
-		defer rows.Close()
-	default:
-		query := "SELECT * FROM todos"
-		rows, err = repo.db.QueryContext(ctx, query)
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+func (repo *TodoRepositoryImpl) SearchTodo(ctx context.Context, q search.Query, filter model.TodoFilter, pagination model.Pagination) (hits []*entity.TodoHit, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.SearchSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := todoConditions(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	memberOf(ctx, &b)
+	join, joinArgs := repo.dialect.MatchTitle("todos", "todo_id", q.Terms())
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM todos"+join+b.String(), append(joinArgs, b.Args()...)...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if condition, args := p.Keyset("fts.score", "todos."+query.MixedTodoID); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	args := append(append(joinArgs, b.Args()...), limitArgs...)
+	rows, err := executor.QueryContext(ctx, "SELECT todos.*, fts.score FROM todos"+join+b.String()+p.OrderBy("fts.score", "todos."+query.MixedTodoID)+limit, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit entity.TodoHit
+		hit.Todo, err = scanTodo(rows, &hit.Score)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		defer rows.Close()
+		hits = append(hits, &hit)
+	}
+	return hits, total, rows.Err()
+}
+
+func (repo *TodoRepositoryImpl) GetAllTodo(ctx context.Context, filter model.TodoFilter, pagination model.Pagination) (todos []*entity.Todo, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortExpr, ok := todoSortColumns[p.Sort]
+	if !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
+
+	b, err := todoConditions(ctx, filter)
+	if err != nil {
+		return nil, nil, err
 	}
+	memberOf(ctx, &b)
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+
+	var total int64
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM todos"+b.String(), b.Args()...).Scan(&total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if condition, args := p.Keyset(sortExpr, "todo_id"); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	rows, err := executor.QueryContext(ctx, "SELECT * FROM todos"+b.String()+p.OrderBy(sortExpr, "todo_id")+limit, append(b.Args(), limitArgs...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var t entity.Todo
-		err := rows.Scan(&t.ID, &t.ActivityGroupID, &t.Title, &t.IsActive, &t.Priority, &t.CreatedAt, &t.UpdatedAt)
+		t, err := scanTodo(rows)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		todos = append(todos, &t)
+		todos = append(todos, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
 	}
-	return todos, nil
-}
 
-func (repo *TodoRepositoryImpl) UpdateTodo(todo entity.Todo) (*entity.Todo, error) {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
+	todos, page = query.Result(p, todos, total, todoSortKey(p.Sort))
+	return todos, page, nil
+}
 
-	query := "UPDATE todos SET title=?, priority=?, is_active=? WHERE todo_id=?"
+func (repo *TodoRepositoryImpl) UpdateTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error) {
 	args := []interface{}{
 		todo.Title,
 		todo.Priority,
 		todo.IsActive,
-		todo.ID,
+		todo.Status,
+		query.FormatNullTime(todo.StartAt),
+		query.FormatNullTime(todo.DueAt),
+		todo.Recurrence,
+		todo.SeriesID,
+		todo.AutoComplete,
+		todo.ActivityGroupID,
+		todo.Position,
+		todo.AssigneeID,
 	}
-	_, err := repo.db.ExecContext(ctx, query, args...)
+	b, err := query.Workspace(ctx)
 	if err != nil {
 		return nil, err
 	}
+	b.Where("todo_id=?", todo.ID)
+	b.Where("version=?", todo.Version)
+	b.Where("deleted_at IS NULL")
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx,
+		"UPDATE todos SET title=?, priority=?, is_active=?, status=?, start_at=?, due_at=?, recurrence=?, series_id=?, auto_complete=?, activity_group_id=?, position=?, assignee_id=?, version=version+1"+b.String(), append(args, b.Args()...)...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return nil, model.ErrVersionMismatch
+	}
 
-	t, err := repo.GetTodoByID(todo.ID)
+	t, err := repo.GetTodoByID(ctx, todo.ID)
 	if err != nil {
 		return nil, err
 	}
 	return t, nil
 }
 
-func (repo *TodoRepositoryImpl) DeleteTodo(id int64, title string) error {
-	ctx, cancel := infrastructure.NewMySQLContext()
-	defer cancel()
+// UpdateTodos writes several todos with one statement per batch, checking
+// the version of each the way UpdateTodo does.
+func (repo *TodoRepositoryImpl) UpdateTodos(ctx context.Context, todos []entity.Todo) ([]*entity.Todo, error) {
+	columns := []struct {
+		name  string
+		value func(t entity.Todo) interface{}
+	}{
+		{"title", func(t entity.Todo) interface{} { return t.Title }},
+		{"priority", func(t entity.Todo) interface{} { return t.Priority }},
+		{"is_active", func(t entity.Todo) interface{} { return t.IsActive }},
+		{"status", func(t entity.Todo) interface{} { return t.Status }},
+		{"start_at", func(t entity.Todo) interface{} { return query.FormatNullTime(t.StartAt) }},
+		{"due_at", func(t entity.Todo) interface{} { return query.FormatNullTime(t.DueAt) }},
+		{"recurrence", func(t entity.Todo) interface{} { return t.Recurrence }},
+		{"series_id", func(t entity.Todo) interface{} { return t.SeriesID }},
+		{"auto_complete", func(t entity.Todo) interface{} { return t.AutoComplete }},
+		{"activity_group_id", func(t entity.Todo) interface{} { return t.ActivityGroupID }},
+		{"position", func(t entity.Todo) interface{} { return t.Position }},
+		{"assignee_id", func(t entity.Todo) interface{} { return t.AssigneeID }},
+	}
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	ids := make([]int64, 0, len(todos))
+	for _, batch := range query.Batches(todos) {
+		var sb strings.Builder
+		args := make([]interface{}, 0, (2*len(columns)+2)*len(batch)+1)
+		sb.WriteString("UPDATE todos SET ")
+		for _, c := range columns {
+			sb.WriteString(c.name + "=CASE todo_id")
+			for _, t := range batch {
+				sb.WriteString(" WHEN ? THEN ?")
+				args = append(args, t.ID, c.value(t))
+			}
+			sb.WriteString(" END, ")
+		}
+		sb.WriteString("version=version+1")
+
+		b, err := query.Workspace(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.Where("deleted_at IS NULL")
+		matches := make([]string, 0, len(batch))
+		matchArgs := make([]interface{}, 0, 2*len(batch))
+		for _, t := range batch {
+			matches = append(matches, "(todo_id=? AND version=?)")
+			matchArgs = append(matchArgs, t.ID, t.Version)
+			ids = append(ids, t.ID)
+		}
+		b.Where("("+strings.Join(matches, " OR ")+")", matchArgs...)
+
+		result, err := executor.ExecContext(ctx, sb.String()+b.String(), append(args, b.Args()...)...)
+		if err != nil {
+			return nil, err
+		}
+		if affected, _ := result.RowsAffected(); affected != int64(len(batch)) {
+			return nil, model.ErrVersionMismatch
+		}
+	}
+	return repo.GetTodoByIDs(ctx, ids)
+}
+
+// SetTodoPositions rewrites the positions of todos when their group is
+// rebalanced. The order of the todos does not change, so neither do their
+// versions.
+func (repo *TodoRepositoryImpl) SetTodoPositions(ctx context.Context, positions map[int64]string) error {
+	ids := make([]int64, 0, len(positions))
+	for id := range positions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(ids) {
+		var sb strings.Builder
+		args := make([]interface{}, 0, 2*len(batch))
+		sb.WriteString("UPDATE todos SET position=CASE todo_id")
+		for _, id := range batch {
+			sb.WriteString(" WHEN ? THEN ?")
+			args = append(args, id, positions[id])
+		}
+		sb.WriteString(" END, updated_at=updated_at")
+
+		b, err := query.Workspace(ctx)
+		if err != nil {
+			return err
+		}
+		b.WhereInIDs("todo_id", batch)
+		_, err = executor.ExecContext(ctx, sb.String()+b.String(), append(args, b.Args()...)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	query := "DELETE FROM todos WHERE todo_id=? AND title=?"
+func (repo *TodoRepositoryImpl) InsertTodoTransition(ctx context.Context, transition entity.TodoTransition) error {
+	query := "INSERT INTO todo_status_transitions(todo_id, from_status, to_status) VALUES(?,?,?)"
 	args := []interface{}{
-		id,
-		title,
+		transition.TodoID,
+		transition.FromStatus,
+		transition.ToStatus,
+	}
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryImpl) InsertTodoTransitions(ctx context.Context, transitions []entity.TodoTransition) error {
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(transitions) {
+		args := make([]interface{}, 0, 3*len(batch))
+		for _, transition := range batch {
+			args = append(args, transition.TodoID, transition.FromStatus, transition.ToStatus)
+		}
+		_, err := executor.ExecContext(ctx, "INSERT INTO todo_status_transitions(todo_id, from_status, to_status) VALUES"+query.Values(len(batch), 3), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryImpl) GetTodoTransitionByTodoID(ctx context.Context, todoID int64) (transitions []*entity.TodoTransition, err error) {
+	query := "SELECT transition_id, todo_id, from_status, to_status, created_at FROM todo_status_transitions WHERE todo_id=? ORDER BY transition_id"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t entity.TodoTransition
+		err = rows.Scan(&t.ID, &t.TodoID, &t.FromStatus, &t.ToStatus, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, &t)
+	}
+	return transitions, rows.Err()
+}
+
+// DeleteTodos moves todos to the trash with one statement per batch.
+func (repo *TodoRepositoryImpl) DeleteTodos(ctx context.Context, ids []int64, deletedAt time.Time) error {
+	executor := transaction.GetExecutor(ctx, repo.db)
+	for _, batch := range query.Batches(ids) {
+		b, err := query.Workspace(ctx)
+		if err != nil {
+			return err
+		}
+		b.WhereInIDs("todo_id", batch)
+		b.Where("deleted_at IS NULL")
+		result, err := executor.ExecContext(ctx, "UPDATE todos SET deleted_at=?, version=version+1"+b.String(), append([]interface{}{query.FormatTime(deletedAt)}, b.Args()...)...)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected != int64(len(batch)) {
+			return model.ErrTodoNotFound
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryImpl) GetTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (todos []*entity.Todo, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_group_id=?", activityGroupID)
+	b.Where("deleted_at IS NULL")
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String()+" ORDER BY position, todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+func (repo *TodoRepositoryImpl) CountTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (count int64, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	b.Where("activity_group_id=?", activityGroupID)
+	b.Where("deleted_at IS NULL")
+	err = transaction.GetExecutor(ctx, repo.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM todos"+b.String(), b.Args()...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (repo *TodoRepositoryImpl) DeleteTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	b.Where("activity_group_id=?", activityGroupID)
+	b.Where("deleted_at IS NULL")
+	args := append([]interface{}{query.FormatTime(deletedAt)}, b.Args()...)
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE todos SET deleted_at=?, version=version+1"+b.String(), args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTodoByParentIDs returns the live subtasks of the given todos, ordered
+// by position.
+func (repo *TodoRepositoryImpl) GetTodoByParentIDs(ctx context.Context, parentIDs []int64) (todos []*entity.Todo, err error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.WhereInIDs("parent_todo_id", parentIDs)
+	b.Where("deleted_at IS NULL")
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String()+" ORDER BY position, todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+// CountTodoByParentIDs counts the live and the completed subtasks of the
+// given todos. Todos without subtasks are left out.
+func (repo *TodoRepositoryImpl) CountTodoByParentIDs(ctx context.Context, parentIDs []int64) (progress map[int64]entity.TodoProgress, err error) {
+	progress = make(map[int64]entity.TodoProgress)
+	if len(parentIDs) == 0 {
+		return progress, nil
+	}
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.WhereInIDs("parent_todo_id", parentIDs)
+	b.Where("deleted_at IS NULL")
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+		"SELECT parent_todo_id, SUM(CASE WHEN is_active THEN 0 ELSE 1 END), COUNT(*) FROM todos"+b.String()+" GROUP BY parent_todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var p entity.TodoProgress
+		if err = rows.Scan(&id, &p.Completed, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[id] = p
+	}
+	return progress, rows.Err()
+}
+
+// GetTrashedTodoByParentID returns the subtasks trashed together with their
+// parent at deletedAt.
+func (repo *TodoRepositoryImpl) GetTrashedTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) (todos []*entity.Todo, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("parent_todo_id=?", parentID)
+	b.Where("deleted_at=?", query.FormatTime(deletedAt))
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String()+" ORDER BY todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+// RestoreTodoByParentID restores the subtasks that were trashed together
+// with their parent, leaving ones trashed on their own untouched.
+func (repo *TodoRepositoryImpl) RestoreTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	b.Where("parent_todo_id=?", parentID)
+	b.Where("deleted_at=?", query.FormatTime(deletedAt))
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE todos SET deleted_at=NULL, version=version+1"+b.String(), b.Args()...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryImpl) GetTrashedTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("todo_id=?", id)
+	b.Where("deleted_at IS NOT NULL")
+	memberOf(ctx, &b)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanTodo(rows)
+	}
+	return nil, model.ErrTodoNotInTrash.WithMessage("Todo with ID %v Not Found in Trash", id)
+}
+
+func (repo *TodoRepositoryImpl) GetAllTrashedTodo(ctx context.Context, pagination model.Pagination) (todos []*entity.Todo, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.TrashSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	b.Where("deleted_at IS NOT NULL")
+	memberOf(ctx, &b)
+
+	executor := transaction.GetExecutor(ctx, repo.db)
+	err = executor.QueryRowContext(ctx, "SELECT COUNT(*) FROM todos"+b.String(), b.Args()...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if condition, args := p.Keyset(query.TrashSort, query.MixedTodoID); condition != "" {
+		b.Where(condition, args...)
+	}
+	limit, limitArgs := p.LimitOffset()
+	rows, err := executor.QueryContext(ctx, "SELECT * FROM todos"+b.String()+p.OrderBy(query.TrashSort, query.MixedTodoID)+limit, append(b.Args(), limitArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, total, rows.Err()
+}
+
+func (repo *TodoRepositoryImpl) RestoreTodo(ctx context.Context, id int64) (*entity.Todo, error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("todo_id=?", id)
+	b.Where("deleted_at IS NOT NULL")
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE todos SET deleted_at=NULL, version=version+1"+b.String(), b.Args()...)
+	if err != nil {
+		return nil, err
 	}
-	result, err := repo.db.ExecContext(ctx, query, args...)
 	if affected, _ := result.RowsAffected(); affected != 1 {
-		return fmt.Errorf("Todo with ID %v and Title: %v Not Found", id, title)
+		return nil, model.ErrTodoNotInTrash.WithMessage("Todo with ID %v Not Found in Trash", id)
+	}
+
+	return repo.GetTodoByID(ctx, id)
+}
+
+// GetTrashedTodoByActivityGroupID returns the todos trashed together with
+// their activity group at deletedAt.
+func (repo *TodoRepositoryImpl) GetTrashedTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) (todos []*entity.Todo, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Where("activity_group_id=?", activityGroupID)
+	b.Where("deleted_at=?", query.FormatTime(deletedAt))
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, "SELECT * FROM todos"+b.String()+" ORDER BY todo_id", b.Args()...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+// RestoreTodoByActivityGroupID restores the todos that were trashed together
+// with their activity group, leaving ones trashed on their own untouched.
+func (repo *TodoRepositoryImpl) RestoreTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return err
+	}
+	b.Where("activity_group_id=?", activityGroupID)
+	b.Where("deleted_at=?", query.FormatTime(deletedAt))
+	_, err = transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "UPDATE todos SET deleted_at=NULL, version=version+1"+b.String(), b.Args()...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// PurgeTodo permanently removes todos trashed before deletedBefore, along
+// with any todo whose activity group is about to be purged.
+func (repo *TodoRepositoryImpl) PurgeTodo(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	before := query.FormatTime(deletedBefore)
+	b.Where("((deleted_at IS NOT NULL AND deleted_at<?) "+
+		"OR activity_group_id IN (SELECT activity_id FROM activities WHERE deleted_at IS NOT NULL AND deleted_at<?))", before, before)
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, "DELETE FROM todos"+b.String(), b.Args()...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// todoConditions renders the conditions of a todo filter within the
+// workspace of ctx, leaving out trashed todos.
+func todoConditions(ctx context.Context, filter model.TodoFilter) (query.Builder, error) {
+	b, err := query.Workspace(ctx)
+	if err != nil {
+		return b, err
+	}
+	b.Where("deleted_at IS NULL")
+	if filter.ActivityGroupID != 0 {
+		b.Where("activity_group_id=?", filter.ActivityGroupID)
+	}
+	if filter.IsActive != nil {
+		b.Where("is_active=?", *filter.IsActive)
+	}
+	b.WhereIn("priority", filter.Priorities)
+	b.WhereIn("status", filter.Statuses)
+	if filter.SeriesID != 0 {
+		b.Where("series_id=?", filter.SeriesID)
+	}
+	if filter.ParentTodoID != 0 {
+		b.Where("parent_todo_id=?", filter.ParentTodoID)
+	}
+	if filter.TopLevel {
+		b.Where("parent_todo_id IS NULL")
+	}
+	if filter.Scheduled {
+		b.Where("due_at IS NOT NULL")
+	}
+	if filter.AssigneeID != 0 {
+		b.Where("assignee_id=?", filter.AssigneeID)
+	}
+	if filter.Unassigned {
+		b.Where("assignee_id IS NULL")
+	}
+	if len(filter.Tags) > 0 {
+		var tb query.Builder
+		tb.WhereIn("g.name", filter.Tags)
+		tagged := "SELECT tt.todo_id FROM todo_tags tt JOIN tags g ON g.tag_id=tt.tag_id" + tb.String()
+		args := tb.Args()
+		if filter.TagMatchAll {
+			tagged += " GROUP BY tt.todo_id HAVING COUNT(DISTINCT g.tag_id)=?"
+			args = append(args, len(filter.Tags))
+		}
+		b.Where("todo_id IN ("+tagged+")", args...)
+	}
+	if filter.Title != "" {
+		b.Where("title LIKE ? ESCAPE '"+query.LikeEscape+"'", query.Contains(filter.Title))
+	}
+	if filter.CreatedAfter != nil {
+		b.Where("created_at>=?", query.FormatTime(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		b.Where("created_at<?", query.FormatTime(*filter.CreatedBefore))
+	}
+	if filter.UpdatedAfter != nil {
+		b.Where("updated_at>=?", query.FormatTime(*filter.UpdatedAfter))
+	}
+	if filter.UpdatedBefore != nil {
+		b.Where("updated_at<?", query.FormatTime(*filter.UpdatedBefore))
+	}
+	if filter.DueAfter != nil {
+		b.Where("due_at>=?", query.FormatTime(*filter.DueAfter))
+	}
+	if filter.DueBefore != nil {
+		b.Where("due_at<?", query.FormatTime(*filter.DueBefore))
+	}
+	if filter.Overdue != nil {
+		now := query.FormatTime(time.Now())
+		if *filter.Overdue {
+			b.Where("(due_at<? AND is_active=?)", now, true)
+		} else {
+			b.Where("(due_at IS NULL OR due_at>=? OR is_active=?)", now, false)
+		}
+	}
+	return b, nil
+}
+
+// memberOf limits b to the todos in activity groups the user of ctx, if
+// any, is a member of.
+func memberOf(ctx context.Context, b *query.Builder) {
+	if userID, ok := model.UserFromContext(ctx); ok {
+		b.Where("activity_group_id IN (SELECT activity_id FROM activity_members WHERE user_id=?)", userID)
+	}
+}
+
+// scanTodo scans a row of todos, followed by the columns in extra if the
+// query selects more.
+func scanTodo(rows *sql.Rows, extra ...interface{}) (*entity.Todo, error) {
+	var t entity.Todo
+	dest := []interface{}{&t.ID, &t.ActivityGroupID, &t.Title, &t.IsActive, &t.Priority, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt, &t.Version, &t.Status, &t.StartAt, &t.DueAt, &t.Recurrence, &t.SeriesID, &t.ParentTodoID, &t.AutoComplete, &t.Position, &t.WorkspaceID, &t.AssigneeID}
+	err := rows.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
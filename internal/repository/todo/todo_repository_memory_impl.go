@@ -0,0 +1,735 @@
+package todo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/search"
+)
+
+type TodoRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewTodoMemoryRepository(db *infrastructure.MemoryDatabase) TodoRepository {
+	return &TodoRepositoryMemoryImpl{
+		db: db,
+	}
+}
+
+func (repo *TodoRepositoryMemoryImpl) InsertTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error) {
+	workspaceID, err := query.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	w, ok := repo.db.Workspaces[workspaceID]
+	if !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	if a, ok := repo.db.Activities[todo.ActivityGroupID]; !ok || a.DeletedAt != nil || a.WorkspaceID != workspaceID {
+		return nil, model.ErrActivityGroupNotFound
+	}
+	if w.MaxTodos != nil {
+		var count int64
+		for _, t := range repo.db.Todos {
+			if t.WorkspaceID == workspaceID {
+				count++
+			}
+		}
+		if count >= *w.MaxTodos {
+			return nil, model.ErrTodoQuotaExceeded
+		}
+	}
+
+	now := repo.db.Now()
+	todo.ID = repo.db.NextID(todo.TableName())
+	todo.WorkspaceID = workspaceID
+	if todo.Priority == "" {
+		todo.Priority = entity.DefaultPriority
+	}
+	todo.CreatedAt = now
+	todo.UpdatedAt = now
+	todo.Version = 1
+	repo.db.Todos[todo.ID] = todo
+
+	return &todo, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Todos[id]
+	if !ok || t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) {
+		return nil, model.ErrTodoNotFound.WithMessage("Todo with ID %v Not Found", id)
+	}
+	return &t, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoByIDs(ctx context.Context, ids []int64) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, id := range ids {
+		if t, ok := repo.db.Todos[id]; ok && t.DeletedAt == nil && inWorkspace(t.WorkspaceID) && repo.memberOf(ctx, t) {
+			t := t
+			todos = append(todos, &t)
+		}
+	}
+	sortTodoByID(todos)
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoByFilter(ctx context.Context, filter model.TodoFilter, limit int) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	tagged := repo.taggedTodos(filter)
+	for _, t := range repo.db.Todos {
+		if t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) || !matchTodo(t, filter, now) || (tagged != nil && !tagged[t.ID]) {
+			continue
+		}
+		t := t
+		todos = append(todos, &t)
+	}
+	sortTodoByID(todos)
+	if len(todos) > limit {
+		todos = todos[:limit]
+	}
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetAllTodo(ctx context.Context, filter model.TodoFilter, pagination model.Pagination) (todos []*entity.Todo, page *model.PageInfo, err error) {
+	p, err := query.NewPage(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := todoSortColumns[p.Sort]; !ok {
+		return nil, nil, model.ErrInvalidSort
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	tagged := repo.taggedTodos(filter)
+	for _, t := range repo.db.Todos {
+		if t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) || !matchTodo(t, filter, now) || (tagged != nil && !tagged[t.ID]) {
+			continue
+		}
+		t := t
+		todos = append(todos, &t)
+	}
+
+	total := int64(len(todos))
+	key := todoSortKey(p.Sort)
+	todos, page = query.Result(p, query.Apply(p, todos, key), total, key)
+	return todos, page, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) SearchTodo(ctx context.Context, q search.Query, filter model.TodoFilter, pagination model.Pagination) (hits []*entity.TodoHit, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.SearchSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	tagged := repo.taggedTodos(filter)
+	var todos []*entity.Todo
+	var titles []string
+	for _, t := range repo.db.Todos {
+		if t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) || !matchTodo(t, filter, now) || (tagged != nil && !tagged[t.ID]) {
+			continue
+		}
+		t := t
+		todos = append(todos, &t)
+		titles = append(titles, t.Title)
+	}
+
+	for _, r := range search.Rank(q, titles) {
+		hits = append(hits, &entity.TodoHit{Todo: todos[r.Index], Score: int64(math.Round(r.Score * search.ScoreScale))})
+	}
+	total = int64(len(hits))
+	return query.Apply(p, hits, func(hit *entity.TodoHit) (interface{}, int64) {
+		return hit.Score, query.MixedTodoKey(hit.Todo.ID)
+	}), total, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) UpdateTodo(ctx context.Context, todo entity.Todo) (*entity.Todo, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Todos[todo.ID]
+	if !ok || t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) {
+		return nil, model.ErrTodoNotFound.WithMessage("Todo with ID %v Not Found", todo.ID)
+	}
+	if t.Version != todo.Version {
+		return nil, model.ErrVersionMismatch
+	}
+	t.Title = todo.Title
+	t.Priority = todo.Priority
+	t.IsActive = todo.IsActive
+	t.Status = todo.Status
+	t.StartAt = todo.StartAt
+	t.DueAt = todo.DueAt
+	t.Recurrence = todo.Recurrence
+	t.SeriesID = todo.SeriesID
+	t.AutoComplete = todo.AutoComplete
+	t.ActivityGroupID = todo.ActivityGroupID
+	t.Position = todo.Position
+	t.AssigneeID = todo.AssigneeID
+	t.UpdatedAt = repo.db.Now()
+	t.Version++
+	repo.db.Todos[t.ID] = t
+
+	return &t, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) UpdateTodos(ctx context.Context, todos []entity.Todo) ([]*entity.Todo, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for _, todo := range todos {
+		if t, ok := repo.db.Todos[todo.ID]; !ok || t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) || t.Version != todo.Version {
+			return nil, model.ErrVersionMismatch
+		}
+	}
+
+	now := repo.db.Now()
+	got := make([]*entity.Todo, 0, len(todos))
+	for _, todo := range todos {
+		t := repo.db.Todos[todo.ID]
+		t.Title = todo.Title
+		t.Priority = todo.Priority
+		t.IsActive = todo.IsActive
+		t.Status = todo.Status
+		t.StartAt = todo.StartAt
+		t.DueAt = todo.DueAt
+		t.Recurrence = todo.Recurrence
+		t.SeriesID = todo.SeriesID
+		t.AutoComplete = todo.AutoComplete
+		t.ActivityGroupID = todo.ActivityGroupID
+		t.Position = todo.Position
+		t.AssigneeID = todo.AssigneeID
+		t.UpdatedAt = now
+		t.Version++
+		repo.db.Todos[t.ID] = t
+		got = append(got, &t)
+	}
+	sortTodoByID(got)
+	return got, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) SetTodoPositions(ctx context.Context, positions map[int64]string) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, position := range positions {
+		if t, ok := repo.db.Todos[id]; ok && inWorkspace(t.WorkspaceID) {
+			t.Position = position
+			repo.db.Todos[id] = t
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) InsertTodoTransition(ctx context.Context, transition entity.TodoTransition) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	transition.ID = repo.db.NextID(transition.TableName())
+	transition.CreatedAt = repo.db.Now()
+	repo.db.TodoTransitions[transition.ID] = transition
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) InsertTodoTransitions(ctx context.Context, transitions []entity.TodoTransition) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	now := repo.db.Now()
+	for _, transition := range transitions {
+		transition.ID = repo.db.NextID(transition.TableName())
+		transition.CreatedAt = now
+		repo.db.TodoTransitions[transition.ID] = transition
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoTransitionByTodoID(ctx context.Context, todoID int64) (transitions []*entity.TodoTransition, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.TodoTransitions {
+		if t.TodoID != todoID {
+			continue
+		}
+		t := t
+		transitions = append(transitions, &t)
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].ID < transitions[j].ID
+	})
+	return transitions, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) DeleteTodos(ctx context.Context, ids []int64, deletedAt time.Time) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for _, id := range ids {
+		if t, ok := repo.db.Todos[id]; !ok || t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) {
+			return model.ErrTodoNotFound
+		}
+	}
+	for _, id := range ids {
+		t := repo.db.Todos[id]
+		t.DeletedAt = &deletedAt
+		t.Version++
+		repo.db.Todos[id] = t
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.ActivityGroupID == activityGroupID && t.DeletedAt == nil && inWorkspace(t.WorkspaceID) {
+			t := t
+			todos = append(todos, &t)
+		}
+	}
+	sortTodoByPosition(todos)
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) CountTodoByActivityGroupID(ctx context.Context, activityGroupID int64) (count int64, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.ActivityGroupID == activityGroupID && t.DeletedAt == nil && inWorkspace(t.WorkspaceID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) DeleteTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, t := range repo.db.Todos {
+		if t.ActivityGroupID == activityGroupID && t.DeletedAt == nil && inWorkspace(t.WorkspaceID) {
+			t.DeletedAt = &deletedAt
+			t.Version++
+			repo.db.Todos[id] = t
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTodoByParentIDs(ctx context.Context, parentIDs []int64) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.ParentTodoID != nil && containsID(parentIDs, *t.ParentTodoID) && t.DeletedAt == nil && inWorkspace(t.WorkspaceID) {
+			t := t
+			todos = append(todos, &t)
+		}
+	}
+	sortTodoByPosition(todos)
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) CountTodoByParentIDs(ctx context.Context, parentIDs []int64) (progress map[int64]entity.TodoProgress, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	progress = make(map[int64]entity.TodoProgress)
+	for _, t := range repo.db.Todos {
+		if t.ParentTodoID == nil || !containsID(parentIDs, *t.ParentTodoID) || t.DeletedAt != nil || !inWorkspace(t.WorkspaceID) {
+			continue
+		}
+		p := progress[*t.ParentTodoID]
+		p.Total++
+		if !t.IsActive {
+			p.Completed++
+		}
+		progress[*t.ParentTodoID] = p
+	}
+	return progress, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTrashedTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.ParentTodoID != nil && *t.ParentTodoID == parentID && t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) && inWorkspace(t.WorkspaceID) {
+			t := t
+			todos = append(todos, &t)
+		}
+	}
+	sortTodoByID(todos)
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) RestoreTodoByParentID(ctx context.Context, parentID int64, deletedAt time.Time) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, t := range repo.db.Todos {
+		if t.ParentTodoID != nil && *t.ParentTodoID == parentID && t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) && inWorkspace(t.WorkspaceID) {
+			t.DeletedAt = nil
+			t.Version++
+			repo.db.Todos[id] = t
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTrashedTodoByID(ctx context.Context, id int64) (todo *entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Todos[id]
+	if !ok || t.DeletedAt == nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) {
+		return nil, model.ErrTodoNotInTrash.WithMessage("Todo with ID %v Not Found in Trash", id)
+	}
+	return &t, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetAllTrashedTodo(ctx context.Context, pagination model.Pagination) (todos []*entity.Todo, total int64, err error) {
+	p, err := query.NewMixedPage(pagination, query.TrashSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.DeletedAt == nil || !inWorkspace(t.WorkspaceID) || !repo.memberOf(ctx, t) {
+			continue
+		}
+		t := t
+		todos = append(todos, &t)
+	}
+	total = int64(len(todos))
+	return query.Apply(p, todos, func(t *entity.Todo) (interface{}, int64) {
+		return query.TrashTodoKey(t.ID, *t.DeletedAt)
+	}), total, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) RestoreTodo(ctx context.Context, id int64) (*entity.Todo, error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	t, ok := repo.db.Todos[id]
+	if !ok || t.DeletedAt == nil || !inWorkspace(t.WorkspaceID) {
+		return nil, model.ErrTodoNotInTrash.WithMessage("Todo with ID %v Not Found in Trash", id)
+	}
+	t.DeletedAt = nil
+	t.Version++
+	repo.db.Todos[id] = t
+	return &t, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) GetTrashedTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) (todos []*entity.Todo, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, t := range repo.db.Todos {
+		if t.ActivityGroupID == activityGroupID && t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) && inWorkspace(t.WorkspaceID) {
+			t := t
+			todos = append(todos, &t)
+		}
+	}
+	sortTodoByID(todos)
+	return todos, nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) RestoreTodoByActivityGroupID(ctx context.Context, activityGroupID int64, deletedAt time.Time) error {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, t := range repo.db.Todos {
+		if t.ActivityGroupID == activityGroupID && t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) && inWorkspace(t.WorkspaceID) {
+			t.DeletedAt = nil
+			t.Version++
+			repo.db.Todos[id] = t
+		}
+	}
+	return nil
+}
+
+func (repo *TodoRepositoryMemoryImpl) PurgeTodo(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	inWorkspace, err := query.InWorkspace(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for id, t := range repo.db.Todos {
+		if !inWorkspace(t.WorkspaceID) {
+			continue
+		}
+		a := repo.db.Activities[t.ActivityGroupID]
+		if (t.DeletedAt != nil && t.DeletedAt.Before(deletedBefore)) ||
+			(a.DeletedAt != nil && a.DeletedAt.Before(deletedBefore)) {
+			delete(repo.db.Todos, id)
+			purged++
+		}
+	}
+	for tt := range repo.db.TodoTags {
+		if _, ok := repo.db.Todos[tt.TodoID]; !ok {
+			delete(repo.db.TodoTags, tt)
+		}
+	}
+	for key := range repo.db.TodoWatchers {
+		if _, ok := repo.db.Todos[key.TodoID]; !ok {
+			delete(repo.db.TodoWatchers, key)
+		}
+	}
+	for id, c := range repo.db.TodoComments {
+		if _, ok := repo.db.Todos[c.TodoID]; !ok {
+			delete(repo.db.TodoComments, id)
+		}
+	}
+	return purged, nil
+}
+
+// memberOf reports whether the user of ctx, if any, is a member of the
+// activity group of t. Callers must hold a lock.
+func (repo *TodoRepositoryMemoryImpl) memberOf(ctx context.Context, t entity.Todo) bool {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return true
+	}
+	_, ok = repo.db.ActivityMembers[entity.MemberKey{ActivityID: t.ActivityGroupID, UserID: userID}]
+	return ok
+}
+
+// taggedTodos returns the ids of the todos matching the tag filter, or nil
+// when the filter names no tags. Callers must hold the lock.
+func (repo *TodoRepositoryMemoryImpl) taggedTodos(filter model.TodoFilter) map[int64]bool {
+	if len(filter.Tags) == 0 {
+		return nil
+	}
+	matches := make(map[int64]int)
+	for tt := range repo.db.TodoTags {
+		for _, name := range filter.Tags {
+			if strings.EqualFold(repo.db.Tags[tt.TagID].Name, name) {
+				matches[tt.TodoID]++
+				break
+			}
+		}
+	}
+	tagged := make(map[int64]bool, len(matches))
+	for id, n := range matches {
+		tagged[id] = !filter.TagMatchAll || n == len(filter.Tags)
+	}
+	return tagged
+}
+
+func matchTodo(t entity.Todo, filter model.TodoFilter, now time.Time) bool {
+	switch {
+	case filter.ActivityGroupID != 0 && t.ActivityGroupID != filter.ActivityGroupID:
+		return false
+	case filter.IsActive != nil && t.IsActive != *filter.IsActive:
+		return false
+	case len(filter.Priorities) > 0 && !containsString(filter.Priorities, t.Priority):
+		return false
+	case len(filter.Statuses) > 0 && !containsString(filter.Statuses, t.Status):
+		return false
+	case filter.SeriesID != 0 && (t.SeriesID == nil || *t.SeriesID != filter.SeriesID):
+		return false
+	case filter.ParentTodoID != 0 && (t.ParentTodoID == nil || *t.ParentTodoID != filter.ParentTodoID):
+		return false
+	case filter.TopLevel && t.ParentTodoID != nil:
+		return false
+	case filter.Scheduled && t.DueAt == nil:
+		return false
+	case filter.AssigneeID != 0 && (t.AssigneeID == nil || *t.AssigneeID != filter.AssigneeID):
+		return false
+	case filter.Unassigned && t.AssigneeID != nil:
+		return false
+	case filter.Title != "" && !strings.Contains(strings.ToLower(t.Title), strings.ToLower(filter.Title)):
+		return false
+	case filter.CreatedAfter != nil && t.CreatedAt.Before(*filter.CreatedAfter):
+		return false
+	case filter.CreatedBefore != nil && !t.CreatedAt.Before(*filter.CreatedBefore):
+		return false
+	case filter.UpdatedAfter != nil && t.UpdatedAt.Before(*filter.UpdatedAfter):
+		return false
+	case filter.UpdatedBefore != nil && !t.UpdatedAt.Before(*filter.UpdatedBefore):
+		return false
+	case filter.DueAfter != nil && (t.DueAt == nil || t.DueAt.Before(*filter.DueAfter)):
+		return false
+	case filter.DueBefore != nil && (t.DueAt == nil || !t.DueAt.Before(*filter.DueBefore)):
+		return false
+	case filter.Overdue != nil && isOverdue(t, now) != *filter.Overdue:
+		return false
+	}
+	return true
+}
+
+func isOverdue(t entity.Todo, now time.Time) bool {
+	return t.IsActive && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+func sortTodoByPosition(todos []*entity.Todo) {
+	sort.Slice(todos, func(i, j int) bool {
+		if todos[i].Position != todos[j].Position {
+			return todos[i].Position < todos[j].Position
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
+
+func sortTodoByID(todos []*entity.Todo) {
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].ID < todos[j].ID
+	})
+}
+
+func containsID(values []int64, id int64) bool {
+	for _, v := range values {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
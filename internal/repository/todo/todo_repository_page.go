@@ -0,0 +1,68 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+)
+
+// todoSortColumns maps the sort keys accepted by GetAllTodo to SQL
+// expressions. Priority sorts by urgency rather than alphabetically.
+var todoSortColumns = map[string]string{
+	"id":         "todo_id",
+	"title":      "title",
+	"priority":   priorityRankExpr(),
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"start_at":   "COALESCE(start_at, '" + nullTimeSortValue + "')",
+	"due_at":     "COALESCE(due_at, '" + nullTimeSortValue + "')",
+	"position":   "position",
+}
+
+// nullTimeSortValue stands in for missing dates so they sort last and still
+// work as a cursor value.
+const nullTimeSortValue = "9999-12-31 23:59:59"
+
+func priorityRankExpr() string {
+	var b strings.Builder
+	b.WriteString("(CASE priority")
+	for _, p := range entity.Priorities {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", p, entity.PriorityRank(p))
+	}
+	b.WriteString(" ELSE 0 END)")
+	return b.String()
+}
+
+// todoSortKey returns the value of t that todoSortColumns[sort] evaluates to.
+func todoSortKey(sort string) func(t *entity.Todo) (interface{}, int64) {
+	return func(t *entity.Todo) (interface{}, int64) {
+		switch sort {
+		case "title":
+			return t.Title, t.ID
+		case "priority":
+			return entity.PriorityRank(t.Priority), t.ID
+		case "created_at":
+			return query.FormatTime(t.CreatedAt), t.ID
+		case "updated_at":
+			return query.FormatTime(t.UpdatedAt), t.ID
+		case "start_at":
+			return formatNullTimeSortValue(t.StartAt), t.ID
+		case "due_at":
+			return formatNullTimeSortValue(t.DueAt), t.ID
+		case "position":
+			return t.Position, t.ID
+		default:
+			return t.ID, t.ID
+		}
+	}
+}
+
+func formatNullTimeSortValue(t *time.Time) string {
+	if t == nil {
+		return nullTimeSortValue
+	}
+	return query.FormatTime(*t)
+}
@@ -0,0 +1,31 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+)
+
+// TxManager runs a unit of work atomically. Repositories called with the
+// context passed to fn take part in the same transaction; calling
+// WithinTransaction again with that context joins the outer transaction.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Executor is the subset of *sql.DB and *sql.Tx used by the SQL repositories.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// GetExecutor returns the transaction carried by ctx, or db when the call is
+// not part of a unit of work.
+func GetExecutor(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,71 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	mysqlDeadlockErrorNumber = 1213
+	maxDeadlockRetries       = 3
+)
+
+type TxManagerImpl struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) TxManager {
+	return &TxManagerImpl{db: db}
+}
+
+func (tm *TxManagerImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := tm.run(ctx, fn)
+		if !isDeadlock(err) || attempt > maxDeadlockRetries {
+			return err
+		}
+		logrus.Warnf("deadlock detected, retrying transaction (attempt %v)", attempt)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt*50) * time.Millisecond):
+		}
+	}
+}
+
+func (tm *TxManagerImpl) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			logrus.Error(rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDeadlockErrorNumber
+}
@@ -0,0 +1,19 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+)
+
+type TxManagerMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewMemoryTxManager(db *infrastructure.MemoryDatabase) TxManager {
+	return &TxManagerMemoryImpl{db: db}
+}
+
+func (tm *TxManagerMemoryImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.db.Transaction(ctx, fn)
+}
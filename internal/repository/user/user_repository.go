@@ -0,0 +1,13 @@
+package user
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type UserRepository interface {
+	InsertUser(ctx context.Context, user entity.User) (*entity.User, error)
+	GetUserByID(ctx context.Context, id int64) (user *entity.User, err error)
+	GetUserByEmail(ctx context.Context, email string) (user *entity.User, err error)
+}
@@ -0,0 +1,71 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type UserRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &UserRepositoryImpl{db: db}
+}
+
+func (repo *UserRepositoryImpl) InsertUser(ctx context.Context, user entity.User) (*entity.User, error) {
+	query := "INSERT INTO users(email, name, password_hash) VALUES(?,?,?)"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, user.Email, user.Name, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetUserByID(ctx, id)
+}
+
+func (repo *UserRepositoryImpl) GetUserByID(ctx context.Context, id int64) (user *entity.User, err error) {
+	query := "SELECT user_id, email, name, password_hash, created_at, updated_at FROM users WHERE user_id=?"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanUser(rows)
+	}
+	return nil, model.ErrUserNotFound
+}
+
+// GetUserByEmail looks a user up by email, ignoring case.
+func (repo *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (user *entity.User, err error) {
+	query := "SELECT user_id, email, name, password_hash, created_at, updated_at FROM users WHERE email=?"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanUser(rows)
+	}
+	return nil, model.ErrUserNotFound
+}
+
+func scanUser(rows *sql.Rows) (*entity.User, error) {
+	var u entity.User
+	err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,59 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type UserRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewUserMemoryRepository(db *infrastructure.MemoryDatabase) UserRepository {
+	return &UserRepositoryMemoryImpl{db: db}
+}
+
+func (repo *UserRepositoryMemoryImpl) InsertUser(ctx context.Context, user entity.User) (*entity.User, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for _, u := range repo.db.Users {
+		if strings.EqualFold(u.Email, user.Email) {
+			return nil, model.ErrEmailTaken
+		}
+	}
+	now := repo.db.Now()
+	user.ID = repo.db.NextID(user.TableName())
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	repo.db.Users[user.ID] = user
+
+	return &user, nil
+}
+
+func (repo *UserRepositoryMemoryImpl) GetUserByID(ctx context.Context, id int64) (user *entity.User, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	u, ok := repo.db.Users[id]
+	if !ok {
+		return nil, model.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (repo *UserRepositoryMemoryImpl) GetUserByEmail(ctx context.Context, email string) (user *entity.User, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, u := range repo.db.Users {
+		if strings.EqualFold(u.Email, email) {
+			return &u, nil
+		}
+	}
+	return nil, model.ErrUserNotFound
+}
@@ -0,0 +1,13 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type WatcherRepository interface {
+	InsertWatcher(ctx context.Context, watcher entity.TodoWatcher) error
+	DeleteWatcher(ctx context.Context, todoID, userID int64) (removed bool, err error)
+	GetWatcherByTodoIDs(ctx context.Context, todoIDs []int64) (watchers map[int64][]*entity.TodoWatcher, err error)
+}
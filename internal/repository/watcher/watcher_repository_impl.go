@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type WatcherRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewWatcherRepository(db *sql.DB) WatcherRepository {
+	return &WatcherRepositoryImpl{db: db}
+}
+
+func (repo *WatcherRepositoryImpl) InsertWatcher(ctx context.Context, watcher entity.TodoWatcher) error {
+	query := "INSERT INTO todo_watchers(todo_id, user_id) VALUES(?,?)"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, watcher.TodoID, watcher.UserID)
+	return err
+}
+
+func (repo *WatcherRepositoryImpl) DeleteWatcher(ctx context.Context, todoID, userID int64) (removed bool, err error) {
+	query := "DELETE FROM todo_watchers WHERE todo_id=? AND user_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, todoID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetWatcherByTodoIDs returns the watchers of the given todos in the order
+// they started watching, keyed by todo id.
+func (repo *WatcherRepositoryImpl) GetWatcherByTodoIDs(ctx context.Context, todoIDs []int64) (watchers map[int64][]*entity.TodoWatcher, err error) {
+	watchers = make(map[int64][]*entity.TodoWatcher)
+	if len(todoIDs) == 0 {
+		return watchers, nil
+	}
+	var b query.Builder
+	b.WhereInIDs("w.todo_id", todoIDs)
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+		"SELECT w.todo_id, w.user_id, w.created_at, u.email, u.name FROM todo_watchers w JOIN users u ON u.user_id=w.user_id"+
+			b.String()+" ORDER BY w.created_at, w.user_id", b.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w entity.TodoWatcher
+		if err = rows.Scan(&w.TodoID, &w.UserID, &w.CreatedAt, &w.Email, &w.Name); err != nil {
+			return nil, err
+		}
+		watchers[w.TodoID] = append(watchers[w.TodoID], &w)
+	}
+	return watchers, rows.Err()
+}
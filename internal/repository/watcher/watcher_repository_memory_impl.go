@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type WatcherRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewWatcherMemoryRepository(db *infrastructure.MemoryDatabase) WatcherRepository {
+	return &WatcherRepositoryMemoryImpl{db: db}
+}
+
+func (repo *WatcherRepositoryMemoryImpl) InsertWatcher(ctx context.Context, watcher entity.TodoWatcher) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	watcher.CreatedAt = repo.db.Now()
+	watcher.Email, watcher.Name = "", ""
+	repo.db.TodoWatchers[watcher.Key()] = watcher
+	return nil
+}
+
+func (repo *WatcherRepositoryMemoryImpl) DeleteWatcher(ctx context.Context, todoID, userID int64) (removed bool, err error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	key := entity.WatcherKey{TodoID: todoID, UserID: userID}
+	if _, ok := repo.db.TodoWatchers[key]; !ok {
+		return false, nil
+	}
+	delete(repo.db.TodoWatchers, key)
+	return true, nil
+}
+
+func (repo *WatcherRepositoryMemoryImpl) GetWatcherByTodoIDs(ctx context.Context, todoIDs []int64) (watchers map[int64][]*entity.TodoWatcher, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	wanted := make(map[int64]bool, len(todoIDs))
+	for _, id := range todoIDs {
+		wanted[id] = true
+	}
+	watchers = make(map[int64][]*entity.TodoWatcher)
+	for key := range repo.db.TodoWatchers {
+		if !wanted[key.TodoID] {
+			continue
+		}
+		w := repo.db.TodoWatchers[key]
+		u := repo.db.Users[w.UserID]
+		w.Email, w.Name = u.Email, u.Name
+		watchers[key.TodoID] = append(watchers[key.TodoID], &w)
+	}
+	for _, ws := range watchers {
+		sort.Slice(ws, func(i, j int) bool {
+			if !ws[i].CreatedAt.Equal(ws[j].CreatedAt) {
+				return ws[i].CreatedAt.Before(ws[j].CreatedAt)
+			}
+			return ws[i].UserID < ws[j].UserID
+		})
+	}
+	return watchers, nil
+}
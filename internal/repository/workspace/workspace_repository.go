@@ -0,0 +1,32 @@
+package workspace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+// WorkspaceRepository looks after the workspaces themselves, so unlike the
+// repositories of their data it is not scoped to the workspace of the
+// context.
+type WorkspaceRepository interface {
+	InsertWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error)
+	GetWorkspaceByID(ctx context.Context, id int64) (workspace *entity.Workspace, err error)
+	GetWorkspaceBySlug(ctx context.Context, slug string) (workspace *entity.Workspace, err error)
+	GetWorkspaceByUserID(ctx context.Context, userID int64) (workspaces []*entity.Workspace, err error)
+	UpdateWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error)
+	GetWorkspaceUsage(ctx context.Context, ids []int64) (usage map[int64]entity.WorkspaceUsage, err error)
+	InsertWorkspaceMember(ctx context.Context, member entity.WorkspaceMember) error
+	IsWorkspaceMember(ctx context.Context, workspaceID, userID int64) (bool, error)
+	DeleteWorkspaceMember(ctx context.Context, workspaceID, userID int64) error
+}
+
+// GetWorkspaceByRef finds a workspace by its id or its slug. Slugs start
+// with a letter, so a number is always an id.
+func GetWorkspaceByRef(ctx context.Context, repo WorkspaceRepository, ref string) (*entity.Workspace, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return repo.GetWorkspaceByID(ctx, id)
+	}
+	return repo.GetWorkspaceBySlug(ctx, ref)
+}
@@ -0,0 +1,158 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+const workspaceColumns = "w.workspace_id, w.name, w.slug, w.max_activity_groups, w.max_todos, w.created_at, w.updated_at"
+
+type WorkspaceRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewWorkspaceRepository(db *sql.DB) WorkspaceRepository {
+	return &WorkspaceRepositoryImpl{db: db}
+}
+
+func (repo *WorkspaceRepositoryImpl) InsertWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error) {
+	query := "INSERT INTO workspaces(name, slug, max_activity_groups, max_todos) VALUES(?,?,?,?)"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, workspace.Name, workspace.Slug, workspace.MaxActivityGroups, workspace.MaxTodos)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetWorkspaceByID(ctx, id)
+}
+
+func (repo *WorkspaceRepositoryImpl) GetWorkspaceByID(ctx context.Context, id int64) (workspace *entity.Workspace, err error) {
+	return repo.getWorkspace(ctx, "w.workspace_id=?", id)
+}
+
+func (repo *WorkspaceRepositoryImpl) GetWorkspaceBySlug(ctx context.Context, slug string) (workspace *entity.Workspace, err error) {
+	return repo.getWorkspace(ctx, "w.slug=?", slug)
+}
+
+func (repo *WorkspaceRepositoryImpl) getWorkspace(ctx context.Context, cond string, arg interface{}) (*entity.Workspace, error) {
+	query := "SELECT " + workspaceColumns + " FROM workspaces w WHERE " + cond
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanWorkspace(rows)
+	}
+	return nil, model.ErrWorkspaceNotFound
+}
+
+// GetWorkspaceByUserID returns the workspaces a user is a member of, oldest
+// first.
+func (repo *WorkspaceRepositoryImpl) GetWorkspaceByUserID(ctx context.Context, userID int64) (workspaces []*entity.Workspace, err error) {
+	query := "SELECT " + workspaceColumns + " FROM workspaces w JOIN workspace_members m ON m.workspace_id=w.workspace_id WHERE m.user_id=? ORDER BY w.workspace_id"
+	rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, w)
+	}
+	return workspaces, rows.Err()
+}
+
+func (repo *WorkspaceRepositoryImpl) UpdateWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error) {
+	query := "UPDATE workspaces SET name=?, max_activity_groups=?, max_todos=? WHERE workspace_id=?"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, workspace.Name, workspace.MaxActivityGroups, workspace.MaxTodos, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetWorkspaceByID(ctx, workspace.ID)
+}
+
+// GetWorkspaceUsage counts the activity groups and todos of each workspace,
+// trashed ones included as they count against the quotas.
+func (repo *WorkspaceRepositoryImpl) GetWorkspaceUsage(ctx context.Context, ids []int64) (usage map[int64]entity.WorkspaceUsage, err error) {
+	usage = make(map[int64]entity.WorkspaceUsage, len(ids))
+	if len(ids) == 0 {
+		return usage, nil
+	}
+	for _, table := range []string{"activities", "todos"} {
+		var b query.Builder
+		b.WhereInIDs("workspace_id", ids)
+		rows, err := transaction.GetExecutor(ctx, repo.db).QueryContext(ctx,
+			"SELECT workspace_id, COUNT(*) FROM "+table+b.String()+" GROUP BY workspace_id", b.Args()...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id, count int64
+			if err := rows.Scan(&id, &count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			u := usage[id]
+			if table == "activities" {
+				u.ActivityGroups = count
+			} else {
+				u.Todos = count
+			}
+			usage[id] = u
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return usage, nil
+}
+
+func (repo *WorkspaceRepositoryImpl) InsertWorkspaceMember(ctx context.Context, member entity.WorkspaceMember) error {
+	query := "INSERT INTO workspace_members(workspace_id, user_id) VALUES(?,?)"
+	_, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, member.WorkspaceID, member.UserID)
+	return err
+}
+
+func (repo *WorkspaceRepositoryImpl) IsWorkspaceMember(ctx context.Context, workspaceID, userID int64) (bool, error) {
+	var member bool
+	query := "SELECT COUNT(*)>0 FROM workspace_members WHERE workspace_id=? AND user_id=?"
+	err := transaction.GetExecutor(ctx, repo.db).QueryRowContext(ctx, query, workspaceID, userID).Scan(&member)
+	return member, err
+}
+
+func (repo *WorkspaceRepositoryImpl) DeleteWorkspaceMember(ctx context.Context, workspaceID, userID int64) error {
+	query := "DELETE FROM workspace_members WHERE workspace_id=? AND user_id=?"
+	result, err := transaction.GetExecutor(ctx, repo.db).ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	return nil
+}
+
+func scanWorkspace(rows *sql.Rows) (*entity.Workspace, error) {
+	var w entity.Workspace
+	err := rows.Scan(&w.ID, &w.Name, &w.Slug, &w.MaxActivityGroups, &w.MaxTodos, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
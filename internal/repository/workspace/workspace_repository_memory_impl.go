@@ -0,0 +1,145 @@
+package workspace
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+)
+
+type WorkspaceRepositoryMemoryImpl struct {
+	db *infrastructure.MemoryDatabase
+}
+
+func NewWorkspaceMemoryRepository(db *infrastructure.MemoryDatabase) WorkspaceRepository {
+	return &WorkspaceRepositoryMemoryImpl{db: db}
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) InsertWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	for _, w := range repo.db.Workspaces {
+		if w.Slug == workspace.Slug {
+			return nil, model.ErrSlugTaken
+		}
+	}
+	now := repo.db.Now()
+	workspace.ID = repo.db.NextID(workspace.TableName())
+	workspace.CreatedAt = now
+	workspace.UpdatedAt = now
+	repo.db.Workspaces[workspace.ID] = workspace
+
+	return &workspace, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) GetWorkspaceByID(ctx context.Context, id int64) (workspace *entity.Workspace, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	w, ok := repo.db.Workspaces[id]
+	if !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	return &w, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) GetWorkspaceBySlug(ctx context.Context, slug string) (workspace *entity.Workspace, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for _, w := range repo.db.Workspaces {
+		if w.Slug == slug {
+			return &w, nil
+		}
+	}
+	return nil, model.ErrWorkspaceNotFound
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) GetWorkspaceByUserID(ctx context.Context, userID int64) (workspaces []*entity.Workspace, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	for key := range repo.db.WorkspaceMembers {
+		if key.UserID == userID {
+			w := repo.db.Workspaces[key.WorkspaceID]
+			workspaces = append(workspaces, &w)
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].ID < workspaces[j].ID })
+	return workspaces, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) UpdateWorkspace(ctx context.Context, workspace entity.Workspace) (*entity.Workspace, error) {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	w, ok := repo.db.Workspaces[workspace.ID]
+	if !ok {
+		return nil, model.ErrWorkspaceNotFound
+	}
+	w.Name = workspace.Name
+	w.MaxActivityGroups = workspace.MaxActivityGroups
+	w.MaxTodos = workspace.MaxTodos
+	w.UpdatedAt = repo.db.Now()
+	repo.db.Workspaces[w.ID] = w
+
+	return &w, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) GetWorkspaceUsage(ctx context.Context, ids []int64) (usage map[int64]entity.WorkspaceUsage, err error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	usage = make(map[int64]entity.WorkspaceUsage, len(ids))
+	for _, id := range ids {
+		usage[id] = entity.WorkspaceUsage{}
+	}
+	for _, a := range repo.db.Activities {
+		if u, ok := usage[a.WorkspaceID]; ok {
+			u.ActivityGroups++
+			usage[a.WorkspaceID] = u
+		}
+	}
+	for _, t := range repo.db.Todos {
+		if u, ok := usage[t.WorkspaceID]; ok {
+			u.Todos++
+			usage[t.WorkspaceID] = u
+		}
+	}
+	return usage, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) InsertWorkspaceMember(ctx context.Context, member entity.WorkspaceMember) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	if _, ok := repo.db.WorkspaceMembers[member.Key()]; ok {
+		return model.ErrAlreadyWorkspaceMember
+	}
+	member.CreatedAt = repo.db.Now()
+	repo.db.WorkspaceMembers[member.Key()] = member
+	return nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) IsWorkspaceMember(ctx context.Context, workspaceID, userID int64) (bool, error) {
+	unlock := repo.db.RLock(ctx)
+	defer unlock()
+
+	_, ok := repo.db.WorkspaceMembers[entity.WorkspaceMemberKey{WorkspaceID: workspaceID, UserID: userID}]
+	return ok, nil
+}
+
+func (repo *WorkspaceRepositoryMemoryImpl) DeleteWorkspaceMember(ctx context.Context, workspaceID, userID int64) error {
+	unlock := repo.db.Lock(ctx)
+	defer unlock()
+
+	key := entity.WorkspaceMemberKey{WorkspaceID: workspaceID, UserID: userID}
+	if _, ok := repo.db.WorkspaceMembers[key]; !ok {
+		return model.ErrMemberNotFound.WithMessage("Member with user ID %v Not Found", userID)
+	}
+	delete(repo.db.WorkspaceMembers, key)
+	return nil
+}
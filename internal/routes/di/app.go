@@ -0,0 +1,13 @@
+package di
+
+import (
+	"github.com/vnnyx/golang-todo-api/internal/routes"
+	"github.com/vnnyx/golang-todo-api/internal/worker"
+)
+
+// App bundles everything the server needs so that the HTTP routes and the
+// background workers share a single set of repositories.
+type App struct {
+	Route       *routes.Route
+	TrashPurger *worker.TrashPurger
+}
@@ -8,26 +8,100 @@ import (
 	"github.com/google/wire"
 	"github.com/patrickmn/go-cache"
 	activityController "github.com/vnnyx/golang-todo-api/internal/controller/activity"
+	authController "github.com/vnnyx/golang-todo-api/internal/controller/auth"
+	calendarController "github.com/vnnyx/golang-todo-api/internal/controller/calendar"
+	searchController "github.com/vnnyx/golang-todo-api/internal/controller/search"
+	tagController "github.com/vnnyx/golang-todo-api/internal/controller/tag"
 	todoController "github.com/vnnyx/golang-todo-api/internal/controller/todo"
+	transferController "github.com/vnnyx/golang-todo-api/internal/controller/transfer"
+	trashController "github.com/vnnyx/golang-todo-api/internal/controller/trash"
+	workspaceController "github.com/vnnyx/golang-todo-api/internal/controller/workspace"
 	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
-	activityRepo "github.com/vnnyx/golang-todo-api/internal/repository/activity"
-	todoRepo "github.com/vnnyx/golang-todo-api/internal/repository/todo"
 	"github.com/vnnyx/golang-todo-api/internal/routes"
 	activityUC "github.com/vnnyx/golang-todo-api/internal/usecase/activity"
+	authUC "github.com/vnnyx/golang-todo-api/internal/usecase/auth"
+	calendarUC "github.com/vnnyx/golang-todo-api/internal/usecase/calendar"
+	searchUC "github.com/vnnyx/golang-todo-api/internal/usecase/search"
+	tagUC "github.com/vnnyx/golang-todo-api/internal/usecase/tag"
 	todoUC "github.com/vnnyx/golang-todo-api/internal/usecase/todo"
+	transferUC "github.com/vnnyx/golang-todo-api/internal/usecase/transfer"
+	trashUC "github.com/vnnyx/golang-todo-api/internal/usecase/trash"
+	workspaceUC "github.com/vnnyx/golang-todo-api/internal/usecase/workspace"
+	"github.com/vnnyx/golang-todo-api/internal/worker"
 )
 
-func InitializeRoute(configName string, e *fiber.App, c *cache.Cache) *routes.Route {
+func InitializeApp(configName string, e *fiber.App, c *cache.Cache) *App {
 	wire.Build(
 		infrastructure.NewConfig,
-		infrastructure.NewMySQLDatabase,
-		activityRepo.NewActivityRepository,
-		todoRepo.NewTodoRepository,
+		infrastructure.NewDatabase,
+		infrastructure.NewMemoryDatabase,
+		provideActivityRepository,
+		provideTodoRepository,
+		provideEventRepository,
+		provideTagRepository,
+		provideCalendarRepository,
+		provideUserRepository,
+		provideAPIKeyRepository,
+		provideMemberRepository,
+		provideWorkspaceRepository,
+		provideWatcherRepository,
+		provideCommentRepository,
+		provideTxManager,
 		activityUC.NewActivityUC,
 		todoUC.NewTodoUC,
+		trashUC.NewTrashUC,
+		tagUC.NewTagUC,
+		transferUC.NewTransferUC,
+		calendarUC.NewCalendarUC,
+		searchUC.NewSearchUC,
+		authUC.NewAuthUC,
+		workspaceUC.NewWorkspaceUC,
 		activityController.NewActivityController,
 		todoController.NewTodoController,
+		trashController.NewTrashController,
+		tagController.NewTagController,
+		transferController.NewTransferController,
+		calendarController.NewCalendarController,
+		searchController.NewSearchController,
+		authController.NewAuthController,
+		workspaceController.NewWorkspaceController,
 		routes.NewRoute,
+		worker.NewTrashPurger,
+		wire.Struct(new(App), "*"),
+	)
+	return nil
+}
+
+// InitializeTransferUC serves the import and export commands, which work on
+// the configured database without starting the server.
+func InitializeTransferUC(configName string) transferUC.TransferUC {
+	wire.Build(
+		infrastructure.NewConfig,
+		infrastructure.NewDatabase,
+		infrastructure.NewMemoryDatabase,
+		provideActivityRepository,
+		provideTodoRepository,
+		provideEventRepository,
+		provideTagRepository,
+		provideMemberRepository,
+		provideCommentRepository,
+		provideTxManager,
+		transferUC.NewTransferUC,
+	)
+	return nil
+}
+
+// InitializeWorkspaceUC serves the workspace command, which manages
+// workspaces on the configured database without starting the server.
+func InitializeWorkspaceUC(configName string) workspaceUC.WorkspaceUC {
+	wire.Build(
+		infrastructure.NewConfig,
+		infrastructure.NewDatabase,
+		infrastructure.NewMemoryDatabase,
+		provideUserRepository,
+		provideWorkspaceRepository,
+		provideTxManager,
+		workspaceUC.NewWorkspaceUC,
 	)
 	return nil
 }
@@ -0,0 +1,107 @@
+package di
+
+import (
+	"database/sql"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	activityRepo "github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	apiKeyRepo "github.com/vnnyx/golang-todo-api/internal/repository/apikey"
+	calendarRepo "github.com/vnnyx/golang-todo-api/internal/repository/calendar"
+	commentRepo "github.com/vnnyx/golang-todo-api/internal/repository/comment"
+	eventRepo "github.com/vnnyx/golang-todo-api/internal/repository/event"
+	memberRepo "github.com/vnnyx/golang-todo-api/internal/repository/member"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	tagRepo "github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	todoRepo "github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	userRepo "github.com/vnnyx/golang-todo-api/internal/repository/user"
+	watcherRepo "github.com/vnnyx/golang-todo-api/internal/repository/watcher"
+	workspaceRepo "github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+)
+
+// The SQL repositories serve both the MySQL and SQLite drivers; only the
+// memory driver needs its own implementations.
+
+func provideActivityRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) activityRepo.ActivityRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return activityRepo.NewActivityMemoryRepository(memDB)
+	}
+	return activityRepo.NewActivityRepository(db, query.Dialect(cfg.StorageDriver))
+}
+
+func provideTodoRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) todoRepo.TodoRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return todoRepo.NewTodoMemoryRepository(memDB)
+	}
+	return todoRepo.NewTodoRepository(db, query.Dialect(cfg.StorageDriver))
+}
+
+func provideEventRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) eventRepo.EventRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return eventRepo.NewEventMemoryRepository(memDB)
+	}
+	return eventRepo.NewEventRepository(db)
+}
+
+func provideTagRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) tagRepo.TagRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return tagRepo.NewTagMemoryRepository(memDB)
+	}
+	return tagRepo.NewTagRepository(db)
+}
+
+func provideCalendarRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) calendarRepo.CalendarRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return calendarRepo.NewCalendarMemoryRepository(memDB)
+	}
+	return calendarRepo.NewCalendarRepository(db)
+}
+
+func provideUserRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) userRepo.UserRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return userRepo.NewUserMemoryRepository(memDB)
+	}
+	return userRepo.NewUserRepository(db)
+}
+
+func provideAPIKeyRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) apiKeyRepo.APIKeyRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return apiKeyRepo.NewAPIKeyMemoryRepository(memDB)
+	}
+	return apiKeyRepo.NewAPIKeyRepository(db)
+}
+
+func provideMemberRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) memberRepo.MemberRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return memberRepo.NewMemberMemoryRepository(memDB)
+	}
+	return memberRepo.NewMemberRepository(db)
+}
+
+func provideWatcherRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) watcherRepo.WatcherRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return watcherRepo.NewWatcherMemoryRepository(memDB)
+	}
+	return watcherRepo.NewWatcherRepository(db)
+}
+
+func provideCommentRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) commentRepo.CommentRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return commentRepo.NewCommentMemoryRepository(memDB)
+	}
+	return commentRepo.NewCommentRepository(db)
+}
+
+func provideWorkspaceRepository(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) workspaceRepo.WorkspaceRepository {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return workspaceRepo.NewWorkspaceMemoryRepository(memDB)
+	}
+	return workspaceRepo.NewWorkspaceRepository(db)
+}
+
+func provideTxManager(cfg *infrastructure.Config, db *sql.DB, memDB *infrastructure.MemoryDatabase) transaction.TxManager {
+	if cfg.StorageDriver == infrastructure.StorageDriverMemory {
+		return transaction.NewMemoryTxManager(memDB)
+	}
+	return transaction.NewTxManager(db)
+}
@@ -9,27 +9,100 @@ package di
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/patrickmn/go-cache"
-	activity3 "github.com/vnnyx/golang-todo-api/internal/controller/activity"
-	todo3 "github.com/vnnyx/golang-todo-api/internal/controller/todo"
+	activity2 "github.com/vnnyx/golang-todo-api/internal/controller/activity"
+	auth2 "github.com/vnnyx/golang-todo-api/internal/controller/auth"
+	calendar2 "github.com/vnnyx/golang-todo-api/internal/controller/calendar"
+	search2 "github.com/vnnyx/golang-todo-api/internal/controller/search"
+	tag2 "github.com/vnnyx/golang-todo-api/internal/controller/tag"
+	todo2 "github.com/vnnyx/golang-todo-api/internal/controller/todo"
+	transfer2 "github.com/vnnyx/golang-todo-api/internal/controller/transfer"
+	trash2 "github.com/vnnyx/golang-todo-api/internal/controller/trash"
+	workspace2 "github.com/vnnyx/golang-todo-api/internal/controller/workspace"
 	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
-	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
-	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
 	"github.com/vnnyx/golang-todo-api/internal/routes"
-	activity2 "github.com/vnnyx/golang-todo-api/internal/usecase/activity"
-	todo2 "github.com/vnnyx/golang-todo-api/internal/usecase/todo"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/activity"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/auth"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/calendar"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/search"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/tag"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/todo"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/transfer"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/trash"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/workspace"
+	"github.com/vnnyx/golang-todo-api/internal/worker"
 )
 
 // Injectors from injector.go:
 
-func InitializeRoute(configName string, e *fiber.App, c *cache.Cache) *routes.Route {
+func InitializeApp(configName string, e *fiber.App, c *cache.Cache) *App {
 	config := infrastructure.NewConfig(configName)
-	db := infrastructure.NewMySQLDatabase(config)
-	activityRepository := activity.NewActivityRepository(db)
-	activityUC := activity2.NewActivityUC(activityRepository)
-	activityController := activity3.NewActivityController(activityUC, c)
-	todoRepository := todo.NewTodoRepository(db)
-	todoUC := todo2.NewTodoUC(todoRepository)
-	todoController := todo3.NewTodoController(todoUC, c)
-	route := routes.NewRoute(activityController, todoController, e)
-	return route
+	db := infrastructure.NewDatabase(config)
+	memoryDatabase := infrastructure.NewMemoryDatabase()
+	activityRepository := provideActivityRepository(config, db, memoryDatabase)
+	todoRepository := provideTodoRepository(config, db, memoryDatabase)
+	eventRepository := provideEventRepository(config, db, memoryDatabase)
+	memberRepository := provideMemberRepository(config, db, memoryDatabase)
+	userRepository := provideUserRepository(config, db, memoryDatabase)
+	workspaceRepository := provideWorkspaceRepository(config, db, memoryDatabase)
+	tagRepository := provideTagRepository(config, db, memoryDatabase)
+	watcherRepository := provideWatcherRepository(config, db, memoryDatabase)
+	commentRepository := provideCommentRepository(config, db, memoryDatabase)
+	txManager := provideTxManager(config, db, memoryDatabase)
+	todoUC := todo.NewTodoUC(todoRepository, activityRepository, eventRepository, tagRepository, memberRepository, watcherRepository, commentRepository, txManager)
+	activityUC := activity.NewActivityUC(activityRepository, todoRepository, eventRepository, memberRepository, userRepository, workspaceRepository, todoUC, txManager)
+	activityController := activity2.NewActivityController(activityUC, c)
+	todoController := todo2.NewTodoController(todoUC, c)
+	trashUC := trash.NewTrashUC(activityRepository, todoRepository, txManager)
+	trashController := trash2.NewTrashController(trashUC)
+	tagUC := tag.NewTagUC(tagRepository, txManager)
+	tagController := tag2.NewTagController(tagUC, c)
+	transferUC := transfer.NewTransferUC(activityRepository, todoRepository, eventRepository, tagRepository, memberRepository, commentRepository, txManager)
+	transferController := transfer2.NewTransferController(transferUC, c)
+	calendarRepository := provideCalendarRepository(config, db, memoryDatabase)
+	calendarUC := calendar.NewCalendarUC(calendarRepository, activityRepository, todoRepository, tagRepository, workspaceRepository, txManager)
+	calendarController := calendar2.NewCalendarController(calendarUC)
+	searchUC := search.NewSearchUC(activityRepository, todoRepository, tagRepository)
+	searchController := search2.NewSearchController(searchUC)
+	apiKeyRepository := provideAPIKeyRepository(config, db, memoryDatabase)
+	authUC := auth.NewAuthUC(config, userRepository, apiKeyRepository, workspaceRepository, txManager)
+	authController := auth2.NewAuthController(authUC)
+	workspaceUC := workspace.NewWorkspaceUC(config, workspaceRepository, userRepository, txManager)
+	workspaceController := workspace2.NewWorkspaceController(workspaceUC)
+	route := routes.NewRoute(config, activityController, todoController, trashController, tagController, transferController, calendarController, searchController, authController, workspaceController, authUC, e)
+	trashPurger := worker.NewTrashPurger(config, trashUC)
+	app := &App{
+		Route:       route,
+		TrashPurger: trashPurger,
+	}
+	return app
+}
+
+// InitializeTransferUC serves the import and export commands, which work on
+// the configured database without starting the server.
+func InitializeTransferUC(configName string) transfer.TransferUC {
+	config := infrastructure.NewConfig(configName)
+	db := infrastructure.NewDatabase(config)
+	memoryDatabase := infrastructure.NewMemoryDatabase()
+	activityRepository := provideActivityRepository(config, db, memoryDatabase)
+	todoRepository := provideTodoRepository(config, db, memoryDatabase)
+	eventRepository := provideEventRepository(config, db, memoryDatabase)
+	tagRepository := provideTagRepository(config, db, memoryDatabase)
+	memberRepository := provideMemberRepository(config, db, memoryDatabase)
+	commentRepository := provideCommentRepository(config, db, memoryDatabase)
+	txManager := provideTxManager(config, db, memoryDatabase)
+	transferUC := transfer.NewTransferUC(activityRepository, todoRepository, eventRepository, tagRepository, memberRepository, commentRepository, txManager)
+	return transferUC
+}
+
+// InitializeWorkspaceUC serves the workspace command, which manages
+// workspaces on the configured database without starting the server.
+func InitializeWorkspaceUC(configName string) workspace.WorkspaceUC {
+	config := infrastructure.NewConfig(configName)
+	db := infrastructure.NewDatabase(config)
+	memoryDatabase := infrastructure.NewMemoryDatabase()
+	workspaceRepository := provideWorkspaceRepository(config, db, memoryDatabase)
+	userRepository := provideUserRepository(config, db, memoryDatabase)
+	txManager := provideTxManager(config, db, memoryDatabase)
+	workspaceUC := workspace.NewWorkspaceUC(config, workspaceRepository, userRepository, txManager)
+	return workspaceUC
 }
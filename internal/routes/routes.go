@@ -1,37 +1,137 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/vnnyx/golang-todo-api/internal/controller/activity"
+	"github.com/vnnyx/golang-todo-api/internal/controller/auth"
+	"github.com/vnnyx/golang-todo-api/internal/controller/calendar"
+	"github.com/vnnyx/golang-todo-api/internal/controller/search"
+	"github.com/vnnyx/golang-todo-api/internal/controller/tag"
 	"github.com/vnnyx/golang-todo-api/internal/controller/todo"
+	"github.com/vnnyx/golang-todo-api/internal/controller/transfer"
+	"github.com/vnnyx/golang-todo-api/internal/controller/trash"
+	"github.com/vnnyx/golang-todo-api/internal/controller/workspace"
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/middleware"
+	authUC "github.com/vnnyx/golang-todo-api/internal/usecase/auth"
 )
 
 type Route struct {
-	activityController activity.ActivityController
-	todoController     todo.TodoController
-	route              *fiber.App
+	cfg                 *infrastructure.Config
+	activityController  activity.ActivityController
+	todoController      todo.TodoController
+	trashController     trash.TrashController
+	tagController       tag.TagController
+	transferController  transfer.TransferController
+	calendarController  calendar.CalendarController
+	searchController    search.SearchController
+	authController      auth.AuthController
+	workspaceController workspace.WorkspaceController
+	authUC              authUC.AuthUC
+	route               *fiber.App
 }
 
-func NewRoute(activityController activity.ActivityController, todoController todo.TodoController, route *fiber.App) *Route {
+func NewRoute(cfg *infrastructure.Config, activityController activity.ActivityController, todoController todo.TodoController, trashController trash.TrashController, tagController tag.TagController, transferController transfer.TransferController, calendarController calendar.CalendarController, searchController search.SearchController, authController auth.AuthController, workspaceController workspace.WorkspaceController, authUC authUC.AuthUC, route *fiber.App) *Route {
 	return &Route{
-		activityController: activityController,
-		todoController:     todoController,
-		route:              route,
+		cfg:                 cfg,
+		activityController:  activityController,
+		todoController:      todoController,
+		trashController:     trashController,
+		tagController:       tagController,
+		transferController:  transferController,
+		calendarController:  calendarController,
+		searchController:    searchController,
+		authController:      authController,
+		workspaceController: workspaceController,
+		authUC:              authUC,
+		route:               route,
 	}
 }
 
 func (r *Route) InitRoute() {
+	r.route.Use(middleware.RequestContext(time.Duration(r.cfg.RequestTimeoutSecond) * time.Second))
+	r.route.Use(middleware.Actor())
+
+	// Fiber runs handlers in the order they are registered, so the routes
+	// above the Auth middleware are the public ones.
+	authGroup := r.route.Group("/auth")
+	authGroup.Post("/register", r.authController.Register)
+	authGroup.Post("/login", r.authController.Login)
+	authGroup.Post("/refresh", r.authController.Refresh)
+
+	// Calendar apps cannot log in, feeds are read with their own token.
 	activity := r.route.Group("/activity-groups")
+	activity.Get("/:id/calendar.ics", r.calendarController.GetCalendar)
+	r.route.Get("/calendar.ics", r.calendarController.GetAllCalendar)
+
+	r.route.Use(middleware.Auth(r.authUC))
+
+	authGroup.Get("/me", r.authController.GetCurrentUser)
+	authGroup.Post("/api-keys", r.authController.InsertAPIKey)
+	authGroup.Get("/api-keys", r.authController.GetAllAPIKey)
+	authGroup.Delete("/api-keys/:id", r.authController.DeleteAPIKey)
+
+	r.route.Get("/workspaces", r.workspaceController.GetAllWorkspace)
+
 	activity.Post("", r.activityController.InsertActivity)
+	activity.Post("/import", r.transferController.ImportActivity)
 	activity.Get("/:id", r.activityController.GetActivityByID)
 	activity.Get("", r.activityController.GetAllActivity)
 	activity.Patch("/:id", r.activityController.UpdateActivity)
 	activity.Delete("/:id", r.activityController.DeleteActivity)
+	activity.Post("/:id/restore", r.activityController.RestoreActivity)
+	activity.Get("/:id/history", r.activityController.GetActivityHistory)
+	activity.Get("/:id/workflow", r.activityController.GetActivityWorkflow)
+	activity.Put("/:id/workflow", r.activityController.UpdateActivityWorkflow)
+	activity.Get("/:id/members", r.activityController.GetAllMember)
+	activity.Post("/:id/members", r.activityController.InsertMember)
+	activity.Patch("/:id/members/:userId", r.activityController.UpdateMember)
+	activity.Delete("/:id/members/:userId", r.activityController.DeleteMember)
+	activity.Put("/:id/owner", r.activityController.TransferOwnership)
+	activity.Get("/:id/export", r.transferController.ExportActivity)
+	activity.Post("/:id/import", r.transferController.ImportTodos)
+	activity.Post("/:id/calendar-feed", r.calendarController.InsertCalendarFeed)
+	activity.Delete("/:id/calendar-feed", r.calendarController.DeleteCalendarFeed)
 
 	todo := r.route.Group("/todo-items")
 	todo.Post("", r.todoController.InsertTodo)
+	todo.Post("/bulk", r.todoController.BulkTodo)
+	todo.Get("/today", r.todoController.GetTodayTodo)
+	todo.Get("/upcoming", r.todoController.GetUpcomingTodo)
+	todo.Get("/overdue", r.todoController.GetOverdueTodo)
+	todo.Get("/mine", r.todoController.GetMyTodo)
 	todo.Get("/:id", r.todoController.GetTodoByID)
 	todo.Get("", r.todoController.GetAllTodo)
 	todo.Patch("/:id", r.todoController.UpdateTodo)
+	todo.Post("/:id/move", r.todoController.MoveTodo)
 	todo.Delete("/:id", r.todoController.DeleteTodo)
+	todo.Post("/:id/restore", r.todoController.RestoreTodo)
+	todo.Get("/:id/history", r.todoController.GetTodoHistory)
+	todo.Get("/:id/transitions", r.todoController.GetTodoTransitions)
+	todo.Post("/:id/subtasks", r.todoController.InsertSubtask)
+	todo.Get("/:id/subtasks", r.todoController.GetSubtasks)
+	todo.Put("/:id/tags/:tagId", r.todoController.AddTodoTag)
+	todo.Delete("/:id/tags/:tagId", r.todoController.RemoveTodoTag)
+	todo.Put("/:id/watchers/:userId", r.todoController.AddTodoWatcher)
+	todo.Delete("/:id/watchers/:userId", r.todoController.RemoveTodoWatcher)
+	todo.Post("/:id/comments", r.todoController.InsertComment)
+	todo.Get("/:id/comments", r.todoController.GetComments)
+	todo.Patch("/:id/comments/:commentId", r.todoController.UpdateComment)
+	todo.Delete("/:id/comments/:commentId", r.todoController.DeleteComment)
+
+	tag := r.route.Group("/tags")
+	tag.Post("", r.tagController.InsertTag)
+	tag.Get("/:id", r.tagController.GetTagByID)
+	tag.Get("", r.tagController.GetAllTag)
+	tag.Patch("/:id", r.tagController.UpdateTag)
+	tag.Delete("/:id", r.tagController.DeleteTag)
+
+	r.route.Get("/trash", r.trashController.GetTrash)
+
+	r.route.Get("/search", r.searchController.Search)
+
+	r.route.Post("/calendar-feed", r.calendarController.InsertAllCalendarFeed)
+	r.route.Delete("/calendar-feed", r.calendarController.DeleteAllCalendarFeed)
 }
@@ -0,0 +1,349 @@
+// Package search ranks short texts, such as titles, against a free text
+// query. It needs no index: the texts are tokenized when they are ranked.
+// The memory driver searches with it; the SQL drivers match through their
+// full-text index, exact words and prefixes only, and use it to highlight
+// what they found.
+//
+// Every term of a query has to match a word of a text, in one of three
+// ways, from best to worst:
+//
+//	exact   the word is the term
+//	prefix  the word starts with the term, as when the query is still typed
+//	fuzzy   the word is a few typos away from the term: one edit for terms of
+//	        four to seven letters, two for longer ones
+//
+// Texts are scored by how well and how rare the matched words are, with a
+// bonus when terms match adjacent words in the order of the query.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.7
+	fuzzyWeight  = 0.5
+
+	// phraseBonus is added per pair of terms matching adjacent words.
+	phraseBonus = 0.5
+)
+
+// Query is a parsed search query.
+type Query struct {
+	terms []string
+}
+
+// ParseQuery splits q into terms. Words are compared without regard to
+// case; everything but letters and digits separates them.
+func ParseQuery(q string) Query {
+	var query Query
+	seen := make(map[string]bool)
+	for _, w := range tokenize(q) {
+		if !seen[w.text] {
+			seen[w.text] = true
+			query.terms = append(query.terms, w.text)
+		}
+	}
+	return query
+}
+
+// Empty reports whether the query has no terms.
+func (q Query) Empty() bool {
+	return len(q.terms) == 0
+}
+
+// Terms returns the distinct lower case words of the query, made of letters
+// and digits only.
+func (q Query) Terms() []string {
+	return q.terms
+}
+
+// ScoreScale is how many units of a score there are in one point, for those
+// who keep scores as integers to sort and page by them exactly. SQLite scores
+// a word found in most titles in millionths.
+const ScoreScale = 1000000
+
+// Match is the byte range of a matched word in a text.
+type Match struct {
+	Start int
+	End   int
+}
+
+// Result is a text matching a query. Index is its position among the texts
+// that were ranked.
+type Result struct {
+	Index   int
+	Score   float64
+	Matches []Match
+}
+
+type word struct {
+	text       string
+	start, end int
+}
+
+// termMatch is the best match of a term within a text.
+type termMatch struct {
+	word   int
+	weight float64
+}
+
+// Rank returns the texts matching every term of q, best first. Texts with
+// the same score keep their order.
+func Rank(q Query, texts []string) []Result {
+	if q.Empty() {
+		return nil
+	}
+
+	type candidate struct {
+		index   int
+		words   []word
+		matches []termMatch
+	}
+	var candidates []candidate
+	df := make([]int, len(q.terms))
+	for i, text := range texts {
+		words := tokenize(text)
+		matches := make([]termMatch, len(q.terms))
+		all := true
+		for t, term := range q.terms {
+			matches[t] = bestMatch(term, words)
+			if matches[t].weight == 0 {
+				all = false
+				continue
+			}
+			df[t]++
+		}
+		if all {
+			candidates = append(candidates, candidate{index: i, words: words, matches: matches})
+		}
+	}
+
+	n := float64(len(texts))
+	idf := make([]float64, len(q.terms))
+	for t := range q.terms {
+		idf[t] = math.Log(1 + (n-float64(df[t])+0.5)/(float64(df[t])+0.5))
+	}
+
+	results := make([]Result, 0, len(candidates))
+	for _, c := range candidates {
+		var score float64
+		for t, m := range c.matches {
+			score += idf[t] * m.weight
+			if t > 0 && m.word == c.matches[t-1].word+1 {
+				score += phraseBonus * idf[t]
+			}
+		}
+		// Of two texts matching equally well, the shorter one is closer to
+		// what was asked for.
+		score /= 1 + 0.02*float64(len(c.words))
+
+		results = append(results, Result{Index: c.index, Score: math.Round(score*1000) / 1000, Matches: spans(c.words, c.matches)})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+// Matches returns where the terms of q match words of text, for texts found
+// some other way, such as through a full-text index. Terms matching no word
+// are left out.
+func Matches(q Query, text string) []Match {
+	words := tokenize(text)
+	var matches []termMatch
+	for _, term := range q.terms {
+		if m := bestMatch(term, words); m.weight > 0 {
+			matches = append(matches, m)
+		}
+	}
+	return spans(words, matches)
+}
+
+// spans returns the byte ranges of the matched words, in text order.
+func spans(words []word, matches []termMatch) []Match {
+	matched := make(map[int]bool)
+	var res []Match
+	for _, m := range matches {
+		if !matched[m.word] {
+			matched[m.word] = true
+			res = append(res, Match{Start: words[m.word].start, End: words[m.word].end})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Start < res[j].Start })
+	return res
+}
+
+// bestMatch finds the word matching term best; a zero weight means none
+// does.
+func bestMatch(term string, words []word) termMatch {
+	best := termMatch{}
+	for i, w := range words {
+		var weight float64
+		switch {
+		case w.text == term:
+			weight = exactWeight
+		case strings.HasPrefix(w.text, term) && utf8.RuneCountInString(term) >= 2:
+			// The more of the word the term covers, the better.
+			weight = prefixWeight * (0.5 + 0.5*float64(len(term))/float64(len(w.text)))
+		default:
+			limit := maxEdits(term)
+			if limit == 0 {
+				continue
+			}
+			if d := distance(term, w.text, limit); d <= limit {
+				weight = fuzzyWeight / float64(d)
+			}
+		}
+		if weight > best.weight {
+			best = termMatch{word: i, weight: weight}
+		}
+	}
+	return best
+}
+
+func maxEdits(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b, so a
+// swap of two adjacent letters counts as one edit. It gives up with
+// limit+1 once the distance is known to exceed limit.
+func distance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// tokenize splits text into lower case words of letters and digits,
+// keeping where each word is in text.
+func tokenize(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			words = append(words, word{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// Highlight returns text as HTML with the matches wrapped in <mark>. Texts
+// longer than width bytes are cut to a fragment around the first match,
+// with an ellipsis marking each cut.
+func Highlight(text string, matches []Match, width int) string {
+	from, to := 0, len(text)
+	if width > 0 && len(text) > width && len(matches) > 0 {
+		from = matches[0].Start - width/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + width
+		if to > len(text) {
+			to = len(text)
+			from = to - width
+		}
+		from, to = wordBoundary(text, from, -1), wordBoundary(text, to, 1)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range matches {
+		if m.Start < pos || m.End > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m.Start]))
+		b.WriteString("<mark>" + html.EscapeString(text[m.Start:m.End]) + "</mark>")
+		pos = m.End
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// wordBoundary moves i in direction dir until it no longer splits a word or
+// a UTF-8 sequence.
+func wordBoundary(text string, i, dir int) int {
+	for i > 0 && i < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		if utf8.RuneStart(text[i]) && !(isWordRune(r) && isWordRune(prev)) {
+			break
+		}
+		i += dir
+	}
+	return i
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,147 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"title", "priority", "done", "status", "start_at", "due_at", "recurrence", "tags", "parent"}
+
+// csvTagSeparator separates the tags of a todo within the tags column.
+const csvTagSeparator = ";"
+
+func encodeCSV(w io.Writer, list *List) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	row := 0
+	write := func(item *Item, parent int) error {
+		row++
+		record := []string{
+			item.Title,
+			item.Priority,
+			strconv.FormatBool(item.Done),
+			item.Status,
+			formatCSVTime(item.StartAt),
+			formatCSVTime(item.DueAt),
+			"",
+			strings.Join(item.Tags, csvTagSeparator),
+			"",
+		}
+		if item.Recurrence != nil {
+			record[6] = *item.Recurrence
+		}
+		if parent > 0 {
+			record[8] = strconv.Itoa(parent)
+		}
+		return cw.Write(record)
+	}
+	for _, item := range list.Todos {
+		if err := write(item, 0); err != nil {
+			return err
+		}
+		parent := row
+		for _, sub := range item.Subtasks {
+			if err := write(sub, parent); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// decodeCSV reads rows by the names in the header, so columns may come in
+// any order and only title is required. Unknown columns are ignored. Rows
+// are numbered from 1, not counting the header.
+func decodeCSV(r io.Reader) (*List, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the header row is missing")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("the header row has no title column")
+	}
+
+	list := &List{}
+	var rows []*Item
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := &Item{
+			Title:    get("title"),
+			Priority: get("priority"),
+			Status:   get("status"),
+		}
+		if done := get("done"); done != "" {
+			if item.Done, err = strconv.ParseBool(done); err != nil {
+				return nil, fmt.Errorf("row %d: done must be true or false", row)
+			}
+		}
+		for _, d := range []struct {
+			column string
+			dest   **time.Time
+		}{{"start_at", &item.StartAt}, {"due_at", &item.DueAt}} {
+			if v := get(d.column); v != "" {
+				if *d.dest, err = parseDate(v); err != nil {
+					return nil, fmt.Errorf("row %d: %s: %v", row, d.column, err)
+				}
+			}
+		}
+		if rule := get("recurrence"); rule != "" {
+			item.Recurrence = &rule
+		}
+		for _, tag := range strings.Split(get("tags"), csvTagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+
+		rows = append(rows, item)
+		parent := get("parent")
+		if parent == "" {
+			list.Todos = append(list.Todos, item)
+			continue
+		}
+		n, err := strconv.Atoi(parent)
+		if err != nil || n < 1 || n >= len(rows) {
+			return nil, fmt.Errorf("row %d: parent must be the number of an earlier row", row)
+		}
+		rows[n-1].Subtasks = append(rows[n-1].Subtasks, item)
+	}
+	return list, nil
+}
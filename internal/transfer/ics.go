@@ -0,0 +1,131 @@
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/ical"
+)
+
+// icsPriority maps an RFC 5545 PRIORITY, 1 the highest and 9 the lowest, to
+// a priority; 0 means undefined and reads as the default.
+func icsPriority(value string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case err != nil || n < 0 || n > 9:
+		return "", fmt.Errorf("PRIORITY must be a number from 0 to 9")
+	case n == 0:
+		return "", nil
+	case n <= 2:
+		return "very-high", nil
+	case n <= 4:
+		return "high", nil
+	case n == 5:
+		return "normal", nil
+	case n <= 7:
+		return "low", nil
+	default:
+		return "very-low", nil
+	}
+}
+
+// decodeICS reads the VTODO components of a calendar; other components are
+// ignored. The calendar name becomes the title and RELATED-TO links a
+// subtask to its parent. COMPLETED and CANCELLED todos are done.
+func decodeICS(r io.Reader) (*List, error) {
+	cal, err := ical.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iCalendar: %v", err)
+	}
+	if cal.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("invalid iCalendar: expected a VCALENDAR, not a %s", cal.Name)
+	}
+
+	list := &List{}
+	if p := cal.Get("X-WR-CALNAME"); p != nil {
+		list.Title = ical.UnescapeText(p.Value)
+	}
+	todos := cal.Children("VTODO")
+	items := make([]*Item, len(todos))
+	byUID := make(map[string]*Item, len(todos))
+	for i, c := range todos {
+		item, err := decodeVTodo(c)
+		if err != nil {
+			return nil, fmt.Errorf("VTODO %d: %v", i+1, err)
+		}
+		items[i] = item
+		if p := c.Get("UID"); p != nil {
+			byUID[p.Value] = item
+		}
+	}
+
+	for i, c := range todos {
+		if parent := icsParent(c); parent != nil && byUID[*parent] != nil && byUID[*parent] != items[i] {
+			byUID[*parent].Subtasks = append(byUID[*parent].Subtasks, items[i])
+			continue
+		}
+		list.Todos = append(list.Todos, items[i])
+	}
+	return list, nil
+}
+
+func decodeVTodo(c *ical.Component) (*Item, error) {
+	item := &Item{}
+	if p := c.Get("SUMMARY"); p != nil {
+		item.Title = strings.TrimSpace(ical.UnescapeText(p.Value))
+	}
+	if item.Title == "" {
+		return nil, fmt.Errorf("SUMMARY is missing")
+	}
+
+	var err error
+	if p := c.Get("PRIORITY"); p != nil {
+		if item.Priority, err = icsPriority(p.Value); err != nil {
+			return nil, err
+		}
+	}
+	if p := c.Get("STATUS"); p != nil {
+		status := strings.ToUpper(strings.TrimSpace(p.Value))
+		item.Done = status == "COMPLETED" || status == "CANCELLED"
+	}
+	if c.Get("COMPLETED") != nil {
+		item.Done = true
+	}
+	for _, d := range []struct {
+		name string
+		dest **time.Time
+	}{{"DTSTART", &item.StartAt}, {"DUE", &item.DueAt}} {
+		if p := c.Get(d.name); p != nil {
+			t, err := p.Time()
+			if err != nil {
+				return nil, err
+			}
+			*d.dest = &t
+		}
+	}
+	if p := c.Get("RRULE"); p != nil {
+		rule := p.Value
+		item.Recurrence = &rule
+	}
+	for _, p := range c.All("CATEGORIES") {
+		for _, tag := range ical.SplitText(p.Value) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+	}
+	return item, nil
+}
+
+// icsParent returns the UID of the parent a VTODO is RELATED-TO, if any.
+func icsParent(c *ical.Component) *string {
+	for _, p := range c.All("RELATED-TO") {
+		if reltype := strings.ToUpper(p.Params["RELTYPE"]); reltype == "" || reltype == "PARENT" {
+			return &p.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// markdownPriorities are the Obsidian Tasks priority markers. Todos without
+// one have the normal priority; the medium marker reads as normal too.
+var markdownPriorities = []struct {
+	priority string
+	marker   string
+}{
+	{"very-high", "🔺"},
+	{"high", "⏫"},
+	{"low", "🔽"},
+	{"very-low", "⏬"},
+}
+
+const (
+	markdownMediumMarker  = "🔼"
+	markdownStartMarker   = "🛫"
+	markdownDueMarker     = "📅"
+	markdownDoneMarker    = "✅"
+	markdownCreatedMarker = "➕"
+	markdownPlanMarker    = "⏳"
+)
+
+var markdownTask = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s*(.*)$`)
+
+// encodeMarkdown writes the title as a heading and the todos as a checklist,
+// subtasks indented below their parent. Statuses are not kept.
+func encodeMarkdown(w io.Writer, list *List) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# " + strings.Join(strings.Fields(list.Title), " ") + "\n\n")
+	write := func(indent string, item *Item) {
+		box := "[ ]"
+		if item.Done {
+			box = "[x]"
+		}
+		parts := []string{indent + "- " + box, strings.Join(strings.Fields(item.Title), " ")}
+		for _, tag := range item.Tags {
+			parts = append(parts, "#"+strings.Join(strings.Fields(tag), "_"))
+		}
+		for _, p := range markdownPriorities {
+			if p.priority == item.Priority {
+				parts = append(parts, p.marker)
+			}
+		}
+		if item.StartAt != nil {
+			parts = append(parts, markdownStartMarker+" "+dateOnly(item.StartAt))
+		}
+		if item.DueAt != nil {
+			parts = append(parts, markdownDueMarker+" "+dateOnly(item.DueAt))
+		}
+		bw.WriteString(strings.Join(parts, " ") + "\n")
+	}
+	for _, item := range list.Todos {
+		write("", item)
+		for _, sub := range item.Subtasks {
+			write("  ", sub)
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeMarkdown reads the first top level heading as the title and every
+// checklist item as a todo, indented items as subtasks of the item above.
+// Other lines are ignored.
+func decodeMarkdown(r io.Reader) (*List, error) {
+	list := &List{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if list.Title == "" && strings.HasPrefix(text, "# ") {
+			list.Title = strings.TrimSpace(text[2:])
+			continue
+		}
+		m := markdownTask.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+
+		item := &Item{Priority: "normal", Done: m[2] != " "}
+		var words []string
+		fields := strings.Fields(strings.ReplaceAll(m[3], "\ufe0f", ""))
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			var err error
+			switch field {
+			case markdownStartMarker, markdownDueMarker, markdownDoneMarker, markdownCreatedMarker, markdownPlanMarker:
+				if i+1 == len(fields) {
+					return nil, fmt.Errorf("line %d: %s must be followed by a date", line, field)
+				}
+				i++
+				switch field {
+				case markdownStartMarker:
+					item.StartAt, err = parseDate(fields[i])
+				case markdownDueMarker:
+					item.DueAt, err = parseDate(fields[i])
+				}
+			case markdownMediumMarker:
+			default:
+				if p := markerPriority(field); p != "" {
+					item.Priority = p
+				} else if len(field) > 1 && field[0] == '#' {
+					item.Tags = append(item.Tags, field[1:])
+				} else {
+					words = append(words, field)
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("line %d: the todo has no text", line)
+		}
+		item.Title = strings.Join(words, " ")
+
+		if m[1] != "" && len(list.Todos) > 0 {
+			parent := list.Todos[len(list.Todos)-1]
+			parent.Subtasks = append(parent.Subtasks, item)
+			continue
+		}
+		list.Todos = append(list.Todos, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func markerPriority(marker string) string {
+	for _, p := range markdownPriorities {
+		if p.marker == marker {
+			return p.priority
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// todoTxtPriorities maps priorities to todo.txt priority letters. Letters
+// past E read as very-low.
+var todoTxtPriorities = []struct {
+	priority string
+	letter   string
+}{
+	{"very-high", "A"},
+	{"high", "B"},
+	{"normal", "C"},
+	{"low", "D"},
+	{"very-low", "E"},
+}
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// encodeTodoTxt writes a line per todo, subtasks right after their parent.
+// Tags become projects, start_at the t: threshold date and completed todos
+// keep their priority in a pri: tag, as the format suggests.
+func encodeTodoTxt(w io.Writer, list *List) error {
+	bw := bufio.NewWriter(w)
+	write := func(item *Item) {
+		var parts []string
+		letter := priorityLetter(item.Priority)
+		if item.Done {
+			parts = append(parts, "x")
+		} else if letter != "" {
+			parts = append(parts, "("+letter+")")
+		}
+		parts = append(parts, strings.Join(strings.Fields(item.Title), " "))
+		for _, tag := range item.Tags {
+			parts = append(parts, "+"+strings.Join(strings.Fields(tag), "_"))
+		}
+		if item.StartAt != nil {
+			parts = append(parts, "t:"+dateOnly(item.StartAt))
+		}
+		if item.DueAt != nil {
+			parts = append(parts, "due:"+dateOnly(item.DueAt))
+		}
+		if item.Done && letter != "" {
+			parts = append(parts, "pri:"+letter)
+		}
+		if item.Status != "" {
+			parts = append(parts, "status:"+item.Status)
+		}
+		bw.WriteString(strings.Join(parts, " ") + "\n")
+	}
+	for _, item := range list.Todos {
+		write(item)
+		for _, sub := range item.Subtasks {
+			write(sub)
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeTodoTxt reads a todo per non-blank line. Projects and contexts both
+// become tags; creation and completion dates are skipped.
+func decodeTodoTxt(r io.Reader) (*List, error) {
+	list := &List{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		item := &Item{}
+		if fields[0] == "x" {
+			item.Done = true
+			fields = fields[1:]
+		}
+		if len(fields) > 0 {
+			if m := todoTxtPriority.FindStringSubmatch(fields[0]); m != nil {
+				item.Priority = letterPriority(m[1])
+				fields = fields[1:]
+			}
+		}
+		for len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+
+		var words []string
+		for _, field := range fields {
+			key, value, _ := strings.Cut(field, ":")
+			var err error
+			switch {
+			case len(field) > 1 && (field[0] == '+' || field[0] == '@'):
+				item.Tags = append(item.Tags, field[1:])
+			case key == "due" && value != "":
+				item.DueAt, err = parseDate(value)
+			case key == "t" && value != "":
+				item.StartAt, err = parseDate(value)
+			case key == "pri" && len(value) == 1 && value >= "A" && value <= "Z":
+				item.Priority = letterPriority(value)
+			case key == "status" && value != "":
+				item.Status = value
+			default:
+				words = append(words, field)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %v", line, key, err)
+			}
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("line %d: the todo has no text", line)
+		}
+		item.Title = strings.Join(words, " ")
+		list.Todos = append(list.Todos, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func priorityLetter(priority string) string {
+	for _, p := range todoTxtPriorities {
+		if p.priority == priority {
+			return p.letter
+		}
+	}
+	return ""
+}
+
+func letterPriority(letter string) string {
+	for _, p := range todoTxtPriorities {
+		if p.letter == letter {
+			return p.priority
+		}
+	}
+	return "very-low"
+}
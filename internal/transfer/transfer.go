@@ -0,0 +1,188 @@
+// Package transfer reads and writes activity groups in the formats they are
+// exported in, so lists can move between installations and other tools:
+//
+//	json      lossless, including the workflow and subtasks
+//	csv       one row per todo; parent holds the row number of the parent
+//	todotxt   the todo.txt format, with subtasks flattened
+//	markdown  a checklist using the Obsidian Tasks priority and date markers
+//	ics       the VTODO components of an iCalendar file, import only
+//
+// Only json carries the workflow and comments; comments are exported for
+// the record and left out of imports. csv and todotxt have no place for the
+// title of the group, which importers have to supply. Calendars are
+// exported as feeds instead, see the calendar usecase.
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/ical"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatTodoTxt  = "todotxt"
+	FormatMarkdown = "markdown"
+	FormatICS      = "ics"
+)
+
+// Formats lists the formats lists are exported in.
+var Formats = []string{FormatJSON, FormatCSV, FormatTodoTxt, FormatMarkdown}
+
+// ImportFormats lists the formats lists are imported from.
+var ImportFormats = []string{FormatJSON, FormatCSV, FormatTodoTxt, FormatMarkdown, FormatICS}
+
+// List is an activity group together with its todos in position order.
+type List struct {
+	Title    string           `json:"title"`
+	Email    string           `json:"email,omitempty"`
+	Workflow *web.WorkflowDTO `json:"workflow,omitempty"`
+	Todos    []*Item          `json:"todos"`
+}
+
+// Item is a todo. An empty Priority stands for the default priority and an
+// empty Status for the status Done implies.
+type Item struct {
+	Title      string     `json:"title"`
+	Priority   string     `json:"priority,omitempty"`
+	Done       bool       `json:"done"`
+	Status     string     `json:"status,omitempty"`
+	StartAt    *time.Time `json:"start_at,omitempty"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	Recurrence *string    `json:"recurrence,omitempty"`
+	// AutoComplete is only kept by json.
+	AutoComplete bool       `json:"auto_complete,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Comments     []*Comment `json:"comments,omitempty"`
+	Subtasks     []*Item    `json:"subtasks,omitempty"`
+}
+
+// Comment is a comment on a todo, oldest first, with the email of its
+// author. Body is Markdown.
+type Comment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Valid reports whether lists can be exported in format.
+func Valid(format string) bool {
+	return contains(Formats, format)
+}
+
+// ValidImport reports whether lists can be imported from format.
+func ValidImport(format string) bool {
+	return contains(ImportFormats, format)
+}
+
+func contains(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the media type of format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatTodoTxt:
+		return "text/plain; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatICS:
+		return ical.ContentType
+	default:
+		return "application/json"
+	}
+}
+
+// Extension returns the file name extension of format, dot included.
+func Extension(format string) string {
+	switch format {
+	case FormatCSV:
+		return ".csv"
+	case FormatTodoTxt:
+		return ".txt"
+	case FormatMarkdown:
+		return ".md"
+	case FormatICS:
+		return ".ics"
+	default:
+		return ".json"
+	}
+}
+
+func Encode(w io.Writer, format string, list *List) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	case FormatCSV:
+		return encodeCSV(w, list)
+	case FormatTodoTxt:
+		return encodeTodoTxt(w, list)
+	case FormatMarkdown:
+		return encodeMarkdown(w, list)
+	}
+	return fmt.Errorf("format %q is not supported", format)
+}
+
+// Decode reads a list. Errors describe where the input is malformed.
+func Decode(r io.Reader, format string) (*List, error) {
+	switch format {
+	case FormatJSON:
+		var list List
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&list); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		return &list, nil
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatTodoTxt:
+		return decodeTodoTxt(r)
+	case FormatMarkdown:
+		return decodeMarkdown(r)
+	case FormatICS:
+		return decodeICS(r)
+	}
+	return nil, fmt.Errorf("format %q is not supported", format)
+}
+
+// Len counts the items of the list, subtasks included.
+func (l *List) Len() int {
+	n := 0
+	for _, item := range l.Todos {
+		n += 1 + len(item.Subtasks)
+	}
+	return n
+}
+
+const dateLayout = "2006-01-02"
+
+// parseDate reads an RFC 3339 timestamp or a YYYY-MM-DD date.
+func parseDate(s string) (*time.Time, error) {
+	for _, layout := range []string{time.RFC3339, dateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a YYYY-MM-DD date", s)
+}
+
+// dateOnly renders the date of t, for the formats whose dates have no time
+// of day.
+func dateOnly(t *time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
@@ -0,0 +1,108 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tricky holds titles that trip up the formats: separators, quotes, line
+// breaks and a leading "x ", which todo.txt uses to mark completed todos.
+func tricky() *List {
+	start := time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2023, 4, 12, 0, 0, 0, 0, time.UTC)
+	rule := "FREQ=WEEKLY;BYDAY=MO"
+	return &List{
+		Title: `Launch, "Q3" | ops`,
+		Todos: []*Item{
+			{Title: `Buy milk, eggs and "fresh" bread`, Priority: "high", Tags: []string{"errands", "home"}, DueAt: &due},
+			{Title: "x marks the spot", Priority: "normal"},
+			{Title: "x out the old logo", Priority: "very-low", Done: true},
+			{Title: "Call Ann\nabout the \"offer\"", Priority: "very-high", Status: "in_progress"},
+			{Title: "a | b | c; d", Priority: "low", StartAt: &start, DueAt: &due, Recurrence: &rule},
+			{
+				Title:        "Ship, it",
+				Priority:     "high",
+				AutoComplete: true,
+				Comments:     []*Comment{{Author: "ann@example.com", Body: "**Soon**, please", CreatedAt: start}},
+				Subtasks: []*Item{
+					{Title: "x-ray the build", Priority: "normal", Done: true},
+					{Title: `Write "release notes"`, Priority: "low"},
+				},
+			},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		// lose applies what the format is known not to keep.
+		lose func(list *List)
+	}{
+		{FormatJSON, func(list *List) {}},
+		{FormatCSV, func(list *List) {
+			list.Title = ""
+			each(list, func(item *Item) {
+				item.AutoComplete, item.Comments = false, nil
+			})
+		}},
+		{FormatTodoTxt, func(list *List) {
+			list.Title = ""
+			each(list, func(item *Item) {
+				item.Title = strings.Join(strings.Fields(item.Title), " ")
+				item.Recurrence, item.AutoComplete, item.Comments = nil, false, nil
+			})
+			// Subtasks follow their parent as todos of their own.
+			var flat []*Item
+			for _, item := range list.Todos {
+				flat = append(flat, item)
+				flat = append(flat, item.Subtasks...)
+				item.Subtasks = nil
+			}
+			list.Todos = flat
+		}},
+		{FormatMarkdown, func(list *List) {
+			each(list, func(item *Item) {
+				item.Title = strings.Join(strings.Fields(item.Title), " ")
+				item.Status, item.Recurrence, item.AutoComplete, item.Comments = "", nil, false, nil
+			})
+		}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Encode(&buf, tt.format, tricky()); err != nil {
+			t.Fatalf("%s: Encode error = %v", tt.format, err)
+		}
+		got, err := Decode(bytes.NewReader(buf.Bytes()), tt.format)
+		if err != nil {
+			t.Fatalf("%s: Decode error = %v\n%s", tt.format, err, buf.String())
+		}
+
+		want := tricky()
+		tt.lose(want)
+		if g, w := marshal(t, got), marshal(t, want); g != w {
+			t.Errorf("%s: round trip of\n%s\n= %s\nwant %s", tt.format, buf.String(), g, w)
+		}
+	}
+}
+
+func each(list *List, f func(item *Item)) {
+	for _, item := range list.Todos {
+		f(item)
+		for _, sub := range item.Subtasks {
+			f(sub)
+		}
+	}
+}
+
+func marshal(t *testing.T, list *List) string {
+	t.Helper()
+	b, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
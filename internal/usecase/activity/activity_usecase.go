@@ -9,7 +9,16 @@ import (
 type ActivityUC interface {
 	CreateActivity(ctx context.Context, req web.ActivityCreateRequest) (*web.ActivityDTO, error)
 	GetActivityByID(ctx context.Context, id int64) (*web.ActivityDTO, error)
-	GetAllActivity(ctx context.Context) ([]*web.ActivityDTO, error)
+	GetAllActivity(ctx context.Context, req web.ActivityListRequest) ([]*web.ActivityDTO, *web.Pagination, error)
 	UpdateActivity(ctx context.Context, req web.ActivityUpdateRequest) (*web.ActivityDTO, error)
-	DeleteActivity(ctx context.Context, id int64) error
+	DeleteActivity(ctx context.Context, req web.ActivityDeleteRequest) error
+	RestoreActivity(ctx context.Context, id int64) (*web.ActivityDTO, error)
+	GetActivityHistory(ctx context.Context, id int64) ([]*web.EventDTO, error)
+	GetActivityWorkflow(ctx context.Context, id int64) (*web.WorkflowDTO, error)
+	UpdateActivityWorkflow(ctx context.Context, req web.WorkflowUpdateRequest) (*web.WorkflowDTO, *web.ActivityDTO, error)
+	GetAllMember(ctx context.Context, activityID int64) ([]*web.MemberDTO, error)
+	InviteMember(ctx context.Context, req web.MemberInviteRequest) (*web.MemberDTO, error)
+	UpdateMember(ctx context.Context, req web.MemberUpdateRequest) (*web.MemberDTO, error)
+	DeleteMember(ctx context.Context, activityID, userID int64) error
+	TransferOwnership(ctx context.Context, req web.OwnershipTransferRequest) (*web.ActivityDTO, error)
 }
@@ -2,89 +2,448 @@ package activity
 
 import (
 	"context"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
 	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/entity"
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
 	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/event"
+	"github.com/vnnyx/golang-todo-api/internal/repository/member"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/user"
+	"github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+	todoUsecase "github.com/vnnyx/golang-todo-api/internal/usecase/todo"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
 )
 
 type ActivityUCImpl struct {
-	activityRepository activity.ActivityRepository
+	activityRepository  activity.ActivityRepository
+	todoRepository      todo.TodoRepository
+	eventRepository     event.EventRepository
+	memberRepository    member.MemberRepository
+	userRepository      user.UserRepository
+	workspaceRepository workspace.WorkspaceRepository
+	todoUC              todoUsecase.TodoUC
+	txManager           transaction.TxManager
 }
 
-func NewActivityUC(activityRepository activity.ActivityRepository) ActivityUC {
-	return &ActivityUCImpl{activityRepository: activityRepository}
+func NewActivityUC(activityRepository activity.ActivityRepository, todoRepository todo.TodoRepository, eventRepository event.EventRepository, memberRepository member.MemberRepository, userRepository user.UserRepository, workspaceRepository workspace.WorkspaceRepository, todoUC todoUsecase.TodoUC, txManager transaction.TxManager) ActivityUC {
+	return &ActivityUCImpl{
+		activityRepository:  activityRepository,
+		todoRepository:      todoRepository,
+		eventRepository:     eventRepository,
+		memberRepository:    memberRepository,
+		userRepository:      userRepository,
+		workspaceRepository: workspaceRepository,
+		todoUC:              todoUC,
+		txManager:           txManager,
+	}
 }
 
 func (uc *ActivityUCImpl) CreateActivity(ctx context.Context, req web.ActivityCreateRequest) (*web.ActivityDTO, error) {
-	if req.Title == "" {
-		return nil, model.ErrTitleCannotBeNull
+	if err := validation.Struct(req); err != nil {
+		return nil, err
 	}
-	got, err := uc.activityRepository.InsertActivity(entity.Activity{
-		Title: req.Title,
-		Email: req.Email,
+
+	var got *entity.Activity
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		got, err = uc.activityRepository.InsertActivity(ctx, entity.Activity{
+			Title: req.Title,
+			Email: req.Email,
+		})
+		if err != nil {
+			return err
+		}
+		if userID, ok := model.UserFromContext(ctx); ok {
+			_, err = uc.memberRepository.InsertMember(ctx, entity.ActivityMember{
+				ActivityID: got.ID,
+				UserID:     userID,
+				Role:       entity.MemberRoleOwner,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return uc.recordEvent(ctx, entity.EventActionCreate, nil, got)
 	})
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
-	return got.ToDTO(), nil
+	return uc.toDTO(ctx, got)
 }
 
 func (uc *ActivityUCImpl) GetActivityByID(ctx context.Context, id int64) (*web.ActivityDTO, error) {
-	got, err := uc.activityRepository.GetActivityByID(id)
+	got, err := uc.activityRepository.GetActivityByID(ctx, id)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
-	return got.ToDTO(), nil
+	return uc.toDTO(ctx, got)
 }
 
-func (uc *ActivityUCImpl) GetAllActivity(ctx context.Context) ([]*web.ActivityDTO, error) {
-	got, err := uc.activityRepository.GetAllActivity()
+func (uc *ActivityUCImpl) GetAllActivity(ctx context.Context, req web.ActivityListRequest) ([]*web.ActivityDTO, *web.Pagination, error) {
+	pagination, err := model.NewPagination(req.Sort, req.Order, req.Limit, req.Offset, req.Cursor,
+		"id", "title", "created_at", "updated_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := model.ActivityFilter{
+		Title: req.Title,
+		Email: req.Email,
+	}
+	for _, d := range []struct {
+		field string
+		value string
+		dest  **time.Time
+	}{
+		{"created_after", req.CreatedAfter, &filter.CreatedAfter},
+		{"created_before", req.CreatedBefore, &filter.CreatedBefore},
+		{"updated_after", req.UpdatedAfter, &filter.UpdatedAfter},
+		{"updated_before", req.UpdatedBefore, &filter.UpdatedBefore},
+	} {
+		if *d.dest, err = model.ParseTime(d.value); err != nil {
+			return nil, nil, model.ErrInvalidDate.WithField(d.field, d.field+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+
+	got, page, err := uc.activityRepository.GetAllActivity(ctx, filter, pagination)
 	if err != nil {
 		logrus.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
-	res := make([]*web.ActivityDTO, 0)
-	for _, a := range got {
-		res = append(res, a.ToDTO())
+	res, err := uc.toDTOs(ctx, got)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
 	}
 
-	return res, nil
+	return res, &web.Pagination{Total: page.Total, Next: page.Next, Prev: page.Prev}, nil
 }
 
 func (uc *ActivityUCImpl) UpdateActivity(ctx context.Context, req web.ActivityUpdateRequest) (*web.ActivityDTO, error) {
-	activity, err := uc.activityRepository.GetActivityByID(req.ID)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.Activity
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		if req.Version != nil && *req.Version != activity.Version {
+			return model.ErrVersionMismatch
+		}
+
+		before := *activity
+		activity.Title = req.Title
+
+		got, err = uc.activityRepository.UpdateActivity(ctx, *activity)
+		if err != nil {
+			return err
+		}
+		return uc.recordEvent(ctx, entity.EventActionUpdate, &before, got)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	return uc.toDTO(ctx, got)
+}
+
+func (uc *ActivityUCImpl) DeleteActivity(ctx context.Context, req web.ActivityDeleteRequest) error {
+	if req.Mode == "" {
+		req.Mode = web.ActivityDeleteModeCascade
+	}
+	switch req.Mode {
+	case web.ActivityDeleteModeCascade, web.ActivityDeleteModeRestrict:
+	case web.ActivityDeleteModeMove:
+		if req.Target == 0 {
+			return model.ErrMoveTargetCannotBeNull
+		}
+		if req.Target == req.ID {
+			return model.ErrMoveTargetIsSameGroup
+		}
+	default:
+		return model.ErrInvalidDeleteMode
+	}
+
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleOwner); err != nil {
+			return err
+		}
+
+		switch req.Mode {
+		case web.ActivityDeleteModeRestrict:
+			count, err := uc.todoRepository.CountTodoByActivityGroupID(ctx, activity.ID)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return model.ErrActivityGroupNotEmpty
+			}
+		case web.ActivityDeleteModeMove:
+			target, err := uc.activityRepository.GetActivityByID(ctx, req.Target)
+			if err != nil {
+				if apperror.IsNotFound(err) {
+					return model.ErrMoveTargetNotFound
+				}
+				return err
+			}
+			if err = uc.authorize(ctx, target.ID, entity.MemberRoleEditor); err != nil {
+				return err
+			}
+			// Moved todos are placed after those of the target and keep
+			// only its members as assignees and watchers.
+			if err = uc.todoUC.MoveActivityTodos(ctx, activity.ID, target.ID); err != nil {
+				return err
+			}
+		default:
+			todos, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, activity.ID)
+			if err != nil {
+				return err
+			}
+			if err := uc.todoRepository.DeleteTodoByActivityGroupID(ctx, activity.ID, deletedAt); err != nil {
+				return err
+			}
+			if err := uc.recordTodoEvents(ctx, entity.EventActionDelete, todos, &deletedAt); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.activityRepository.DeleteActivity(ctx, activity.ID, deletedAt); err != nil {
+			return err
+		}
+		after := *activity
+		after.DeletedAt = &deletedAt
+		return uc.recordEvent(ctx, entity.EventActionDelete, activity, &after)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (uc *ActivityUCImpl) RestoreActivity(ctx context.Context, id int64) (*web.ActivityDTO, error) {
+	var got *entity.Activity
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetTrashedActivityByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleOwner); err != nil {
+			return err
+		}
+
+		got, err = uc.activityRepository.RestoreActivity(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.recordEvent(ctx, entity.EventActionRestore, activity, got); err != nil {
+			return err
+		}
+
+		todos, err := uc.todoRepository.GetTrashedTodoByActivityGroupID(ctx, activity.ID, *activity.DeletedAt)
+		if err != nil {
+			return err
+		}
+		if err = uc.todoRepository.RestoreTodoByActivityGroupID(ctx, activity.ID, *activity.DeletedAt); err != nil {
+			return err
+		}
+		return uc.recordTodoEvents(ctx, entity.EventActionRestore, todos, nil)
+	})
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
+	return uc.toDTO(ctx, got)
+}
 
-	activity.Title = req.Title
+// GetActivityHistory lists the changes made to an activity group, oldest
+// first. Trashed groups keep their history.
+func (uc *ActivityUCImpl) GetActivityHistory(ctx context.Context, id int64) ([]*web.EventDTO, error) {
+	if _, err := uc.activityRepository.GetActivityByID(ctx, id); err != nil {
+		if _, trashedErr := uc.activityRepository.GetTrashedActivityByID(ctx, id); trashedErr != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+	}
 
-	got, err := uc.activityRepository.UpdateActivity(*activity)
+	got, err := uc.eventRepository.GetActivityEventByActivityID(ctx, id)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
 
-	return got.ToDTO(), nil
+	res := make([]*web.EventDTO, 0)
+	for _, e := range got {
+		res = append(res, e.ToDTO())
+	}
+	return res, nil
 }
 
-func (uc *ActivityUCImpl) DeleteActivity(ctx context.Context, id int64) error {
-	activity, err := uc.activityRepository.GetActivityByID(id)
+// GetActivityWorkflow returns the workflow todos of the group follow, which
+// is the default one unless the group defines its own.
+func (uc *ActivityUCImpl) GetActivityWorkflow(ctx context.Context, id int64) (*web.WorkflowDTO, error) {
+	got, err := uc.activityRepository.GetActivityByID(ctx, id)
 	if err != nil {
 		logrus.Error(err)
-		return err
+		return nil, err
+	}
+	return got.WorkflowOrDefault().ToDTO(), nil
+}
+
+// UpdateActivityWorkflow replaces the workflow of a group. It refuses to drop
+// a status that live todos of the group are still in.
+func (uc *ActivityUCImpl) UpdateActivityWorkflow(ctx context.Context, req web.WorkflowUpdateRequest) (*web.WorkflowDTO, *web.ActivityDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, nil, err
+	}
+	workflow := &entity.Workflow{
+		Initial:     req.Initial,
+		Done:        req.Done,
+		Closed:      req.Closed,
+		Transitions: req.Transitions,
+	}
+	if workflow.Closed == nil {
+		workflow.Closed = []string{}
+	}
+	for status, targets := range workflow.Transitions {
+		if targets == nil {
+			workflow.Transitions[status] = []string{}
+		}
 	}
-	err = uc.activityRepository.DeleteActivity(activity.ID)
+	if err := workflow.Validate(); err != nil {
+		return nil, nil, model.ErrInvalidWorkflow.WithMessage("%s", err.Error())
+	}
+
+	var got *entity.Activity
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ActivityID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		if req.Version != nil && *req.Version != activity.Version {
+			return model.ErrVersionMismatch
+		}
+
+		todos, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		for _, t := range todos {
+			if !workflow.Has(t.Status) {
+				return model.ErrWorkflowStatusInUse.WithMessage("todo %d is still in status %s", t.ID, t.Status)
+			}
+			if workflow.IsClosed(t.Status) == t.IsActive {
+				return model.ErrWorkflowStatusInUse.WithMessage("todo %d in status %s would change is_active", t.ID, t.Status)
+			}
+		}
+
+		before := *activity
+		activity.Workflow = workflow
+
+		got, err = uc.activityRepository.UpdateActivity(ctx, *activity)
+		if err != nil {
+			return err
+		}
+		return uc.recordEvent(ctx, entity.EventActionUpdate, &before, got)
+	})
 	if err != nil {
 		logrus.Error(err)
+		return nil, nil, err
+	}
+	res, err := uc.toDTO(ctx, got)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+	return got.WorkflowOrDefault().ToDTO(), res, nil
+}
+
+func (uc *ActivityUCImpl) recordEvent(ctx context.Context, action string, before, after *entity.Activity) error {
+	return uc.eventRepository.InsertActivityEvent(ctx, entity.NewActivityEvent(action, before, after, model.ActorFromContext(ctx)))
+}
+
+// recordTodoEvents records action on each of todos, which leaves them in the
+// trash since deletedAt, or out of it when deletedAt is nil.
+func (uc *ActivityUCImpl) recordTodoEvents(ctx context.Context, action string, todos []*entity.Todo, deletedAt *time.Time) error {
+	actor := model.ActorFromContext(ctx)
+	events := make([]entity.TodoEvent, 0, len(todos))
+	for _, t := range todos {
+		after := *t
+		after.DeletedAt = deletedAt
+		events = append(events, entity.NewTodoEvent(action, t, &after, actor))
+	}
+	return uc.eventRepository.InsertTodoEvents(ctx, events)
+}
+
+// authorize checks that the user of ctx has at least role in the activity
+// group. Without a user, as on the command line, everything is allowed.
+func (uc *ActivityUCImpl) authorize(ctx context.Context, activityID int64, role string) error {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	member, err := uc.memberRepository.GetMember(ctx, activityID, userID)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return model.ErrInsufficientRole
+		}
 		return err
 	}
+	if !member.Can(role) {
+		return model.ErrInsufficientRole
+	}
 	return nil
 }
+
+func (uc *ActivityUCImpl) toDTO(ctx context.Context, activity *entity.Activity) (*web.ActivityDTO, error) {
+	res, err := uc.toDTOs(ctx, []*entity.Activity{activity})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// toDTOs converts activities to DTOs listing their members.
+func (uc *ActivityUCImpl) toDTOs(ctx context.Context, activities []*entity.Activity) ([]*web.ActivityDTO, error) {
+	ids := make([]int64, 0, len(activities))
+	for _, a := range activities {
+		ids = append(ids, a.ID)
+	}
+	members, err := uc.memberRepository.GetMemberByActivityIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*web.ActivityDTO, 0, len(activities))
+	for _, a := range activities {
+		dto := a.ToDTO()
+		for _, m := range members[a.ID] {
+			dto.Members = append(dto.Members, m.ToDTO())
+		}
+		res = append(res, dto)
+	}
+	return res, nil
+}
@@ -0,0 +1,188 @@
+package activity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+// GetAllMember lists the members of an activity group, the owner first.
+func (uc *ActivityUCImpl) GetAllMember(ctx context.Context, activityID int64) ([]*web.MemberDTO, error) {
+	if _, err := uc.activityRepository.GetActivityByID(ctx, activityID); err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	got, err := uc.memberRepository.GetMemberByActivityIDs(ctx, []int64{activityID})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.MemberDTO, 0, len(got[activityID]))
+	for _, m := range got[activityID] {
+		res = append(res, m.ToDTO())
+	}
+	return res, nil
+}
+
+// InviteMember adds the user with the given email to an activity group.
+// Only the owner may invite, and only people who already have an account
+// in the workspace of the group.
+func (uc *ActivityUCImpl) InviteMember(ctx context.Context, req web.MemberInviteRequest) (*web.MemberDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.ActivityMember
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ActivityGroupID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleOwner); err != nil {
+			return err
+		}
+		invitee, err := uc.userRepository.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+		if err != nil {
+			if apperror.IsNotFound(err) {
+				return model.ErrUnknownInvitee
+			}
+			return err
+		}
+		if inWorkspace, err := uc.workspaceRepository.IsWorkspaceMember(ctx, activity.WorkspaceID, invitee.ID); err != nil {
+			return err
+		} else if !inWorkspace {
+			return model.ErrUnknownInvitee
+		}
+		if _, err = uc.memberRepository.GetMember(ctx, activity.ID, invitee.ID); err == nil {
+			return model.ErrAlreadyMember
+		} else if !apperror.IsNotFound(err) {
+			return err
+		}
+
+		got, err = uc.memberRepository.InsertMember(ctx, entity.ActivityMember{
+			ActivityID: activity.ID,
+			UserID:     invitee.ID,
+			Role:       req.Role,
+		})
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// UpdateMember changes the role of a member other than the owner.
+func (uc *ActivityUCImpl) UpdateMember(ctx context.Context, req web.MemberUpdateRequest) (*web.MemberDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.ActivityMember
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ActivityGroupID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleOwner); err != nil {
+			return err
+		}
+		member, err := uc.memberRepository.GetMember(ctx, activity.ID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if member.Role == entity.MemberRoleOwner {
+			return model.ErrOwnerRole
+		}
+
+		got, err = uc.memberRepository.UpdateMemberRole(ctx, activity.ID, member.UserID, req.Role)
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// DeleteMember removes a member from an activity group. The owner may
+// remove anyone but themselves; everyone else may only leave.
+func (uc *ActivityUCImpl) DeleteMember(ctx context.Context, activityID, userID int64) error {
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, activityID)
+		if err != nil {
+			return err
+		}
+		if current, ok := model.UserFromContext(ctx); !ok || current != userID {
+			if err = uc.authorize(ctx, activity.ID, entity.MemberRoleOwner); err != nil {
+				return err
+			}
+		}
+		member, err := uc.memberRepository.GetMember(ctx, activity.ID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == entity.MemberRoleOwner {
+			return model.ErrOwnerRole
+		}
+		return uc.memberRepository.DeleteMember(ctx, activity.ID, member.UserID)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// TransferOwnership makes another member the owner of an activity group.
+// The previous owner stays on as an editor.
+func (uc *ActivityUCImpl) TransferOwnership(ctx context.Context, req web.OwnershipTransferRequest) (*web.ActivityDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.Activity
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		got, err = uc.activityRepository.GetActivityByID(ctx, req.ActivityGroupID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, got.ID, entity.MemberRoleOwner); err != nil {
+			return err
+		}
+		member, err := uc.memberRepository.GetMember(ctx, got.ID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if member.Role == entity.MemberRoleOwner {
+			return nil
+		}
+
+		members, err := uc.memberRepository.GetMemberByActivityIDs(ctx, []int64{got.ID})
+		if err != nil {
+			return err
+		}
+		for _, m := range members[got.ID] {
+			if m.Role == entity.MemberRoleOwner {
+				if _, err = uc.memberRepository.UpdateMemberRole(ctx, got.ID, m.UserID, entity.MemberRoleEditor); err != nil {
+					return err
+				}
+			}
+		}
+		_, err = uc.memberRepository.UpdateMemberRole(ctx, got.ID, member.UserID, entity.MemberRoleOwner)
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.toDTO(ctx, got)
+}
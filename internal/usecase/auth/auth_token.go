@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+const (
+	issuer = "golang-todo-api"
+
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+
+	// APIKeyPrefix starts every API key, so keys are told apart from access
+	// tokens and are easy to spot in leaked logs.
+	APIKeyPrefix = "tda_"
+	// apiKeyShownLength is how much of a key is kept to tell keys apart.
+	apiKeyShownLength = len(APIKeyPrefix) + 8
+)
+
+// claims are the claims of access and refresh tokens. TokenType keeps a
+// refresh token from being used as an access token and the other way round.
+// WorkspaceID, when set, binds the token to a workspace.
+type claims struct {
+	jwt.RegisteredClaims
+	TokenType   string `json:"token_type"`
+	WorkspaceID int64  `json:"wid,omitempty"`
+}
+
+func (uc *AuthUCImpl) signToken(userID, workspaceID int64, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		TokenType:   tokenType,
+		WorkspaceID: workspaceID,
+	})
+	return token.SignedString(uc.secret)
+}
+
+// parseToken returns the user a token of tokenType was issued to and the
+// workspace it is bound to, if any.
+func (uc *AuthUCImpl) parseToken(token, tokenType string) (userID, workspaceID int64, err error) {
+	var c claims
+	_, err = jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return uc.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || c.TokenType != tokenType || !c.VerifyIssuer(issuer, true) {
+		return 0, 0, model.ErrInvalidToken
+	}
+	userID, err = strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return 0, 0, model.ErrInvalidToken
+	}
+	return userID, c.WorkspaceID, nil
+}
+
+// newAPIKey returns a key of 256 random bits behind APIKeyPrefix.
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func isAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
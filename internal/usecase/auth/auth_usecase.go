@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type AuthUC interface {
+	Register(ctx context.Context, req web.RegisterRequest) (*web.AuthDTO, error)
+	Login(ctx context.Context, req web.LoginRequest) (*web.AuthDTO, error)
+	Refresh(ctx context.Context, req web.RefreshRequest) (*web.AuthDTO, error)
+	Authenticate(ctx context.Context, credentials web.Credentials) (*web.Identity, error)
+	GetCurrentUser(ctx context.Context) (*web.UserDTO, error)
+	CreateAPIKey(ctx context.Context, req web.APIKeyCreateRequest) (*web.APIKeyDTO, error)
+	GetAllAPIKey(ctx context.Context) ([]*web.APIKeyDTO, error)
+	DeleteAPIKey(ctx context.Context, id int64) error
+}
@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/apikey"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/user"
+	"github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// apiKeyTouchInterval bounds how often the last use of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+// dummyHash is compared against when no user has the email of a login, so
+// unknown emails take as long to reject as wrong passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+type AuthUCImpl struct {
+	cfg                 *infrastructure.Config
+	userRepository      user.UserRepository
+	apiKeyRepository    apikey.APIKeyRepository
+	workspaceRepository workspace.WorkspaceRepository
+	txManager           transaction.TxManager
+	secret              []byte
+	accessTTL           time.Duration
+	refreshTTL          time.Duration
+}
+
+func NewAuthUC(cfg *infrastructure.Config, userRepository user.UserRepository, apiKeyRepository apikey.APIKeyRepository, workspaceRepository workspace.WorkspaceRepository, txManager transaction.TxManager) AuthUC {
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		logrus.Warn("JWT_SECRET is not set, tokens will not survive a restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+	return &AuthUCImpl{
+		cfg:                 cfg,
+		userRepository:      userRepository,
+		apiKeyRepository:    apiKeyRepository,
+		workspaceRepository: workspaceRepository,
+		txManager:           txManager,
+		secret:              secret,
+		accessTTL:           time.Duration(cfg.JWTAccessTTLMinute) * time.Minute,
+		refreshTTL:          time.Duration(cfg.JWTRefreshTTLHour) * time.Hour,
+	}
+}
+
+// Register signs a user up with a personal workspace of their own.
+func (uc *AuthUCImpl) Register(ctx context.Context, req web.RegisterRequest) (*web.AuthDTO, error) {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	var got *entity.User
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.userRepository.GetUserByEmail(ctx, req.Email); err == nil {
+			return model.ErrEmailTaken
+		} else if !apperror.IsNotFound(err) {
+			return err
+		}
+		got, err = uc.userRepository.InsertUser(ctx, entity.User{
+			Email:        req.Email,
+			Name:         req.Name,
+			PasswordHash: string(hash),
+		})
+		if err != nil {
+			return err
+		}
+		return uc.insertPersonalWorkspace(ctx, got)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.session(got, 0)
+}
+
+func (uc *AuthUCImpl) Login(ctx context.Context, req web.LoginRequest) (*web.AuthDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	got, err := uc.userRepository.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		if !apperror.IsNotFound(err) {
+			logrus.Error(err)
+			return nil, err
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password))
+		return nil, model.ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(got.PasswordHash), []byte(req.Password)) != nil {
+		return nil, model.ErrInvalidCredentials
+	}
+
+	var workspaceID int64
+	if req.Workspace != "" {
+		if workspaceID, err = uc.memberWorkspace(ctx, got.ID, req.Workspace); err != nil {
+			return nil, err
+		}
+	}
+	return uc.session(got, workspaceID)
+}
+
+// Refresh trades a refresh token for a new pair of tokens, bound to the
+// same workspace.
+func (uc *AuthUCImpl) Refresh(ctx context.Context, req web.RefreshRequest) (*web.AuthDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	userID, workspaceID, err := uc.parseToken(req.RefreshToken, tokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	got, err := uc.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return nil, model.ErrInvalidToken
+		}
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.session(got, workspaceID)
+}
+
+func (uc *AuthUCImpl) session(u *entity.User, workspaceID int64) (*web.AuthDTO, error) {
+	access, err := uc.signToken(u.ID, workspaceID, tokenTypeAccess, uc.accessTTL)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	refresh, err := uc.signToken(u.ID, workspaceID, tokenTypeRefresh, uc.refreshTTL)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return &web.AuthDTO{
+		User:         u.ToDTO(),
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(uc.accessTTL / time.Second),
+	}, nil
+}
+
+// Authenticate resolves the user a request acts as and the workspace it
+// works in. Bearer tokens carrying an API key are accepted too, as most HTTP
+// clients only know bearer auth.
+func (uc *AuthUCImpl) Authenticate(ctx context.Context, credentials web.Credentials) (*web.Identity, error) {
+	if credentials.APIKey == "" && isAPIKey(credentials.BearerToken) {
+		credentials.APIKey, credentials.BearerToken = credentials.BearerToken, ""
+	}
+
+	var userID, boundID int64
+	switch {
+	case credentials.APIKey != "":
+		key, err := uc.apiKeyRepository.GetAPIKeyByHash(ctx, hashAPIKey(credentials.APIKey))
+		if err != nil {
+			if apperror.IsNotFound(err) {
+				return nil, model.ErrInvalidAPIKey
+			}
+			logrus.Error(err)
+			return nil, err
+		}
+		now := time.Now()
+		if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+			if err := uc.apiKeyRepository.TouchAPIKey(ctx, key.ID, now); err != nil {
+				logrus.Error(err)
+			}
+		}
+		userID = key.UserID
+		if key.WorkspaceID != nil {
+			boundID = *key.WorkspaceID
+		}
+	case credentials.BearerToken != "":
+		id, workspaceID, err := uc.parseToken(credentials.BearerToken, tokenTypeAccess)
+		if err != nil {
+			return nil, err
+		}
+		userID, boundID = id, workspaceID
+	default:
+		return nil, model.ErrUnauthenticated
+	}
+
+	got, err := uc.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return nil, model.ErrInvalidToken
+		}
+		logrus.Error(err)
+		return nil, err
+	}
+	workspaceID, err := uc.resolveWorkspace(ctx, userID, boundID, credentials.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &web.Identity{User: got.ToDTO(), WorkspaceID: workspaceID}, nil
+}
+
+func (uc *AuthUCImpl) GetCurrentUser(ctx context.Context) (*web.UserDTO, error) {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, model.ErrUnauthenticated
+	}
+	got, err := uc.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// CreateAPIKey issues a key for the current user, bound to a workspace when
+// one is asked for. The key is only ever returned here.
+func (uc *AuthUCImpl) CreateAPIKey(ctx context.Context, req web.APIKeyCreateRequest) (*web.APIKeyDTO, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, model.ErrUnauthenticated
+	}
+	var workspaceID *int64
+	if req.Workspace != "" {
+		id, err := uc.memberWorkspace(ctx, userID, req.Workspace)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = &id
+	}
+	key, err := newAPIKey()
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	got, err := uc.apiKeyRepository.InsertAPIKey(ctx, entity.APIKey{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Name:        req.Name,
+		Prefix:      key[:apiKeyShownLength],
+		KeyHash:     hashAPIKey(key),
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	res := got.ToDTO()
+	res.Key = key
+	return res, nil
+}
+
+func (uc *AuthUCImpl) GetAllAPIKey(ctx context.Context) ([]*web.APIKeyDTO, error) {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, model.ErrUnauthenticated
+	}
+	got, err := uc.apiKeyRepository.GetAPIKeyByUserID(ctx, userID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.APIKeyDTO, 0, len(got))
+	for _, k := range got {
+		res = append(res, k.ToDTO())
+	}
+	return res, nil
+}
+
+// DeleteAPIKey revokes a key of the current user.
+func (uc *AuthUCImpl) DeleteAPIKey(ctx context.Context, id int64) error {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return model.ErrUnauthenticated
+	}
+	if err := uc.apiKeyRepository.DeleteAPIKey(ctx, userID, id); err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
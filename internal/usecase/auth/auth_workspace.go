@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+)
+
+func (uc *AuthUCImpl) insertPersonalWorkspace(ctx context.Context, u *entity.User) error {
+	name := u.Name
+	if name == "" {
+		name = u.Email
+	}
+	maxActivityGroups, maxTodos := uc.cfg.WorkspaceQuotas()
+	w, err := uc.workspaceRepository.InsertWorkspace(ctx, entity.Workspace{
+		Name:              name,
+		Slug:              entity.PersonalWorkspaceSlugPrefix + strconv.FormatInt(u.ID, 10),
+		MaxActivityGroups: maxActivityGroups,
+		MaxTodos:          maxTodos,
+	})
+	if err != nil {
+		return err
+	}
+	return uc.workspaceRepository.InsertWorkspaceMember(ctx, entity.WorkspaceMember{WorkspaceID: w.ID, UserID: u.ID})
+}
+
+// resolveWorkspace picks the workspace of a request: the one it asks for,
+// else the one its credentials are bound to, else the first the user
+// joined. Bound credentials cannot ask for another workspace, and the user
+// must still be a member of it.
+func (uc *AuthUCImpl) resolveWorkspace(ctx context.Context, userID, boundID int64, ref string) (int64, error) {
+	switch {
+	case ref != "":
+		workspaceID, err := uc.memberWorkspace(ctx, userID, ref)
+		if err != nil {
+			return 0, err
+		}
+		if boundID != 0 && workspaceID != boundID {
+			return 0, model.ErrWorkspaceForbidden
+		}
+		return workspaceID, nil
+	case boundID != 0:
+		return uc.memberWorkspace(ctx, userID, strconv.FormatInt(boundID, 10))
+	}
+
+	workspaces, err := uc.workspaceRepository.GetWorkspaceByUserID(ctx, userID)
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	if len(workspaces) == 0 {
+		return 0, model.ErrNoWorkspaceMembership
+	}
+	return workspaces[0].ID, nil
+}
+
+// memberWorkspace returns the id of the workspace ref names, if userID is a
+// member of it. Workspaces the user cannot see are forbidden rather than
+// not found, so their slugs are not given away.
+func (uc *AuthUCImpl) memberWorkspace(ctx context.Context, userID int64, ref string) (int64, error) {
+	w, err := workspace.GetWorkspaceByRef(ctx, uc.workspaceRepository, ref)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return 0, model.ErrWorkspaceForbidden
+		}
+		logrus.Error(err)
+		return 0, err
+	}
+	member, err := uc.workspaceRepository.IsWorkspaceMember(ctx, w.ID, userID)
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	if !member {
+		return 0, model.ErrWorkspaceForbidden
+	}
+	return w.ID, nil
+}
@@ -0,0 +1,13 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type CalendarUC interface {
+	CreateCalendarFeed(ctx context.Context, activityGroupID *int64) (*web.CalendarFeedDTO, error)
+	DeleteCalendarFeed(ctx context.Context, activityGroupID *int64) error
+	GetCalendar(ctx context.Context, req web.CalendarRequest) ([]byte, error)
+}
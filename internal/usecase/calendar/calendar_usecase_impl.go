@@ -0,0 +1,298 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/ical"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/calendar"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+)
+
+const prodID = "-//vnnyx//golang-todo-api//EN"
+
+// icsPriorities maps priorities to RFC 5545 priorities, 1 the highest.
+var icsPriorities = map[string]int{
+	"very-high": 1,
+	"high":      3,
+	"normal":    5,
+	"low":       7,
+	"very-low":  9,
+}
+
+type CalendarUCImpl struct {
+	calendarRepository  calendar.CalendarRepository
+	activityRepository  activity.ActivityRepository
+	todoRepository      todo.TodoRepository
+	tagRepository       tag.TagRepository
+	workspaceRepository workspace.WorkspaceRepository
+	txManager           transaction.TxManager
+}
+
+func NewCalendarUC(calendarRepository calendar.CalendarRepository, activityRepository activity.ActivityRepository, todoRepository todo.TodoRepository, tagRepository tag.TagRepository, workspaceRepository workspace.WorkspaceRepository, txManager transaction.TxManager) CalendarUC {
+	return &CalendarUCImpl{
+		calendarRepository:  calendarRepository,
+		activityRepository:  activityRepository,
+		todoRepository:      todoRepository,
+		tagRepository:       tagRepository,
+		workspaceRepository: workspaceRepository,
+		txManager:           txManager,
+	}
+}
+
+// CreateCalendarFeed issues a new token for the feed of an activity group,
+// or of all groups when activityGroupID is nil. The previous token of the
+// feed stops working.
+func (uc *CalendarUCImpl) CreateCalendarFeed(ctx context.Context, activityGroupID *int64) (*web.CalendarFeedDTO, error) {
+	token, err := newToken()
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	var got *entity.CalendarFeed
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if activityGroupID != nil {
+			if _, err := uc.activityRepository.GetActivityByID(ctx, *activityGroupID); err != nil {
+				return err
+			}
+		}
+		if err := uc.calendarRepository.DeleteCalendarFeed(ctx, activityGroupID); err != nil && !apperror.IsNotFound(err) {
+			return err
+		}
+		got, err = uc.calendarRepository.InsertCalendarFeed(ctx, entity.CalendarFeed{
+			ActivityGroupID: activityGroupID,
+			UserID:          model.OwnerFromContext(ctx),
+			TokenHash:       hashToken(token),
+		})
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	path := "/calendar.ics"
+	if activityGroupID != nil {
+		path = fmt.Sprintf("/activity-groups/%d/calendar.ics", *activityGroupID)
+	}
+	return got.ToDTO(token, path+"?token="+token), nil
+}
+
+// DeleteCalendarFeed revokes the token of a feed.
+func (uc *CalendarUCImpl) DeleteCalendarFeed(ctx context.Context, activityGroupID *int64) error {
+	err := uc.calendarRepository.DeleteCalendarFeed(ctx, activityGroupID)
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetCalendar renders the todos with a due date as an iCalendar feed, as
+// seen by the owner of the feed in the workspace of the feed. A token that
+// does not belong to the requested feed reads as a missing feed, so tokens
+// cannot be probed; so does the feed of an owner who left the workspace.
+func (uc *CalendarUCImpl) GetCalendar(ctx context.Context, req web.CalendarRequest) ([]byte, error) {
+	if req.Component == "" {
+		req.Component = web.CalendarComponentVTodo
+	}
+	if req.Component != web.CalendarComponentVTodo && req.Component != web.CalendarComponentVEvent {
+		return nil, model.ErrInvalidComponent
+	}
+	if req.Token == "" {
+		return nil, model.ErrCalendarFeedNotFound
+	}
+	// Only the token tells which workspace the request is for.
+	feed, err := uc.calendarRepository.GetCalendarFeedByTokenHash(model.WithAllWorkspaces(ctx), hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	// Feeds from before accounts existed have no owner to read as.
+	if !sameGroup(feed.ActivityGroupID, req.ActivityGroupID) || feed.UserID == nil {
+		return nil, model.ErrCalendarFeedNotFound
+	}
+	member, err := uc.workspaceRepository.IsWorkspaceMember(ctx, feed.WorkspaceID, *feed.UserID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	if !member {
+		return nil, model.ErrCalendarFeedNotFound
+	}
+	ctx = model.WithWorkspace(model.WithUser(ctx, *feed.UserID), feed.WorkspaceID)
+
+	filter := model.TodoFilter{Scheduled: true}
+	name := "Todos"
+	groups := make(map[int64]*entity.Activity)
+	if req.ActivityGroupID != nil {
+		got, err := uc.activityRepository.GetActivityByID(ctx, *req.ActivityGroupID)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		filter.ActivityGroupID = got.ID
+		name = got.Title
+		groups[got.ID] = got
+	}
+	todos, err := uc.todoRepository.GetTodoByFilter(ctx, filter, web.MaxCalendarTodos)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(todos))
+	var groupIDs []int64
+	for _, t := range todos {
+		ids = append(ids, t.ID)
+		if _, ok := groups[t.ActivityGroupID]; !ok {
+			groups[t.ActivityGroupID] = nil
+			groupIDs = append(groupIDs, t.ActivityGroupID)
+		}
+	}
+	if len(groupIDs) > 0 {
+		got, err := uc.activityRepository.GetActivityByIDs(ctx, groupIDs)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		for _, a := range got {
+			groups[a.ID] = a
+		}
+	}
+	tags, err := uc.tagRepository.GetTagByTodoIDs(ctx, ids)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	cal := &ical.Component{Name: "VCALENDAR"}
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", prodID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	cal.Add("METHOD", "PUBLISH")
+	cal.AddText("X-WR-CALNAME", name)
+	for _, t := range todos {
+		group := groups[t.ActivityGroupID]
+		if group == nil {
+			continue
+		}
+		cal.Components = append(cal.Components, component(req.Component, t, group.WorkflowOrDefault(), tags[t.ID]))
+	}
+
+	var buf bytes.Buffer
+	if err = ical.Encode(&buf, cal); err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// component renders a todo as a VTODO, or as a VEVENT spanning from its
+// start to its due date for calendars that do not show todos.
+func component(kind string, t *entity.Todo, workflow *entity.Workflow, tags []*entity.Tag) *ical.Component {
+	c := &ical.Component{Name: "VTODO"}
+	if kind == web.CalendarComponentVEvent {
+		c.Name = "VEVENT"
+	}
+	c.Add("UID", uid(t.ID))
+	c.Add("DTSTAMP", ical.FormatDateTime(t.UpdatedAt))
+	c.Add("CREATED", ical.FormatDateTime(t.CreatedAt))
+	c.Add("LAST-MODIFIED", ical.FormatDateTime(t.UpdatedAt))
+	c.Add("SEQUENCE", strconv.FormatInt(t.Version-1, 10))
+	c.AddText("SUMMARY", t.Title)
+	if p, ok := icsPriorities[t.Priority]; ok {
+		c.Add("PRIORITY", strconv.Itoa(p))
+	}
+
+	if kind == web.CalendarComponentVEvent {
+		if t.StartAt != nil && t.StartAt.Before(*t.DueAt) {
+			c.Add("DTSTART", ical.FormatDateTime(*t.StartAt))
+			c.Add("DTEND", ical.FormatDateTime(*t.DueAt))
+		} else {
+			c.Add("DTSTART", ical.FormatDateTime(*t.DueAt))
+		}
+		// Todos do not make anyone busy.
+		c.Add("TRANSP", "TRANSPARENT")
+	} else {
+		if t.StartAt != nil {
+			c.Add("DTSTART", ical.FormatDateTime(*t.StartAt))
+		}
+		c.Add("DUE", ical.FormatDateTime(*t.DueAt))
+		c.Add("STATUS", icsStatus(t, workflow))
+		if !t.IsActive {
+			c.Add("PERCENT-COMPLETE", "100")
+		}
+	}
+
+	if t.Recurrence != nil {
+		c.Add("RRULE", *t.Recurrence)
+	}
+	if len(tags) > 0 {
+		names := make([]string, 0, len(tags))
+		for _, g := range tags {
+			names = append(names, ical.EscapeText(g.Name))
+		}
+		c.Add("CATEGORIES", strings.Join(names, ","))
+	}
+	if t.ParentTodoID != nil {
+		c.Add("RELATED-TO", uid(*t.ParentTodoID))
+	}
+	return c
+}
+
+// icsStatus maps the status of a todo to a VTODO status. Closed statuses
+// other than the done one count as cancelled.
+func icsStatus(t *entity.Todo, workflow *entity.Workflow) string {
+	switch {
+	case t.Status == workflow.Done:
+		return "COMPLETED"
+	case workflow.IsClosed(t.Status):
+		return "CANCELLED"
+	case t.Status == workflow.Initial:
+		return "NEEDS-ACTION"
+	default:
+		return "IN-PROCESS"
+	}
+}
+
+func uid(todoID int64) string {
+	return fmt.Sprintf("todo-%d@golang-todo-api", todoID)
+}
+
+// newToken returns 256 random bits, URL safe.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sameGroup(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
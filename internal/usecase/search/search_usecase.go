@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type SearchUC interface {
+	Search(ctx context.Context, req web.SearchRequest) ([]*web.SearchHitDTO, *web.Pagination, error)
+}
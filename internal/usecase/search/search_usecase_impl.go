@@ -0,0 +1,175 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/search"
+)
+
+type SearchUCImpl struct {
+	activityRepository activity.ActivityRepository
+	todoRepository     todo.TodoRepository
+	tagRepository      tag.TagRepository
+}
+
+func NewSearchUC(activityRepository activity.ActivityRepository, todoRepository todo.TodoRepository, tagRepository tag.TagRepository) SearchUC {
+	return &SearchUCImpl{
+		activityRepository: activityRepository,
+		todoRepository:     todoRepository,
+		tagRepository:      tagRepository,
+	}
+}
+
+// Search matches the titles of the todos and activity groups passing the
+// filters against the query, best first, a page at a time. Each kind is
+// scored within its own table, so a word common to every todo counts for
+// little among todos alone.
+func (uc *SearchUCImpl) Search(ctx context.Context, req web.SearchRequest) ([]*web.SearchHitDTO, *web.Pagination, error) {
+	q := search.ParseQuery(req.Q)
+	if q.Empty() {
+		return nil, nil, model.ErrSearchQueryRequired
+	}
+	if req.Limit == 0 {
+		req.Limit = web.DefaultSearchLimit
+	}
+	pagination, err := model.NewPagination("", "", req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := query.NewMixedPage(pagination, query.SearchSort)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := newTodoFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Each repository has to return the rows skipped by the offset too, for
+	// which kind they fall in is only known once both are merged.
+	pagination = model.Pagination{Limit: p.Limit + p.Offset, Cursor: req.Cursor}
+	var (
+		items []searchItem
+		total int64
+	)
+	if req.Type != web.SearchTypeTodo && !todoOnly(req) {
+		activities, activityTotal, err := uc.activityRepository.SearchActivity(ctx, q, req.ActivityGroupID, pagination)
+		if err != nil {
+			logrus.Error(err)
+			return nil, nil, err
+		}
+		for _, hit := range activities {
+			items = append(items, searchItem{activity: hit})
+		}
+		total += activityTotal
+	}
+	if req.Type != web.SearchTypeActivityGroup {
+		todos, todoTotal, err := uc.todoRepository.SearchTodo(ctx, q, filter, pagination)
+		if err != nil {
+			logrus.Error(err)
+			return nil, nil, err
+		}
+		for _, hit := range todos {
+			items = append(items, searchItem{todo: hit})
+		}
+		total += todoTotal
+	}
+	items, page := query.Result(p, query.Apply(p, items, searchItem.key), total, searchItem.key)
+
+	hits := make([]*web.SearchHitDTO, 0, len(items))
+	var todoIDs []int64
+	for _, item := range items {
+		var hit *web.SearchHitDTO
+		if a := item.activity; a != nil {
+			hit = newSearchHit(q, a.Activity.Title, a.Score)
+			hit.Type, hit.ID, hit.ActivityGroup = web.SearchTypeActivityGroup, a.Activity.ID, a.Activity.ToDTO()
+		} else {
+			t := item.todo
+			hit = newSearchHit(q, t.Todo.Title, t.Score)
+			hit.Type, hit.ID, hit.Todo = web.SearchTypeTodo, t.Todo.ID, t.Todo.ToDTO()
+			todoIDs = append(todoIDs, t.Todo.ID)
+		}
+		hits = append(hits, hit)
+	}
+
+	tags, err := uc.tagRepository.GetTagByTodoIDs(ctx, todoIDs)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+	for _, hit := range hits {
+		if hit.Todo == nil {
+			continue
+		}
+		for _, g := range tags[hit.ID] {
+			hit.Todo.Tags = append(hit.Todo.Tags, g.ToDTO())
+		}
+	}
+
+	return hits, &web.Pagination{Total: page.Total, Next: page.Next, Prev: page.Prev}, nil
+}
+
+// searchItem is a matching activity group or a matching todo.
+type searchItem struct {
+	activity *entity.ActivityHit
+	todo     *entity.TodoHit
+}
+
+func (item searchItem) key() (interface{}, int64) {
+	if item.activity != nil {
+		return item.activity.Score, query.MixedActivityKey(item.activity.Activity.ID)
+	}
+	return item.todo.Score, query.MixedTodoKey(item.todo.Todo.ID)
+}
+
+// newSearchHit returns a hit for title, scored in units of ScoreScale.
+func newSearchHit(q search.Query, title string, score int64) *web.SearchHitDTO {
+	return &web.SearchHitDTO{
+		Score:     float64(score) / search.ScoreScale,
+		Highlight: search.Highlight(title, search.Matches(q, title), web.SearchHighlightWidth),
+	}
+}
+
+func newTodoFilter(req web.SearchRequest) (filter model.TodoFilter, err error) {
+	switch req.Type {
+	case "", web.SearchTypeTodo, web.SearchTypeActivityGroup:
+	default:
+		return filter, model.ErrInvalidSearchType
+	}
+
+	filter = model.TodoFilter{
+		ActivityGroupID: req.ActivityGroupID,
+		IsActive:        req.IsActive,
+	}
+	if req.Priority != "" {
+		for _, p := range strings.Split(req.Priority, ",") {
+			if entity.PriorityRank(p) == 0 {
+				return filter, model.ErrInvalidPriority
+			}
+			filter.Priorities = append(filter.Priorities, p)
+		}
+	}
+	if req.Status != "" {
+		filter.Statuses = strings.Split(req.Status, ",")
+	}
+	for _, name := range req.Tags {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Tags = append(filter.Tags, name)
+		}
+	}
+	return filter, nil
+}
+
+// todoOnly reports whether the request filters on what only todos have.
+func todoOnly(req web.SearchRequest) bool {
+	return req.IsActive != nil || req.Priority != "" || req.Status != "" || len(req.Tags) > 0
+}
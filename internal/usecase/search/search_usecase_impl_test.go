@@ -0,0 +1,196 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+)
+
+const (
+	alice int64 = 1
+	bob   int64 = 2
+)
+
+// fixture is the data the tests search through: alice is a member of the
+// "Shopping" group of the default workspace, bob of its "Secret" group and of
+// a group of a second workspace.
+type fixture struct {
+	uc                      SearchUC
+	shopping, secret, other *entity.Activity
+	titles                  map[int64]string
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	db := infrastructure.NewMemoryDatabase()
+	w := entity.Workspace{Name: "Other", Slug: "other"}
+	w.ID = db.NextID(w.TableName())
+	db.Workspaces[w.ID] = w
+
+	activities := activity.NewActivityMemoryRepository(db)
+	todos := todo.NewTodoMemoryRepository(db)
+	f := &fixture{
+		uc:     NewSearchUC(activities, todos, tag.NewTagMemoryRepository(db)),
+		titles: make(map[int64]string),
+	}
+	group := func(workspaceID int64, title string, member int64) *entity.Activity {
+		a, err := activities.InsertActivity(model.WithWorkspace(context.Background(), workspaceID), entity.Activity{Title: title})
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.ActivityMembers[entity.MemberKey{ActivityID: a.ID, UserID: member}] = entity.ActivityMember{ActivityID: a.ID, UserID: member, Role: entity.MemberRoleOwner}
+		return a
+	}
+	add := func(a *entity.Activity, titles ...string) {
+		for _, title := range titles {
+			got, err := todos.InsertTodo(model.WithWorkspace(context.Background(), a.WorkspaceID), entity.Todo{ActivityGroupID: a.ID, Title: title})
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.titles[got.ID] = title
+		}
+	}
+
+	f.shopping = group(1, "Shopping juice", alice)
+	f.secret = group(1, "Secret juice", bob)
+	f.other = group(w.ID, "Shopping juice", bob)
+	add(f.shopping, "100% juice", "1000 juice boxes", "snake_case juice", "Wow! Juice deals", "Plain juice")
+	add(f.secret, "100% juice for the secret plan")
+	add(f.other, "100% juice abroad", "snake_case juice abroad")
+	return f
+}
+
+func (f *fixture) search(t *testing.T, user, workspaceID int64, req web.SearchRequest) []*web.SearchHitDTO {
+	t.Helper()
+	ctx := model.WithUser(model.WithWorkspace(context.Background(), workspaceID), user)
+	hits, _, err := f.uc.Search(ctx, req)
+	if err != nil {
+		t.Fatalf("Search(%+v) error = %v", req, err)
+	}
+	return hits
+}
+
+// TestSearchWildcards checks that the characters LIKE treats specially are
+// taken literally: on their own they are no query at all rather than one
+// matching everything.
+func TestSearchWildcards(t *testing.T) {
+	f := newFixture(t)
+	ctx := model.WithUser(model.WithWorkspace(context.Background(), 1), alice)
+	for _, q := range []string{"%", "_", "!", "%%", "!%", "!_", "%_!", " % "} {
+		if hits, _, err := f.uc.Search(ctx, web.SearchRequest{Q: q}); !errors.Is(err, model.ErrSearchQueryRequired) {
+			t.Errorf("Search(%q) = %d hits, %v, want %v", q, len(hits), err, model.ErrSearchQueryRequired)
+		}
+	}
+
+	tests := []struct {
+		q    string
+		best string
+		hits int
+	}{
+		{"100%", "100% juice", 2}, // and "1000 juice boxes", by prefix
+		{"100%juice", "100% juice", 2},
+		{"snake_case", "snake_case juice", 1},
+		{"snake%case", "snake_case juice", 1},
+		{"wow!", "Wow! Juice deals", 1},
+		{"!wow", "Wow! Juice deals", 1},
+	}
+	for _, tt := range tests {
+		hits := f.search(t, alice, 1, web.SearchRequest{Q: tt.q, Type: web.SearchTypeTodo})
+		if len(hits) != tt.hits {
+			t.Errorf("Search(%q) = %d hits, want %d", tt.q, len(hits), tt.hits)
+			continue
+		}
+		if got := f.titles[hits[0].ID]; got != tt.best {
+			t.Errorf("Search(%q) best hit = %q, want %q", tt.q, got, tt.best)
+		}
+	}
+}
+
+// TestSearchScope checks that a search only ever returns what the user may
+// see: the groups they are a member of, in the workspace of the request.
+func TestSearchScope(t *testing.T) {
+	f := newFixture(t)
+	tests := []struct {
+		name   string
+		user   int64
+		ws     int64
+		req    web.SearchRequest
+		groups []int64
+	}{
+		{"member", alice, 1, web.SearchRequest{Q: "juice"}, []int64{f.shopping.ID}},
+		{"other member", bob, 1, web.SearchRequest{Q: "juice"}, []int64{f.secret.ID}},
+		{"other workspace", bob, f.other.WorkspaceID, web.SearchRequest{Q: "juice"}, []int64{f.other.ID}},
+		{"non-member group", alice, 1, web.SearchRequest{Q: "juice", ActivityGroupID: f.secret.ID}, nil},
+		{"group of another workspace", bob, 1, web.SearchRequest{Q: "juice", ActivityGroupID: f.other.ID}, nil},
+		{"groups only", alice, 1, web.SearchRequest{Q: "shopping", Type: web.SearchTypeActivityGroup}, []int64{f.shopping.ID}},
+		{"no membership", alice, f.other.WorkspaceID, web.SearchRequest{Q: "juice"}, nil},
+	}
+	for _, tt := range tests {
+		allowed := make(map[int64]bool)
+		for _, id := range tt.groups {
+			allowed[id] = true
+		}
+		hits := f.search(t, tt.user, tt.ws, tt.req)
+		for _, hit := range hits {
+			groupID := hit.ID
+			if hit.Todo != nil {
+				groupID = hit.Todo.ActivityGroupID
+			}
+			if !allowed[groupID] {
+				t.Errorf("%s: hit %s %d of group %d, want only groups %v", tt.name, hit.Type, hit.ID, groupID, tt.groups)
+			}
+		}
+		if len(tt.groups) > 0 && len(hits) == 0 {
+			t.Errorf("%s: no hits, want some from groups %v", tt.name, tt.groups)
+		}
+	}
+}
+
+// TestSearchPages checks that following the next cursor walks through every
+// hit of both kinds once, in the order of a single page holding them all.
+func TestSearchPages(t *testing.T) {
+	f := newFixture(t)
+	ctx := model.WithUser(model.WithWorkspace(context.Background(), 1), alice)
+	all, page, err := f.uc.Search(ctx, web.SearchRequest{Q: "juice", Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(all)) != page.Total || page.Next != "" {
+		t.Fatalf("Search = %d hits, total %d, next %q, want all of them", len(all), page.Total, page.Next)
+	}
+
+	var got []*web.SearchHitDTO
+	req := web.SearchRequest{Q: "juice", Limit: 2}
+	for i := 0; i <= len(all); i++ {
+		hits, page, err := f.uc.Search(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, hits...)
+		if page.Next == "" {
+			break
+		}
+		req.Cursor = page.Next
+	}
+	if len(got) != len(all) {
+		t.Fatalf("pages = %d hits, want %d", len(got), len(all))
+	}
+	for i := range all {
+		if got[i].Type != all[i].Type || got[i].ID != all[i].ID {
+			t.Errorf("hit %d = %s %d, want %s %d", i, got[i].Type, got[i].ID, all[i].Type, all[i].ID)
+		}
+	}
+
+	req.Cursor = "not a cursor"
+	if _, _, err := f.uc.Search(ctx, req); !errors.Is(err, model.ErrInvalidCursor) {
+		t.Errorf("Search with a bad cursor error = %v, want %v", err, model.ErrInvalidCursor)
+	}
+}
@@ -0,0 +1,15 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type TagUC interface {
+	CreateTag(ctx context.Context, req web.TagCreateRequest) (*web.TagDTO, error)
+	GetTagByID(ctx context.Context, id int64) (*web.TagDTO, error)
+	GetAllTag(ctx context.Context) ([]*web.TagDTO, error)
+	UpdateTag(ctx context.Context, req web.TagUpdateRequest) (*web.TagDTO, error)
+	DeleteTag(ctx context.Context, id int64) error
+}
@@ -0,0 +1,139 @@
+package tag
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+type TagUCImpl struct {
+	tagRepository tag.TagRepository
+	txManager     transaction.TxManager
+}
+
+func NewTagUC(tagRepository tag.TagRepository, txManager transaction.TxManager) TagUC {
+	return &TagUCImpl{
+		tagRepository: tagRepository,
+		txManager:     txManager,
+	}
+}
+
+func (uc *TagUCImpl) CreateTag(ctx context.Context, req web.TagCreateRequest) (*web.TagDTO, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.Tag
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if err = uc.checkName(ctx, 0, req.Name); err != nil {
+			return err
+		}
+		got, err = uc.tagRepository.InsertTag(ctx, entity.Tag{
+			Name:  req.Name,
+			Color: color(req.Color),
+		})
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+func (uc *TagUCImpl) GetTagByID(ctx context.Context, id int64) (*web.TagDTO, error) {
+	got, err := uc.tagRepository.GetTagByID(ctx, id)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// GetAllTag lists every tag by name.
+func (uc *TagUCImpl) GetAllTag(ctx context.Context) ([]*web.TagDTO, error) {
+	got, err := uc.tagRepository.GetAllTag(ctx)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.TagDTO, 0)
+	for _, t := range got {
+		res = append(res, t.ToDTO())
+	}
+	return res, nil
+}
+
+func (uc *TagUCImpl) UpdateTag(ctx context.Context, req web.TagUpdateRequest) (*web.TagDTO, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.Tag
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		tag, err := uc.tagRepository.GetTagByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Name != "" {
+			if err = uc.checkName(ctx, tag.ID, req.Name); err != nil {
+				return err
+			}
+			tag.Name = req.Name
+		}
+		if req.Color != nil {
+			tag.Color = color(*req.Color)
+		}
+
+		got, err = uc.tagRepository.UpdateTag(ctx, *tag)
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// DeleteTag removes a tag and takes it off every todo carrying it.
+func (uc *TagUCImpl) DeleteTag(ctx context.Context, id int64) error {
+	if err := uc.tagRepository.DeleteTag(ctx, id); err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// checkName rejects a name another tag already uses, ignoring case.
+func (uc *TagUCImpl) checkName(ctx context.Context, id int64, name string) error {
+	existing, err := uc.tagRepository.GetTagByName(ctx, name)
+	switch {
+	case apperror.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	case existing.ID != id:
+		return model.ErrTagNameTaken.WithMessage("a tag named %q already exists", existing.Name)
+	}
+	return nil
+}
+
+// color stores an empty color as NULL and hex digits in lower case.
+func color(c string) *string {
+	if c == "" {
+		return nil
+	}
+	c = strings.ToLower(c)
+	return &c
+}
@@ -8,8 +8,25 @@ import (
 
 type TodoUC interface {
 	CreateTodo(ctx context.Context, req web.TodoCreateRequest) (*web.TodoDTO, error)
+	CreateSubtask(ctx context.Context, req web.SubtaskCreateRequest) (*web.TodoDTO, error)
 	GetTodoByID(ctx context.Context, id int64) (*web.TodoDTO, error)
-	GetAllTodo(ctx context.Context, activityGroupID int64) ([]*web.TodoDTO, error)
+	GetSubtasks(ctx context.Context, id int64) ([]*web.TodoDTO, error)
+	GetAllTodo(ctx context.Context, req web.TodoListRequest) ([]*web.TodoDTO, *web.Pagination, error)
+	GetTodoView(ctx context.Context, view string, req web.TodoViewRequest) ([]*web.TodoDTO, *web.Pagination, error)
 	UpdateTodo(ctx context.Context, req web.TodoUpdateRequest) (*web.TodoDTO, error)
+	MoveTodo(ctx context.Context, req web.TodoMoveRequest) (*web.TodoDTO, error)
+	MoveActivityTodos(ctx context.Context, fromID, toID int64) error
 	DeleteTodo(ctx context.Context, id int64) error
+	BulkTodo(ctx context.Context, req web.TodoBulkRequest) ([]*web.TodoBulkResult, error)
+	RestoreTodo(ctx context.Context, id int64) (*web.TodoDTO, error)
+	GetTodoHistory(ctx context.Context, id int64) ([]*web.EventDTO, error)
+	GetTodoTransitions(ctx context.Context, id int64) ([]*web.TodoTransitionDTO, error)
+	AddTodoTag(ctx context.Context, req web.TodoTagRequest) (*web.TodoDTO, error)
+	RemoveTodoTag(ctx context.Context, req web.TodoTagRequest) (*web.TodoDTO, error)
+	AddTodoWatcher(ctx context.Context, req web.TodoWatcherRequest) (*web.TodoDTO, error)
+	RemoveTodoWatcher(ctx context.Context, req web.TodoWatcherRequest) (*web.TodoDTO, error)
+	CreateComment(ctx context.Context, req web.CommentCreateRequest) (*web.CommentDTO, error)
+	GetComments(ctx context.Context, req web.CommentListRequest) ([]*web.CommentDTO, *web.Pagination, error)
+	UpdateComment(ctx context.Context, req web.CommentUpdateRequest) (*web.CommentDTO, error)
+	DeleteComment(ctx context.Context, todoID, id int64) error
 }
@@ -0,0 +1,290 @@
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+// BulkTodo runs the operations of req in order within one transaction. When
+// an operation fails nothing is applied and the error names the operation.
+func (uc *TodoUCImpl) BulkTodo(ctx context.Context, req web.TodoBulkRequest) ([]*web.TodoBulkResult, error) {
+	if len(req.Operations) == 0 || len(req.Operations) > web.MaxBulkOperations {
+		return nil, model.ErrInvalidOperationCount
+	}
+
+	results := make([]*web.TodoBulkResult, 0, len(req.Operations))
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		results = results[:0]
+		for i, op := range req.Operations {
+			res, err := uc.runBulkOperation(ctx, op)
+			if err != nil {
+				return bulkError(i, err)
+			}
+			res.Index = i
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+func (uc *TodoUCImpl) runBulkOperation(ctx context.Context, op web.TodoBulkOperation) (*web.TodoBulkResult, error) {
+	res := &web.TodoBulkResult{Op: op.Op}
+	switch op.Op {
+	case web.BulkOpCreate:
+		if op.ID != 0 || op.IDs != nil || op.Filter != nil {
+			return nil, model.ErrInvalidTarget.WithMessage("create takes no id, ids or filter")
+		}
+		var data web.TodoCreateRequest
+		if err := decodeBulkData(op.Data, &data); err != nil {
+			return nil, err
+		}
+		got, err := uc.CreateTodo(ctx, data)
+		if err != nil {
+			return nil, prefixFields(err, "data")
+		}
+		res.Todos = []*web.TodoDTO{got}
+
+	case web.BulkOpMove:
+		if op.ID == 0 || op.IDs != nil || op.Filter != nil {
+			return nil, model.ErrInvalidTarget.WithMessage("move takes an id and no ids or filter")
+		}
+		var data web.TodoMoveRequest
+		if err := decodeBulkData(op.Data, &data); err != nil {
+			return nil, err
+		}
+		data.ID = op.ID
+		got, err := uc.MoveTodo(ctx, data)
+		if err != nil {
+			return nil, prefixFields(err, "data")
+		}
+		res.Todos = []*web.TodoDTO{got}
+
+	case web.BulkOpUpdate:
+		var data web.TodoUpdateRequest
+		if err := decodeBulkData(op.Data, &data); err != nil {
+			return nil, err
+		}
+		if data.Version != nil && op.ID == 0 {
+			return nil, model.ErrInvalidTarget.WithMessage("version can only be given together with an id")
+		}
+		todos, err := uc.bulkTargets(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		if data.Version != nil && *data.Version != todos[0].Version {
+			return nil, model.ErrVersionMismatch
+		}
+		got, err := uc.updateTodos(ctx, todos, data)
+		if err != nil {
+			return nil, prefixFields(err, "data")
+		}
+		if res.Todos, err = uc.toDTOs(ctx, got); err != nil {
+			return nil, err
+		}
+
+	case web.BulkOpDelete:
+		if len(op.Data) > 0 {
+			return nil, model.ErrInvalidTarget.WithMessage("delete takes no data")
+		}
+		todos, err := uc.bulkTargets(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		if err = uc.deleteTodos(ctx, todos, time.Now().UTC().Truncate(time.Second)); err != nil {
+			return nil, err
+		}
+		for _, t := range todos {
+			res.IDs = append(res.IDs, t.ID)
+		}
+		return res, nil
+
+	default:
+		return nil, model.ErrInvalidOperation
+	}
+
+	for _, t := range res.Todos {
+		res.IDs = append(res.IDs, t.ID)
+	}
+	return res, nil
+}
+
+// bulkTargets returns the todos an update or delete operation applies to,
+// which the user of ctx has to be allowed to edit.
+func (uc *TodoUCImpl) bulkTargets(ctx context.Context, op web.TodoBulkOperation) ([]*entity.Todo, error) {
+	todos, err := uc.findBulkTargets(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	checked := make(map[int64]bool)
+	for _, t := range todos {
+		if checked[t.ActivityGroupID] {
+			continue
+		}
+		if err = uc.authorize(ctx, t.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return nil, err
+		}
+		checked[t.ActivityGroupID] = true
+	}
+	return todos, nil
+}
+
+func (uc *TodoUCImpl) findBulkTargets(ctx context.Context, op web.TodoBulkOperation) ([]*entity.Todo, error) {
+	targets := 0
+	for _, given := range []bool{op.ID != 0, op.IDs != nil, op.Filter != nil} {
+		if given {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, model.ErrInvalidTarget.WithMessage("%s takes exactly one of id, ids or filter", op.Op)
+	}
+
+	if op.Filter != nil {
+		filter, err := newTodoFilter(ctx, web.TodoListRequest{
+			ActivityGroupID: op.Filter.ActivityGroupID,
+			IsActive:        op.Filter.IsActive,
+			Priority:        op.Filter.Priority,
+			Status:          op.Filter.Status,
+			Title:           op.Filter.Title,
+			DueAfter:        op.Filter.DueAfter,
+			DueBefore:       op.Filter.DueBefore,
+			Overdue:         op.Filter.Overdue,
+			SeriesID:        op.Filter.SeriesID,
+			ParentTodoID:    op.Filter.ParentTodoID,
+			Tags:            op.Filter.Tags,
+			TagMode:         op.Filter.TagMode,
+			Assignee:        op.Filter.Assignee,
+		})
+		if err != nil {
+			return nil, prefixFields(err, "filter")
+		}
+		// Tag matching on its own narrows nothing down.
+		filter.TagMatchAll = false
+		if reflect.DeepEqual(filter, model.TodoFilter{}) {
+			return nil, model.ErrEmptyFilter
+		}
+		todos, err := uc.todoRepository.GetTodoByFilter(ctx, filter, web.MaxBulkTodos+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(todos) > web.MaxBulkTodos {
+			return nil, model.ErrTooManyTodos
+		}
+		return todos, nil
+	}
+
+	ids := op.IDs
+	if op.ID != 0 {
+		ids = []int64{op.ID}
+	}
+	if len(ids) == 0 || len(ids) > web.MaxBulkTodos {
+		return nil, model.ErrInvalidTarget.WithMessage("ids must hold between 1 and %d ids", web.MaxBulkTodos)
+	}
+	for _, id := range ids {
+		if id < 1 {
+			return nil, model.ErrInvalidID
+		}
+	}
+	todos, err := uc.todoRepository.GetTodoByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]bool, len(todos))
+	for _, t := range todos {
+		found[t.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, model.ErrTodoNotFound.WithMessage("Todo with ID %v Not Found", id)
+		}
+	}
+	return todos, nil
+}
+
+// updateTodos applies req to every todo and saves them in one batch.
+func (uc *TodoUCImpl) updateTodos(ctx context.Context, todos []*entity.Todo, req web.TodoUpdateRequest) ([]*entity.Todo, error) {
+	workflows := make(map[int64]*entity.Workflow)
+	changes := make([]todoChange, 0, len(todos))
+	for _, todo := range todos {
+		workflow, ok := workflows[todo.ActivityGroupID]
+		if !ok {
+			activity, err := uc.activityRepository.GetActivityByID(ctx, todo.ActivityGroupID)
+			if err != nil {
+				return nil, err
+			}
+			workflow = activity.WorkflowOrDefault()
+			workflows[todo.ActivityGroupID] = workflow
+		}
+
+		before := *todo
+		if err := applyTodoUpdate(todo, workflow, req); err != nil {
+			var appErr *apperror.Error
+			if len(todos) > 1 && errors.As(err, &appErr) {
+				return nil, appErr.WithMessage("todo %v: %s", todo.ID, appErr.Message)
+			}
+			return nil, err
+		}
+		changes = append(changes, todoChange{before: &before, after: todo, workflow: workflow})
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return uc.saveTodos(ctx, changes)
+}
+
+// decodeBulkData decodes the data of an operation into dst. Operations
+// without data decode as an empty object.
+func decodeBulkData(data json.RawMessage, dst interface{}) error {
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	return prefixFields(validation.DecodeJSON(data, dst), "data")
+}
+
+// bulkError names the operation at index in err, so clients can tell which
+// operation rolled the request back.
+func bulkError(index int, err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind == apperror.KindInternal || appErr.Kind == apperror.KindTimeout {
+		return err
+	}
+
+	field := fmt.Sprintf("operations[%d]", index)
+	named := appErr.WithMessage("operation %d: %s", index, appErr.Message)
+	if len(named.Fields) == 0 {
+		named.Fields = []apperror.FieldError{{Field: field, Code: appErr.Code, Message: appErr.Message}}
+		return named
+	}
+	return prefixFields(named, field)
+}
+
+// prefixFields returns err with the fields it reports moved under prefix.
+func prefixFields(err error, prefix string) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+		return err
+	}
+	prefixed := *appErr
+	prefixed.Fields = make([]apperror.FieldError, 0, len(appErr.Fields))
+	for _, f := range appErr.Fields {
+		f.Field = prefix + "." + f.Field
+		prefixed.Fields = append(prefixed.Fields, f)
+	}
+	return &prefixed
+}
@@ -0,0 +1,155 @@
+package todo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+// CreateComment adds a comment to a todo on behalf of the user of ctx. Any
+// member of the activity group may comment, and members the body newly
+// mentions start watching the todo.
+func (uc *TodoUCImpl) CreateComment(ctx context.Context, req web.CommentCreateRequest) (*web.CommentDTO, error) {
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, model.ErrUnauthenticated
+	}
+
+	var got *entity.TodoComment
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		todo, err := uc.todoRepository.GetTodoByID(ctx, req.TodoID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleViewer); err != nil {
+			return err
+		}
+		got, err = uc.commentRepository.InsertComment(ctx, entity.TodoComment{TodoID: todo.ID, UserID: userID, Body: req.Body})
+		if err != nil {
+			return err
+		}
+		if err = uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoCommentEvent(todo.ID, nil, got, model.ActorFromContext(ctx))); err != nil {
+			return err
+		}
+		return uc.watchMentions(ctx, todo, "", got.Body)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// GetComments lists the comments of a todo, oldest first unless another
+// sort is requested.
+func (uc *TodoUCImpl) GetComments(ctx context.Context, req web.CommentListRequest) ([]*web.CommentDTO, *web.Pagination, error) {
+	pagination, err := model.NewPagination(req.Sort, req.Order, req.Limit, req.Offset, req.Cursor, "id", "created_at")
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = uc.todoRepository.GetTodoByID(ctx, req.TodoID); err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+
+	got, page, err := uc.commentRepository.GetCommentByTodoID(ctx, req.TodoID, pagination)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+
+	res := make([]*web.CommentDTO, 0, len(got))
+	for _, c := range got {
+		res = append(res, c.ToDTO())
+	}
+	return res, &web.Pagination{Total: page.Total, Next: page.Next, Prev: page.Prev}, nil
+}
+
+// UpdateComment replaces the body of a comment. Only its author may edit
+// it; an unchanged body records nothing.
+func (uc *TodoUCImpl) UpdateComment(ctx context.Context, req web.CommentUpdateRequest) (*web.CommentDTO, error) {
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.TodoComment
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		todo, before, err := uc.authorComment(ctx, req.TodoID, req.ID)
+		if err != nil {
+			return err
+		}
+		if before.Body == req.Body {
+			got = before
+			return nil
+		}
+		after := *before
+		after.Body = req.Body
+		if got, err = uc.commentRepository.UpdateComment(ctx, after); err != nil {
+			return err
+		}
+		if err = uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoCommentEvent(todo.ID, before, got, model.ActorFromContext(ctx))); err != nil {
+			return err
+		}
+		return uc.watchMentions(ctx, todo, before.Body, got.Body)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return got.ToDTO(), nil
+}
+
+// DeleteComment removes a comment. Only its author may delete it.
+func (uc *TodoUCImpl) DeleteComment(ctx context.Context, todoID, id int64) error {
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		todo, before, err := uc.authorComment(ctx, todoID, id)
+		if err != nil {
+			return err
+		}
+		if err = uc.commentRepository.DeleteComment(ctx, before.ID); err != nil {
+			return err
+		}
+		return uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoCommentEvent(todo.ID, before, nil, model.ActorFromContext(ctx)))
+	})
+	if err != nil {
+		logrus.Error(err)
+	}
+	return err
+}
+
+// authorComment returns a comment of a todo that the user of ctx wrote and
+// is still a member of the activity group of.
+func (uc *TodoUCImpl) authorComment(ctx context.Context, todoID, id int64) (*entity.Todo, *entity.TodoComment, error) {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, nil, model.ErrUnauthenticated
+	}
+	todo, err := uc.todoRepository.GetTodoByID(ctx, todoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleViewer); err != nil {
+		return nil, nil, err
+	}
+	c, err := uc.commentRepository.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.TodoID != todo.ID {
+		return nil, nil, model.ErrCommentNotFound.WithMessage("Comment with ID %v Not Found", id)
+	}
+	if c.UserID != userID {
+		return nil, nil, model.ErrNotCommentAuthor
+	}
+	return todo, c, nil
+}
@@ -2,110 +2,1517 @@ package todo
 
 import (
 	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/mention"
 	"github.com/vnnyx/golang-todo-api/internal/model"
 	"github.com/vnnyx/golang-todo-api/internal/model/entity"
 	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/rank"
+	"github.com/vnnyx/golang-todo-api/internal/recurrence"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/comment"
+	"github.com/vnnyx/golang-todo-api/internal/repository/event"
+	"github.com/vnnyx/golang-todo-api/internal/repository/member"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
 	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/watcher"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
 )
 
 type TodoUCImpl struct {
-	todoRepository todo.TodoRepository
+	todoRepository     todo.TodoRepository
+	activityRepository activity.ActivityRepository
+	eventRepository    event.EventRepository
+	tagRepository      tag.TagRepository
+	memberRepository   member.MemberRepository
+	watcherRepository  watcher.WatcherRepository
+	commentRepository  comment.CommentRepository
+	txManager          transaction.TxManager
 }
 
-func NewTodoUC(todoRepository todo.TodoRepository) TodoUC {
+func NewTodoUC(todoRepository todo.TodoRepository, activityRepository activity.ActivityRepository, eventRepository event.EventRepository, tagRepository tag.TagRepository, memberRepository member.MemberRepository, watcherRepository watcher.WatcherRepository, commentRepository comment.CommentRepository, txManager transaction.TxManager) TodoUC {
 	return &TodoUCImpl{
-		todoRepository: todoRepository,
+		todoRepository:     todoRepository,
+		activityRepository: activityRepository,
+		eventRepository:    eventRepository,
+		tagRepository:      tagRepository,
+		memberRepository:   memberRepository,
+		watcherRepository:  watcherRepository,
+		commentRepository:  commentRepository,
+		txManager:          txManager,
 	}
 }
 
 func (uc *TodoUCImpl) CreateTodo(ctx context.Context, req web.TodoCreateRequest) (*web.TodoDTO, error) {
-	if req.ActivityGroupID == 0 {
-		return nil, model.ErrActivityGroupIDCannotBeNull
+	if err := validation.Struct(req); err != nil {
+		return nil, err
 	}
-	if req.Title == "" {
-		return nil, model.ErrTitleCannotBeNull
+	startAt, dueAt := normalizeTime(req.StartAt), normalizeTime(req.DueAt)
+	if err := checkDates(startAt, dueAt); err != nil {
+		return nil, err
 	}
-	isActive := true
-	if req.IsActive != nil {
-		isActive = *req.IsActive
+	rule, err := parseRecurrence(req.Recurrence)
+	if err != nil {
+		return nil, err
 	}
-	got, err := uc.todoRepository.InsertTodo(entity.Todo{
-		ActivityGroupID: req.ActivityGroupID,
-		Title:           req.Title,
-		IsActive:        isActive,
+
+	var got *entity.Todo
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ActivityGroupID)
+		if err != nil {
+			if apperror.IsNotFound(err) {
+				return model.ErrActivityGroupNotFound
+			}
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		if req.AssigneeID != nil {
+			if err = uc.checkAssignee(ctx, activity.ID, *req.AssigneeID); err != nil {
+				return err
+			}
+		}
+		if req.ParentTodoID != nil {
+			parent, err := uc.todoRepository.GetTodoByID(ctx, *req.ParentTodoID)
+			switch {
+			case apperror.IsNotFound(err):
+				return model.ErrParentTodoNotFound
+			case err != nil:
+				return err
+			case parent.ParentTodoID != nil:
+				return model.ErrNestedSubtask
+			case parent.ActivityGroupID != req.ActivityGroupID:
+				return model.ErrParentGroupMismatch
+			}
+		}
+		status, isActive, err := resolveStatus(activity.WorkflowOrDefault(), "", req.Status, req.IsActive)
+		if err != nil {
+			return err
+		}
+		siblings, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+		position, err := uc.rankAt(ctx, siblings, len(siblings))
+		if err != nil {
+			return err
+		}
+
+		got, err = uc.todoRepository.InsertTodo(ctx, entity.Todo{
+			ActivityGroupID: req.ActivityGroupID,
+			Title:           req.Title,
+			IsActive:        isActive,
+			Status:          status,
+			StartAt:         startAt,
+			DueAt:           dueAt,
+			Recurrence:      rule,
+			ParentTodoID:    req.ParentTodoID,
+			AutoComplete:    req.AutoComplete,
+			Position:        position,
+			AssigneeID:      req.AssigneeID,
+		})
+		if err != nil {
+			return err
+		}
+		if err = uc.recordTransition(ctx, got.ID, nil, got.Status); err != nil {
+			return err
+		}
+		if err = uc.recordEvent(ctx, entity.EventActionCreate, nil, got); err != nil {
+			return err
+		}
+		return uc.watchMentions(ctx, got, "", got.Title)
 	})
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
 
-	return got.ToDTO(), nil
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// CreateSubtask adds a subtask to a todo, in the activity group of the todo.
+func (uc *TodoUCImpl) CreateSubtask(ctx context.Context, req web.SubtaskCreateRequest) (*web.TodoDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	parent, err := uc.todoRepository.GetTodoByID(ctx, req.ParentTodoID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	return uc.CreateTodo(ctx, web.TodoCreateRequest{
+		Title:           req.Title,
+		ActivityGroupID: parent.ActivityGroupID,
+		IsActive:        req.IsActive,
+		Status:          req.Status,
+		StartAt:         req.StartAt,
+		DueAt:           req.DueAt,
+		ParentTodoID:    &parent.ID,
+	})
 }
 
 func (uc *TodoUCImpl) GetTodoByID(ctx context.Context, id int64) (*web.TodoDTO, error) {
-	got, err := uc.todoRepository.GetTodoByID(id)
+	got, err := uc.todoRepository.GetTodoByID(ctx, id)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
 
-	return got.ToDTO(), nil
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
 }
 
-func (uc *TodoUCImpl) GetAllTodo(ctx context.Context, activityGroupID int64) ([]*web.TodoDTO, error) {
-	got, err := uc.todoRepository.GetAllTodo(activityGroupID)
+// GetSubtasks lists the subtasks of a todo in position order.
+func (uc *TodoUCImpl) GetSubtasks(ctx context.Context, id int64) ([]*web.TodoDTO, error) {
+	if _, err := uc.todoRepository.GetTodoByID(ctx, id); err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	got, err := uc.todoRepository.GetTodoByParentIDs(ctx, []int64{id})
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
 
-	res := make([]*web.TodoDTO, 0)
-	for _, t := range got {
-		res = append(res, t.ToDTO())
+	res, err := uc.toDTOs(ctx, got)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetAllTodo lists todos in the manual order of their activity group unless
+// another sort is requested.
+func (uc *TodoUCImpl) GetAllTodo(ctx context.Context, req web.TodoListRequest) ([]*web.TodoDTO, *web.Pagination, error) {
+	if req.Sort == "" && req.Cursor == "" {
+		req.Sort = "position"
+	}
+	pagination, err := newTodoPagination(req.PageRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := newTodoFilter(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch req.Layout {
+	case "", web.TodoLayoutFlat:
+		return uc.listTodo(ctx, filter, pagination)
+	case web.TodoLayoutTree:
+		// Pages are made of top level todos only; their subtasks come
+		// along whatever the filters.
+		filter.TopLevel = true
+		res, page, err := uc.listTodo(ctx, filter, pagination)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = uc.nestSubtasks(ctx, res); err != nil {
+			logrus.Error(err)
+			return nil, nil, err
+		}
+		return res, page, nil
+	default:
+		return nil, nil, model.ErrInvalidLayout
+	}
+}
+
+// GetTodoView lists the open todos of a view across all activity groups,
+// soonest due first unless another sort is requested: those due today,
+// upcoming or overdue, or those assigned to the current user. Days are
+// counted in the requested timezone.
+func (uc *TodoUCImpl) GetTodoView(ctx context.Context, view string, req web.TodoViewRequest) ([]*web.TodoDTO, *web.Pagination, error) {
+	if req.Sort == "" && req.Cursor == "" {
+		req.Sort = "due_at"
+	}
+	pagination, err := newTodoPagination(req.PageRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := newTodoFilter(ctx, req.TodoListRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := model.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.Days == 0 {
+		req.Days = web.DefaultUpcomingDays
+	}
+	if req.Days < 1 || req.Days > web.MaxUpcomingDays {
+		return nil, nil, model.ErrInvalidDays
+	}
+
+	isActive := true
+	filter.IsActive = &isActive
+	today := model.StartOfDay(time.Now(), loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	switch view {
+	case web.TodoViewToday:
+		filter.DueAfter, filter.DueBefore = latest(filter.DueAfter, today), earliest(filter.DueBefore, tomorrow)
+	case web.TodoViewUpcoming:
+		filter.DueAfter, filter.DueBefore = latest(filter.DueAfter, tomorrow), earliest(filter.DueBefore, tomorrow.AddDate(0, 0, req.Days))
+	case web.TodoViewOverdue:
+		overdue := true
+		filter.Overdue = &overdue
+	case web.TodoViewMine:
+		userID, ok := model.UserFromContext(ctx)
+		if !ok {
+			return nil, nil, model.ErrUnauthenticated
+		}
+		filter.AssigneeID, filter.Unassigned = userID, false
+	}
+	return uc.listTodo(ctx, filter, pagination)
+}
+
+func (uc *TodoUCImpl) listTodo(ctx context.Context, filter model.TodoFilter, pagination model.Pagination) ([]*web.TodoDTO, *web.Pagination, error) {
+	got, page, err := uc.todoRepository.GetAllTodo(ctx, filter, pagination)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
 	}
 
+	res, err := uc.toDTOs(ctx, got)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+
+	return res, &web.Pagination{Total: page.Total, Next: page.Next, Prev: page.Prev}, nil
+}
+
+// toDTOs converts todos, adding their tags, their watchers, their comment
+// count and the subtask progress of those that have subtasks.
+func (uc *TodoUCImpl) toDTOs(ctx context.Context, todos []*entity.Todo) ([]*web.TodoDTO, error) {
+	ids := make([]int64, 0, len(todos))
+	for _, t := range todos {
+		ids = append(ids, t.ID)
+	}
+	progress, err := uc.todoRepository.CountTodoByParentIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := uc.tagRepository.GetTagByTodoIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	watchers, err := uc.watcherRepository.GetWatcherByTodoIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := uc.commentRepository.CountCommentByTodoIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*web.TodoDTO, 0, len(todos))
+	for _, t := range todos {
+		dto := t.ToDTO()
+		if p, ok := progress[t.ID]; ok {
+			dto.Progress = p.ToDTO()
+		}
+		for _, g := range tags[t.ID] {
+			dto.Tags = append(dto.Tags, g.ToDTO())
+		}
+		for _, w := range watchers[t.ID] {
+			dto.Watchers = append(dto.Watchers, w.ToDTO())
+		}
+		dto.CommentCount = comments[t.ID]
+		res = append(res, dto)
+	}
 	return res, nil
 }
 
+func (uc *TodoUCImpl) nestSubtasks(ctx context.Context, parents []*web.TodoDTO) error {
+	ids := make([]int64, 0, len(parents))
+	byID := make(map[int64]*web.TodoDTO, len(parents))
+	for _, p := range parents {
+		ids = append(ids, p.ID)
+		byID[p.ID] = p
+	}
+	subtasks, err := uc.todoRepository.GetTodoByParentIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	dtos, err := uc.toDTOs(ctx, subtasks)
+	if err != nil {
+		return err
+	}
+	for _, t := range dtos {
+		parent := byID[*t.ParentTodoID]
+		parent.Subtasks = append(parent.Subtasks, t)
+	}
+	return nil
+}
+
+func newTodoPagination(req web.PageRequest) (model.Pagination, error) {
+	return model.NewPagination(req.Sort, req.Order, req.Limit, req.Offset, req.Cursor,
+		"id", "title", "priority", "created_at", "updated_at", "start_at", "due_at", "position")
+}
+
+func newTodoFilter(ctx context.Context, req web.TodoListRequest) (filter model.TodoFilter, err error) {
+	filter = model.TodoFilter{
+		ActivityGroupID: req.ActivityGroupID,
+		IsActive:        req.IsActive,
+		Title:           req.Title,
+	}
+	if req.Priority != "" {
+		for _, p := range strings.Split(req.Priority, ",") {
+			if entity.PriorityRank(p) == 0 {
+				return filter, model.ErrInvalidPriority
+			}
+			filter.Priorities = append(filter.Priorities, p)
+		}
+	}
+	if req.Status != "" {
+		filter.Statuses = strings.Split(req.Status, ",")
+	}
+	for _, d := range []struct {
+		field string
+		value string
+		dest  **time.Time
+	}{
+		{"created_after", req.CreatedAfter, &filter.CreatedAfter},
+		{"created_before", req.CreatedBefore, &filter.CreatedBefore},
+		{"updated_after", req.UpdatedAfter, &filter.UpdatedAfter},
+		{"updated_before", req.UpdatedBefore, &filter.UpdatedBefore},
+		{"due_after", req.DueAfter, &filter.DueAfter},
+		{"due_before", req.DueBefore, &filter.DueBefore},
+	} {
+		if *d.dest, err = model.ParseTime(d.value); err != nil {
+			return filter, model.ErrInvalidDate.WithField(d.field, d.field+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+	}
+	filter.Overdue = req.Overdue
+	filter.SeriesID = req.SeriesID
+	filter.ParentTodoID = req.ParentTodoID
+	if filter.AssigneeID, filter.Unassigned, err = parseAssignee(ctx, req.Assignee); err != nil {
+		return filter, err
+	}
+
+	switch req.TagMode {
+	case "", web.TagModeAny:
+	case web.TagModeAll:
+		filter.TagMatchAll = true
+	default:
+		return filter, model.ErrInvalidTagMode
+	}
+	// Tag names are unique regardless of case, so duplicates would throw
+	// off the count of an all match.
+	seen := make(map[string]bool)
+	for _, name := range req.Tags {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		filter.Tags = append(filter.Tags, name)
+	}
+	return filter, nil
+}
+
+// parseAssignee reads the assignee filter: me for the user of ctx, none for
+// unassigned todos or the id of a user.
+func parseAssignee(ctx context.Context, assignee string) (userID int64, unassigned bool, err error) {
+	switch assignee {
+	case "":
+		return 0, false, nil
+	case web.AssigneeMe:
+		userID, ok := model.UserFromContext(ctx)
+		if !ok {
+			return 0, false, model.ErrInvalidAssignee.WithMessage("assignee cannot be me without signing in")
+		}
+		return userID, false, nil
+	case web.AssigneeNone:
+		return 0, true, nil
+	}
+	userID, err = strconv.ParseInt(assignee, 10, 64)
+	if err != nil || userID < 1 {
+		return 0, false, model.ErrInvalidAssignee
+	}
+	return userID, false, nil
+}
+
+func latest(t *time.Time, bound time.Time) *time.Time {
+	if t != nil && t.After(bound) {
+		return t
+	}
+	return &bound
+}
+
+func earliest(t *time.Time, bound time.Time) *time.Time {
+	if t != nil && t.Before(bound) {
+		return t
+	}
+	return &bound
+}
+
 func (uc *TodoUCImpl) UpdateTodo(ctx context.Context, req web.TodoUpdateRequest) (*web.TodoDTO, error) {
-	isActive := true
-	if req.IsActive != nil {
-		isActive = *req.IsActive
+	if err := validation.Struct(req); err != nil {
+		return nil, err
 	}
-	todo, err := uc.todoRepository.GetTodoByID(req.ID)
+
+	var got *entity.Todo
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		todo, err := uc.todoRepository.GetTodoByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		if req.Version != nil && *req.Version != todo.Version {
+			return model.ErrVersionMismatch
+		}
+
+		activity, err := uc.activityRepository.GetActivityByID(ctx, todo.ActivityGroupID)
+		if err != nil {
+			return err
+		}
+		workflow := activity.WorkflowOrDefault()
+
+		before := *todo
+		if err = applyTodoUpdate(todo, workflow, req); err != nil {
+			return err
+		}
+		got, err = uc.saveTodo(ctx, &before, todo, workflow)
+		return err
+	})
 	if err != nil {
+		logrus.Error(err)
 		return nil, err
 	}
 
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// applyTodoUpdate applies the changes req asks for to todo, a todo of an
+// activity group with the given workflow.
+func applyTodoUpdate(todo *entity.Todo, workflow *entity.Workflow, req web.TodoUpdateRequest) error {
+	status, isActive, err := resolveStatus(workflow, todo.Status, req.Status, req.IsActive)
+	if err != nil {
+		return err
+	}
+	if status != todo.Status && !workflow.CanTransition(todo.Status, status) {
+		return model.ErrInvalidTransition.WithMessage("status cannot change from %s to %s", todo.Status, status)
+	}
+
 	todo.IsActive = isActive
+	todo.Status = status
+	if req.StartAt.Set {
+		todo.StartAt = normalizeTime(req.StartAt.Time)
+	}
+	if req.DueAt.Set {
+		todo.DueAt = normalizeTime(req.DueAt.Time)
+	}
+	if err = checkDates(todo.StartAt, todo.DueAt); err != nil {
+		return err
+	}
 	if req.Title != "" {
 		todo.Title = req.Title
 	}
 	if req.Priority != "" {
 		todo.Priority = req.Priority
 	}
+	if req.Recurrence != nil {
+		if todo.Recurrence, err = parseRecurrence(*req.Recurrence); err != nil {
+			return err
+		}
+	}
+	if req.AutoComplete != nil {
+		todo.AutoComplete = *req.AutoComplete
+	}
+	if req.AssigneeID.Set {
+		if req.AssigneeID.Int64 != nil && *req.AssigneeID.Int64 < 1 {
+			return model.ErrUnknownAssignee
+		}
+		todo.AssigneeID = req.AssigneeID.Int64
+	}
+	return nil
+}
+
+// todoChange is a changed todo waiting to be saved, together with its state
+// before the change and the workflow of its activity group.
+type todoChange struct {
+	before, after *entity.Todo
+	workflow      *entity.Workflow
+}
 
-	got, err := uc.todoRepository.UpdateTodo(*todo)
+// saveTodo writes a changed todo together with what its status change
+// entails: the transition record, the next occurrence of a recurring todo
+// and the completion of a parent whose last open subtask it was.
+func (uc *TodoUCImpl) saveTodo(ctx context.Context, before, todo *entity.Todo, workflow *entity.Workflow) (*entity.Todo, error) {
+	got, err := uc.saveTodos(ctx, []todoChange{{before: before, after: todo, workflow: workflow}})
 	if err != nil {
-		logrus.Error(err)
 		return nil, err
 	}
+	return got[0], nil
+}
+
+// saveTodos is saveTodo for several todos at once, batching the writes.
+// The saved todos are returned in the order of changes.
+func (uc *TodoUCImpl) saveTodos(ctx context.Context, changes []todoChange) ([]*entity.Todo, error) {
+	completedAt := time.Now().UTC().Truncate(time.Second)
+	var next, previous []*entity.Todo
+	todos := make([]entity.Todo, 0, len(changes))
+	for _, c := range changes {
+		todo := c.after
+		if todo.AssigneeID != nil && !sameID(todo.AssigneeID, c.before.AssigneeID) {
+			if err := uc.checkAssignee(ctx, todo.ActivityGroupID, *todo.AssigneeID); err != nil {
+				return nil, err
+			}
+		}
+		if todo.Recurrence != nil && todo.Status == c.workflow.Done && c.before.Status != c.workflow.Done {
+			if n := nextOccurrence(todo, c.workflow, completedAt); n != nil {
+				todo.SeriesID = n.SeriesID
+				next = append(next, n)
+				previous = append(previous, todo)
+			}
+			todo.Recurrence = nil
+		}
+		todos = append(todos, *todo)
+	}
+
+	saved, err := uc.todoRepository.UpdateTodos(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*entity.Todo, len(saved))
+	for _, t := range saved {
+		byID[t.ID] = t
+	}
+
+	actor := model.ActorFromContext(ctx)
+	got := make([]*entity.Todo, 0, len(changes))
+	var transitions []entity.TodoTransition
+	var events []entity.TodoEvent
+	for _, c := range changes {
+		t := byID[c.after.ID]
+		got = append(got, t)
+		if t.Status != c.before.Status {
+			from := c.before.Status
+			transitions = append(transitions, entity.TodoTransition{TodoID: t.ID, FromStatus: &from, ToStatus: t.Status})
+		}
+		events = append(events, entity.NewTodoEvent(entity.EventActionUpdate, c.before, t, actor))
+	}
+	for i := range next {
+		if next[i], err = uc.todoRepository.InsertTodo(ctx, *next[i]); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, entity.TodoTransition{TodoID: next[i].ID, ToStatus: next[i].Status})
+		events = append(events, entity.NewTodoEvent(entity.EventActionCreate, nil, next[i], actor))
+	}
+	if err = uc.carryOver(ctx, previous, next); err != nil {
+		return nil, err
+	}
+	if err = uc.todoRepository.InsertTodoTransitions(ctx, transitions); err != nil {
+		return nil, err
+	}
+	if err = uc.eventRepository.InsertTodoEvents(ctx, events); err != nil {
+		return nil, err
+	}
+	for i, c := range changes {
+		if err = uc.watchMentions(ctx, got[i], c.before.Title, got[i].Title); err != nil {
+			return nil, err
+		}
+	}
+
+	completed := make(map[int64]bool)
+	for i, c := range changes {
+		t := got[i]
+		if !c.before.IsActive || t.IsActive || t.ParentTodoID == nil || completed[*t.ParentTodoID] {
+			continue
+		}
+		completed[*t.ParentTodoID] = true
+		if err = uc.completeParent(ctx, *t.ParentTodoID, c.workflow); err != nil {
+			return nil, err
+		}
+	}
+	return got, nil
+}
+
+// carryOver gives the next occurrences of recurring todos the tags and
+// watchers of the occurrences they follow.
+func (uc *TodoUCImpl) carryOver(ctx context.Context, previous, next []*entity.Todo) error {
+	if len(next) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(previous))
+	for _, t := range previous {
+		ids = append(ids, t.ID)
+	}
+	tags, err := uc.tagRepository.GetTagByTodoIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	watchers, err := uc.watcherRepository.GetWatcherByTodoIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i, t := range previous {
+		for _, g := range tags[t.ID] {
+			if err = uc.tagRepository.AddTodoTag(ctx, entity.TodoTag{TodoID: next[i].ID, TagID: g.ID}); err != nil {
+				return err
+			}
+		}
+		for _, w := range watchers[t.ID] {
+			if err = uc.watcherRepository.InsertWatcher(ctx, entity.TodoWatcher{TodoID: next[i].ID, UserID: w.UserID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-	return got.ToDTO(), nil
+// completeParent moves an auto completing todo to the done status once all
+// of its subtasks are closed, if its workflow allows it.
+func (uc *TodoUCImpl) completeParent(ctx context.Context, id int64, workflow *entity.Workflow) error {
+	parent, err := uc.todoRepository.GetTodoByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !parent.AutoComplete || !parent.IsActive || !workflow.CanTransition(parent.Status, workflow.Done) {
+		return nil
+	}
+	progress, err := uc.todoRepository.CountTodoByParentIDs(ctx, []int64{id})
+	if err != nil {
+		return err
+	}
+	if p := progress[id]; p.Completed < p.Total {
+		return nil
+	}
+
+	before := *parent
+	parent.Status = workflow.Done
+	parent.IsActive = false
+	_, err = uc.saveTodo(ctx, &before, parent, workflow)
+	return err
 }
 
 func (uc *TodoUCImpl) DeleteTodo(ctx context.Context, id int64) error {
-	todo, err := uc.todoRepository.GetTodoByID(id)
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		todo, err := uc.todoRepository.GetTodoByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		return uc.deleteTodos(ctx, []*entity.Todo{todo}, deletedAt)
+	})
 	if err != nil {
+		logrus.Error(err)
 		return err
 	}
+	return nil
+}
 
-	err = uc.todoRepository.DeleteTodo(todo.ID, todo.Title)
+// deleteTodos moves todos to the trash together with their subtasks.
+func (uc *TodoUCImpl) deleteTodos(ctx context.Context, todos []*entity.Todo, deletedAt time.Time) error {
+	parentIDs := make([]int64, 0, len(todos))
+	selected := make(map[int64]bool, len(todos))
+	for _, t := range todos {
+		parentIDs = append(parentIDs, t.ID)
+		selected[t.ID] = true
+	}
+	subtasks, err := uc.todoRepository.GetTodoByParentIDs(ctx, parentIDs)
+	if err != nil {
+		return err
+	}
+
+	trashed := make([]*entity.Todo, 0, len(subtasks)+len(todos))
+	for _, t := range subtasks {
+		if !selected[t.ID] {
+			trashed = append(trashed, t)
+		}
+	}
+	trashed = append(trashed, todos...)
+
+	ids := make([]int64, 0, len(trashed))
+	events := make([]entity.TodoEvent, 0, len(trashed))
+	actor := model.ActorFromContext(ctx)
+	for _, t := range trashed {
+		ids = append(ids, t.ID)
+		after := *t
+		after.DeletedAt = &deletedAt
+		events = append(events, entity.NewTodoEvent(entity.EventActionDelete, t, &after, actor))
+	}
+	if err = uc.todoRepository.DeleteTodos(ctx, ids, deletedAt); err != nil {
+		return err
+	}
+	return uc.eventRepository.InsertTodoEvents(ctx, events)
+}
+
+func (uc *TodoUCImpl) RestoreTodo(ctx context.Context, id int64) (*web.TodoDTO, error) {
+	var got *entity.Todo
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		todo, err := uc.todoRepository.GetTrashedTodoByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+
+		if _, err := uc.activityRepository.GetActivityByID(ctx, todo.ActivityGroupID); err != nil {
+			if apperror.IsNotFound(err) {
+				return model.ErrActivityGroupTrashed
+			}
+			return err
+		}
+		if todo.ParentTodoID != nil {
+			if _, err := uc.todoRepository.GetTodoByID(ctx, *todo.ParentTodoID); err != nil {
+				if apperror.IsNotFound(err) {
+					return model.ErrParentTodoTrashed
+				}
+				return err
+			}
+		}
+
+		got, err = uc.todoRepository.RestoreTodo(ctx, todo.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.recordEvent(ctx, entity.EventActionRestore, todo, got); err != nil {
+			return err
+		}
+
+		subtasks, err := uc.todoRepository.GetTrashedTodoByParentID(ctx, todo.ID, *todo.DeletedAt)
+		if err != nil {
+			return err
+		}
+		if err = uc.todoRepository.RestoreTodoByParentID(ctx, todo.ID, *todo.DeletedAt); err != nil {
+			return err
+		}
+		for _, t := range subtasks {
+			after := *t
+			after.DeletedAt = nil
+			if err = uc.recordEvent(ctx, entity.EventActionRestore, t, &after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// GetTodoHistory lists the changes made to a todo, oldest first. Trashed
+// todos keep their history.
+func (uc *TodoUCImpl) GetTodoHistory(ctx context.Context, id int64) ([]*web.EventDTO, error) {
+	if _, err := uc.todoRepository.GetTodoByID(ctx, id); err != nil {
+		if _, trashedErr := uc.todoRepository.GetTrashedTodoByID(ctx, id); trashedErr != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+	}
+
+	got, err := uc.eventRepository.GetTodoEventByTodoID(ctx, id)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.EventDTO, 0)
+	for _, e := range got {
+		res = append(res, e.ToDTO())
+	}
+	return res, nil
+}
+
+// GetTodoTransitions lists the status changes of a todo, oldest first, for
+// measuring cycle time.
+func (uc *TodoUCImpl) GetTodoTransitions(ctx context.Context, id int64) ([]*web.TodoTransitionDTO, error) {
+	if _, err := uc.todoRepository.GetTodoByID(ctx, id); err != nil {
+		if _, trashedErr := uc.todoRepository.GetTrashedTodoByID(ctx, id); trashedErr != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+	}
+
+	got, err := uc.todoRepository.GetTodoTransitionByTodoID(ctx, id)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.TodoTransitionDTO, 0)
+	for _, t := range got {
+		res = append(res, t.ToDTO())
+	}
+	return res, nil
+}
+
+// MoveTodo changes the place of a todo in the manual order, optionally
+// moving it to another activity group. Subtasks follow their parent to the
+// new group, where statuses its workflow lacks fall back to its initial or
+// done status.
+func (uc *TodoUCImpl) MoveTodo(ctx context.Context, req web.TodoMoveRequest) (*web.TodoDTO, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	for _, id := range []*int64{req.AfterID, req.BeforeID} {
+		if id != nil && *id == req.ID {
+			return nil, model.ErrMoveNeighbourIsSelf
+		}
+	}
+
+	var got *entity.Todo
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		todo, err := uc.todoRepository.GetTodoByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, todo.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		if req.Version != nil && *req.Version != todo.Version {
+			return model.ErrVersionMismatch
+		}
+
+		groupID := todo.ActivityGroupID
+		if req.ActivityGroupID != nil {
+			groupID = *req.ActivityGroupID
+		}
+		if groupID != todo.ActivityGroupID && todo.ParentTodoID != nil {
+			return model.ErrParentGroupMismatch
+		}
+		activity, err := uc.activityRepository.GetActivityByID(ctx, groupID)
+		if err != nil {
+			if apperror.IsNotFound(err) {
+				return model.ErrActivityGroupNotFound
+			}
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		workflow := activity.WorkflowOrDefault()
+
+		group, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		siblings := make([]*entity.Todo, 0, len(group))
+		for _, t := range group {
+			if t.ID != todo.ID {
+				siblings = append(siblings, t)
+			}
+		}
+		index, err := placement(siblings, req.AfterID, req.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		before := *todo
+		if todo.Position, err = uc.rankAt(ctx, siblings, index); err != nil {
+			return err
+		}
+		if groupID != before.ActivityGroupID {
+			if err = uc.keepMembers(ctx, []*entity.Todo{todo}, groupID); err != nil {
+				return err
+			}
+		}
+		todo.ActivityGroupID = groupID
+		if !workflow.Has(todo.Status) {
+			todo.Status = workflow.StatusFor("", todo.IsActive)
+		}
+		if got, err = uc.saveTodo(ctx, &before, todo, workflow); err != nil {
+			return err
+		}
+		if groupID == before.ActivityGroupID {
+			return nil
+		}
+
+		subtasks, err := uc.todoRepository.GetTodoByParentIDs(ctx, []int64{todo.ID})
+		if err != nil {
+			return err
+		}
+		befores := make([]entity.Todo, len(subtasks))
+		for i, t := range subtasks {
+			befores[i] = *t
+		}
+		return uc.moveTodos(ctx, befores, subtasks, activity)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// MoveActivityTodos moves every todo of the activity group fromID after the
+// last todo of the group toID, in their order, as MoveTodo moves one. It
+// leaves authorization to the caller, which deletes the group fromID.
+func (uc *TodoUCImpl) MoveActivityTodos(ctx context.Context, fromID, toID int64) error {
+	return uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		target, err := uc.activityRepository.GetActivityByID(ctx, toID)
+		if err != nil {
+			return err
+		}
+		todos, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, fromID)
+		if err != nil || len(todos) == 0 {
+			return err
+		}
+		siblings, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, toID)
+		if err != nil {
+			return err
+		}
+
+		befores := make([]entity.Todo, len(todos))
+		for i, t := range todos {
+			befores[i] = *t
+			if t.Position, err = uc.rankAt(ctx, siblings, len(siblings)); err != nil {
+				return err
+			}
+			siblings = append(siblings, t)
+		}
+		return uc.moveTodos(ctx, befores, todos, target)
+	})
+}
+
+// moveTodos saves todos, changed from befores, in the activity group target.
+// Assignees and watchers that are not members of target are dropped, and
+// statuses its workflow lacks fall back to its initial or done status.
+func (uc *TodoUCImpl) moveTodos(ctx context.Context, befores []entity.Todo, todos []*entity.Todo, target *entity.Activity) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	if err := uc.keepMembers(ctx, todos, target.ID); err != nil {
+		return err
+	}
+	workflow := target.WorkflowOrDefault()
+	changes := make([]todoChange, 0, len(todos))
+	for i, t := range todos {
+		t.ActivityGroupID = target.ID
+		if !workflow.Has(t.Status) {
+			t.Status = workflow.StatusFor("", t.IsActive)
+		}
+		changes = append(changes, todoChange{before: &befores[i], after: t, workflow: workflow})
+	}
+	_, err := uc.saveTodos(ctx, changes)
+	return err
+}
+
+// placement returns the index among siblings, the other todos of the group
+// in order, that a moved todo goes to.
+func placement(siblings []*entity.Todo, afterID, beforeID *int64) (int, error) {
+	indexOf := func(id int64) int {
+		for i, t := range siblings {
+			if t.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	after, before := -1, len(siblings)
+	if afterID != nil {
+		if after = indexOf(*afterID); after < 0 {
+			return 0, model.ErrMoveNeighbourNotFound
+		}
+	}
+	if beforeID != nil {
+		if before = indexOf(*beforeID); before < 0 {
+			return 0, model.ErrMoveNeighbourNotFound
+		}
+	}
+	switch {
+	case afterID != nil && beforeID != nil && after >= before:
+		return 0, model.ErrMoveNeighbourOrder
+	case afterID != nil:
+		return after + 1, nil
+	default:
+		return before, nil
+	}
+}
+
+// rankAt returns the position of a todo inserted at index among siblings,
+// the other todos of its group in order. Once positions grow too long, or
+// collide after todos moved in from another group, the whole group is
+// spread out again, siblings included.
+func (uc *TodoUCImpl) rankAt(ctx context.Context, siblings []*entity.Todo, index int) (string, error) {
+	var lower, upper string
+	if index > 0 {
+		lower = siblings[index-1].Position
+	}
+	if index < len(siblings) {
+		upper = siblings[index].Position
+	}
+	if r, err := rank.Between(lower, upper); err == nil && len(r) <= rank.MaxLength {
+		return r, nil
+	}
+
+	ranks := rank.Spread(len(siblings) + 1)
+	positions := make(map[int64]string, len(siblings))
+	for i, t := range siblings {
+		if i >= index {
+			i++
+		}
+		t.Position = ranks[i]
+		positions[t.ID] = ranks[i]
+	}
+	if err := uc.todoRepository.SetTodoPositions(ctx, positions); err != nil {
+		return "", err
+	}
+	return ranks[index], nil
+}
+
+// AddTodoTag tags a todo. Adding a tag the todo already has changes
+// nothing.
+func (uc *TodoUCImpl) AddTodoTag(ctx context.Context, req web.TodoTagRequest) (*web.TodoDTO, error) {
+	return uc.changeTodoTag(ctx, req, func(ctx context.Context, tags []*entity.Tag, tag *entity.Tag) ([]*entity.Tag, bool, error) {
+		for _, t := range tags {
+			if t.ID == tag.ID {
+				return tags, false, nil
+			}
+		}
+		if err := uc.tagRepository.AddTodoTag(ctx, entity.TodoTag{TodoID: req.TodoID, TagID: tag.ID}); err != nil {
+			return nil, false, err
+		}
+		return append(tags, tag), true, nil
+	})
+}
+
+// RemoveTodoTag removes a tag from a todo. Removing a tag the todo does not
+// have changes nothing.
+func (uc *TodoUCImpl) RemoveTodoTag(ctx context.Context, req web.TodoTagRequest) (*web.TodoDTO, error) {
+	return uc.changeTodoTag(ctx, req, func(ctx context.Context, tags []*entity.Tag, tag *entity.Tag) ([]*entity.Tag, bool, error) {
+		removed, err := uc.tagRepository.RemoveTodoTag(ctx, entity.TodoTag{TodoID: req.TodoID, TagID: tag.ID})
+		if err != nil || !removed {
+			return tags, false, err
+		}
+		var after []*entity.Tag
+		for _, t := range tags {
+			if t.ID != tag.ID {
+				after = append(after, t)
+			}
+		}
+		return after, true, nil
+	})
+}
+
+// changeTodoTag applies change to the current tags of a todo and records the
+// tags it ends up with in the history.
+func (uc *TodoUCImpl) changeTodoTag(ctx context.Context, req web.TodoTagRequest, change func(ctx context.Context, tags []*entity.Tag, tag *entity.Tag) ([]*entity.Tag, bool, error)) (*web.TodoDTO, error) {
+	var got *entity.Todo
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if got, err = uc.todoRepository.GetTodoByID(ctx, req.TodoID); err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, got.ActivityGroupID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		tag, err := uc.tagRepository.GetTagByID(ctx, req.TagID)
+		if err != nil {
+			return err
+		}
+		current, err := uc.tagRepository.GetTagByTodoIDs(ctx, []int64{got.ID})
+		if err != nil {
+			return err
+		}
+
+		before := current[got.ID]
+		after, changed, err := change(ctx, before, tag)
+		if err != nil || !changed {
+			return err
+		}
+		return uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoTagEvent(got.ID, tagNames(before), tagNames(after), model.ActorFromContext(ctx)))
+	})
 	if err != nil {
 		logrus.Error(err)
+		return nil, err
+	}
+
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// tagNames lists the names of tags in sorted order, so the history does not
+// depend on the order tags were added in.
+func tagNames(tags []*entity.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// AddTodoWatcher has a member of the activity group of a todo watch it.
+// Members may watch todos themselves; adding anyone else takes an editor.
+// Adding a watcher the todo already has changes nothing.
+func (uc *TodoUCImpl) AddTodoWatcher(ctx context.Context, req web.TodoWatcherRequest) (*web.TodoDTO, error) {
+	return uc.changeTodoWatcher(ctx, req, func(ctx context.Context, todo *entity.Todo, watchers []*entity.TodoWatcher) ([]*entity.TodoWatcher, bool, error) {
+		for _, w := range watchers {
+			if w.UserID == req.UserID {
+				return watchers, false, nil
+			}
+		}
+		member, err := uc.memberRepository.GetMember(ctx, todo.ActivityGroupID, req.UserID)
+		if err != nil {
+			if apperror.IsNotFound(err) {
+				return nil, false, model.ErrUnknownWatcher
+			}
+			return nil, false, err
+		}
+		w := entity.TodoWatcher{TodoID: todo.ID, UserID: member.UserID, Email: member.Email, Name: member.Name}
+		if err = uc.watcherRepository.InsertWatcher(ctx, w); err != nil {
+			return nil, false, err
+		}
+		return append(watchers, &w), true, nil
+	})
+}
+
+// RemoveTodoWatcher stops a user watching a todo. Removing a user who does
+// not watch the todo changes nothing.
+func (uc *TodoUCImpl) RemoveTodoWatcher(ctx context.Context, req web.TodoWatcherRequest) (*web.TodoDTO, error) {
+	return uc.changeTodoWatcher(ctx, req, func(ctx context.Context, todo *entity.Todo, watchers []*entity.TodoWatcher) ([]*entity.TodoWatcher, bool, error) {
+		removed, err := uc.watcherRepository.DeleteWatcher(ctx, todo.ID, req.UserID)
+		if err != nil || !removed {
+			return watchers, false, err
+		}
+		var after []*entity.TodoWatcher
+		for _, w := range watchers {
+			if w.UserID != req.UserID {
+				after = append(after, w)
+			}
+		}
+		return after, true, nil
+	})
+}
+
+// changeTodoWatcher applies change to the current watchers of a todo and
+// records the watchers it ends up with in the history.
+func (uc *TodoUCImpl) changeTodoWatcher(ctx context.Context, req web.TodoWatcherRequest, change func(ctx context.Context, todo *entity.Todo, watchers []*entity.TodoWatcher) ([]*entity.TodoWatcher, bool, error)) (*web.TodoDTO, error) {
+	role := entity.MemberRoleEditor
+	if userID, ok := model.UserFromContext(ctx); ok && userID == req.UserID {
+		role = entity.MemberRoleViewer
+	}
+
+	var got *entity.Todo
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if got, err = uc.todoRepository.GetTodoByID(ctx, req.TodoID); err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, got.ActivityGroupID, role); err != nil {
+			return err
+		}
+		current, err := uc.watcherRepository.GetWatcherByTodoIDs(ctx, []int64{got.ID})
+		if err != nil {
+			return err
+		}
+
+		before := current[got.ID]
+		after, changed, err := change(ctx, got, before)
+		if err != nil || !changed {
+			return err
+		}
+		return uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoWatcherEvent(got.ID, watcherEmails(before), watcherEmails(after), model.ActorFromContext(ctx)))
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res, err := uc.toDTOs(ctx, []*entity.Todo{got})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// watchMentions has the members of the activity group of todo that text
+// about it, its title or a comment, newly mentions watch it. Mentions of
+// anyone else are ignored.
+func (uc *TodoUCImpl) watchMentions(ctx context.Context, todo *entity.Todo, before, text string) error {
+	emails := mention.Added(before, text)
+	if len(emails) == 0 {
+		return nil
+	}
+	members, err := uc.memberRepository.GetMemberByActivityIDs(ctx, []int64{todo.ActivityGroupID})
+	if err != nil {
 		return err
 	}
+	byEmail := make(map[string]*entity.ActivityMember)
+	for _, m := range members[todo.ActivityGroupID] {
+		byEmail[strings.ToLower(m.Email)] = m
+	}
+	current, err := uc.watcherRepository.GetWatcherByTodoIDs(ctx, []int64{todo.ID})
+	if err != nil {
+		return err
+	}
+
+	watchers := current[todo.ID]
+	watching := make(map[int64]bool, len(watchers))
+	for _, w := range watchers {
+		watching[w.UserID] = true
+	}
+	after := append([]*entity.TodoWatcher(nil), watchers...)
+	for _, email := range emails {
+		m, ok := byEmail[email]
+		if !ok || watching[m.UserID] {
+			continue
+		}
+		w := entity.TodoWatcher{TodoID: todo.ID, UserID: m.UserID, Email: m.Email, Name: m.Name}
+		if err = uc.watcherRepository.InsertWatcher(ctx, w); err != nil {
+			return err
+		}
+		watching[m.UserID] = true
+		after = append(after, &w)
+	}
+	if len(after) == len(watchers) {
+		return nil
+	}
+	return uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoWatcherEvent(todo.ID, watcherEmails(watchers), watcherEmails(after), model.ActorFromContext(ctx)))
+}
+
+// keepMembers leaves todos, which move to the activity group groupID, with
+// only assignees and watchers that are members of that group.
+func (uc *TodoUCImpl) keepMembers(ctx context.Context, todos []*entity.Todo, groupID int64) error {
+	members, err := uc.memberRepository.GetMemberByActivityIDs(ctx, []int64{groupID})
+	if err != nil {
+		return err
+	}
+	isMember := make(map[int64]bool)
+	for _, m := range members[groupID] {
+		isMember[m.UserID] = true
+	}
+	ids := make([]int64, 0, len(todos))
+	for _, todo := range todos {
+		if todo.AssigneeID != nil && !isMember[*todo.AssigneeID] {
+			todo.AssigneeID = nil
+		}
+		ids = append(ids, todo.ID)
+	}
+
+	current, err := uc.watcherRepository.GetWatcherByTodoIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	actor := model.ActorFromContext(ctx)
+	var events []entity.TodoEvent
+	for _, todo := range todos {
+		watchers := current[todo.ID]
+		var kept []*entity.TodoWatcher
+		for _, w := range watchers {
+			if isMember[w.UserID] {
+				kept = append(kept, w)
+				continue
+			}
+			if _, err = uc.watcherRepository.DeleteWatcher(ctx, todo.ID, w.UserID); err != nil {
+				return err
+			}
+		}
+		if len(kept) != len(watchers) {
+			events = append(events, entity.NewTodoWatcherEvent(todo.ID, watcherEmails(watchers), watcherEmails(kept), actor))
+		}
+	}
+	return uc.eventRepository.InsertTodoEvents(ctx, events)
+}
+
+// watcherEmails lists the emails of watchers in sorted order, like
+// tagNames.
+func watcherEmails(watchers []*entity.TodoWatcher) []string {
+	emails := make([]string, 0, len(watchers))
+	for _, w := range watchers {
+		emails = append(emails, w.Email)
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// checkAssignee checks that the user todos are assigned to is a member of
+// their activity group.
+func (uc *TodoUCImpl) checkAssignee(ctx context.Context, activityID, userID int64) error {
+	_, err := uc.memberRepository.GetMember(ctx, activityID, userID)
+	if apperror.IsNotFound(err) {
+		return model.ErrUnknownAssignee
+	}
+	return err
+}
+
+func sameID(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// resolveStatus works out the status and is_active flag a todo ends up with.
+// An explicit status wins and drives is_active; a bare is_active picks the
+// matching status; with neither the current status is kept, or the initial
+// one for new todos.
+func resolveStatus(workflow *entity.Workflow, current, status string, isActive *bool) (string, bool, error) {
+	switch {
+	case status != "":
+		if !workflow.Has(status) {
+			return "", false, model.ErrInvalidStatus
+		}
+		if isActive != nil && *isActive == workflow.IsClosed(status) {
+			return "", false, model.ErrStatusConflict
+		}
+	case isActive != nil:
+		status = workflow.StatusFor(current, *isActive)
+	case current != "":
+		status = current
+	default:
+		status = workflow.Initial
+	}
+	return status, !workflow.IsClosed(status), nil
+}
+
+// parseRecurrence validates a recurrence rule and returns it in canonical
+// form; an empty rule means none.
+func parseRecurrence(s string) (*string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	rule, err := recurrence.Parse(s)
+	if err != nil {
+		return nil, model.ErrInvalidRecurrence.WithMessage("recurrence %s", err.Error())
+	}
+	canonical := rule.String()
+	return &canonical, nil
+}
+
+// nextOccurrence builds the todo following a completed occurrence, or nil
+// when the series is over. The next occurrence starts over in the initial
+// status and keeps the distance between start and due date; a recurring
+// subtask stays under its parent and an assigned todo with its assignee.
+func nextOccurrence(t *entity.Todo, workflow *entity.Workflow, completedAt time.Time) *entity.Todo {
+	rule, err := recurrence.Parse(*t.Recurrence)
+	if err != nil {
+		return nil
+	}
+	anchor := completedAt
+	if rule.Anchor == recurrence.AnchorDue && t.DueAt != nil {
+		anchor = *t.DueAt
+	}
+	due, rest, ok := rule.Next(anchor)
+	if !ok {
+		return nil
+	}
+
+	seriesID := t.ID
+	if t.SeriesID != nil {
+		seriesID = *t.SeriesID
+	}
+	restRule := rest.String()
+	next := &entity.Todo{
+		ActivityGroupID: t.ActivityGroupID,
+		Title:           t.Title,
+		Priority:        t.Priority,
+		IsActive:        true,
+		Status:          workflow.Initial,
+		DueAt:           &due,
+		Recurrence:      &restRule,
+		SeriesID:        &seriesID,
+		ParentTodoID:    t.ParentTodoID,
+		AutoComplete:    t.AutoComplete,
+		AssigneeID:      t.AssigneeID,
+		// Ties are broken by id, so the next occurrence takes the place
+		// of the completed one.
+		Position: t.Position,
+	}
+	if t.StartAt != nil && t.DueAt != nil {
+		startAt := due.Add(t.StartAt.Sub(*t.DueAt))
+		next.StartAt = &startAt
+	}
+	return next
+}
+
+// normalizeTime stores timestamps in UTC at the precision of DATETIME
+// columns; the offset a client sent is not kept.
+func normalizeTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Truncate(time.Second)
+	return &utc
+}
+
+func checkDates(startAt, dueAt *time.Time) error {
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		return model.ErrStartAfterDue
+	}
 	return nil
 }
+
+// authorize checks that the user of ctx has at least role in the activity
+// group. Without a user, as on the command line, everything is allowed.
+func (uc *TodoUCImpl) authorize(ctx context.Context, activityID int64, role string) error {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	member, err := uc.memberRepository.GetMember(ctx, activityID, userID)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return model.ErrInsufficientRole
+		}
+		return err
+	}
+	if !member.Can(role) {
+		return model.ErrInsufficientRole
+	}
+	return nil
+}
+
+func (uc *TodoUCImpl) recordTransition(ctx context.Context, todoID int64, from *string, to string) error {
+	return uc.todoRepository.InsertTodoTransition(ctx, entity.TodoTransition{
+		TodoID:     todoID,
+		FromStatus: from,
+		ToStatus:   to,
+	})
+}
+
+func (uc *TodoUCImpl) recordEvent(ctx context.Context, action string, before, after *entity.Todo) error {
+	return uc.eventRepository.InsertTodoEvent(ctx, entity.NewTodoEvent(action, before, after, model.ActorFromContext(ctx)))
+}
@@ -0,0 +1,240 @@
+package todo
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/rank"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/comment"
+	"github.com/vnnyx/golang-todo-api/internal/repository/event"
+	"github.com/vnnyx/golang-todo-api/internal/repository/member"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/watcher"
+)
+
+func date(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestNextOccurrenceAnchor(t *testing.T) {
+	completedAt := *date("2023-04-12T18:00:00Z")
+	tests := []struct {
+		name       string
+		recurrence string
+		dueAt      *time.Time
+		want       string
+	}{
+		{"due anchor", "FREQ=WEEKLY", date("2023-04-10T09:00:00Z"), "2023-04-17T09:00:00Z"},
+		{"completion anchor", "FREQ=WEEKLY;X-ANCHOR=COMPLETION", date("2023-04-10T09:00:00Z"), "2023-04-19T18:00:00Z"},
+		{"no due date", "FREQ=WEEKLY", nil, "2023-04-19T18:00:00Z"},
+	}
+	for _, tt := range tests {
+		recurrence := tt.recurrence
+		todo := &entity.Todo{ID: 7, ActivityGroupID: 1, Title: "Water plants", Status: entity.StatusDone, DueAt: tt.dueAt, Recurrence: &recurrence}
+		next := nextOccurrence(todo, entity.DefaultWorkflow(), completedAt)
+		if next == nil {
+			t.Fatalf("%s: nextOccurrence = nil", tt.name)
+		}
+		if !next.DueAt.Equal(*date(tt.want)) {
+			t.Errorf("%s: due_at = %v, want %s", tt.name, next.DueAt, tt.want)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	recurrence := "FREQ=DAILY;COUNT=3"
+	parentID, assigneeID := int64(3), int64(5)
+	todo := &entity.Todo{
+		ID:              7,
+		ActivityGroupID: 1,
+		Title:           "Stand-up",
+		Priority:        "high",
+		Status:          entity.StatusDone,
+		StartAt:         date("2023-04-10T08:30:00Z"),
+		DueAt:           date("2023-04-10T09:00:00Z"),
+		Recurrence:      &recurrence,
+		Position:        "m",
+		ParentTodoID:    &parentID,
+		AutoComplete:    true,
+		AssigneeID:      &assigneeID,
+	}
+	next := nextOccurrence(todo, entity.DefaultWorkflow(), *date("2023-04-10T12:00:00Z"))
+	if next == nil {
+		t.Fatal("nextOccurrence = nil")
+	}
+	if next.Title != todo.Title || next.Priority != todo.Priority || next.ActivityGroupID != todo.ActivityGroupID || next.Position != todo.Position {
+		t.Errorf("nextOccurrence = %+v, want the title, priority, group and position of %+v", next, todo)
+	}
+	if next.ParentTodoID == nil || *next.ParentTodoID != parentID || !next.AutoComplete {
+		t.Errorf("parent_todo_id, auto_complete = %v, %v, want the subtask to stay under %d", next.ParentTodoID, next.AutoComplete, parentID)
+	}
+	if next.AssigneeID == nil || *next.AssigneeID != assigneeID {
+		t.Errorf("assignee_id = %v, want %d", next.AssigneeID, assigneeID)
+	}
+	if next.Status != entity.StatusTodo || !next.IsActive {
+		t.Errorf("status = %q, is_active = %v, want the initial status", next.Status, next.IsActive)
+	}
+	if !next.StartAt.Equal(*date("2023-04-11T08:30:00Z")) || !next.DueAt.Equal(*date("2023-04-11T09:00:00Z")) {
+		t.Errorf("start_at, due_at = %v, %v, want the next day", next.StartAt, next.DueAt)
+	}
+	if *next.Recurrence != "FREQ=DAILY;COUNT=2" || *next.SeriesID != todo.ID {
+		t.Errorf("recurrence, series = %q, %d", *next.Recurrence, *next.SeriesID)
+	}
+
+	last := "FREQ=DAILY;COUNT=1"
+	todo.Recurrence = &last
+	if next := nextOccurrence(todo, entity.DefaultWorkflow(), *date("2023-04-10T12:00:00Z")); next != nil {
+		t.Errorf("nextOccurrence of the last occurrence = %+v, want nil", next)
+	}
+}
+
+// newMemoryUC returns a TodoUC on the memory driver.
+func newMemoryUC(db *infrastructure.MemoryDatabase) TodoUC {
+	return NewTodoUC(todo.NewTodoMemoryRepository(db), activity.NewActivityMemoryRepository(db), event.NewEventMemoryRepository(db),
+		tag.NewTagMemoryRepository(db), member.NewMemberMemoryRepository(db), watcher.NewWatcherMemoryRepository(db),
+		comment.NewCommentMemoryRepository(db), transaction.NewMemoryTxManager(db))
+}
+
+// TestWatchMentions checks that members mentioned in a title or comment
+// watch the todo, while mentions of users outside of the group, or of no
+// user at all, are ignored instead of failing the update.
+func TestWatchMentions(t *testing.T) {
+	db := infrastructure.NewMemoryDatabase()
+	ctx := model.WithWorkspace(context.Background(), 1)
+	activities, todos := activity.NewActivityMemoryRepository(db), todo.NewTodoMemoryRepository(db)
+	uc := newMemoryUC(db)
+
+	group, err := activities.InsertActivity(ctx, entity.Activity{Title: "Release"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []entity.User{{ID: 1, Email: "ann@example.com"}, {ID: 2, Email: "Bob@Example.com"}, {ID: 3, Email: "carol@example.com"}} {
+		db.Users[u.ID] = u
+	}
+	for _, id := range []int64{1, 2} {
+		db.ActivityMembers[entity.MemberKey{ActivityID: group.ID, UserID: id}] = entity.ActivityMember{ActivityID: group.ID, UserID: id, Role: entity.MemberRoleEditor}
+	}
+	got, err := todos.InsertTodo(ctx, entity.Todo{ActivityGroupID: group.ID, Title: "Ship it", Status: entity.StatusTodo, IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watchers := func() (emails []string) {
+		for key := range db.TodoWatchers {
+			if key.TodoID == got.ID {
+				emails = append(emails, db.Users[key.UserID].Email)
+			}
+		}
+		sort.Strings(emails)
+		return emails
+	}
+
+	ctx = model.WithUser(ctx, 1)
+	title := "Ship it with @carol@example.com, @nobody@example.com and @ann@example.com. Thanks @ANN@example.com!"
+	if _, err := uc.UpdateTodo(ctx, web.TodoUpdateRequest{ID: got.ID, Title: title}); err != nil {
+		t.Fatalf("UpdateTodo error = %v", err)
+	}
+	if got, want := watchers(), []string{"ann@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("watchers after the title = %q, want %q", got, want)
+	}
+
+	if _, err := uc.CreateComment(ctx, web.CommentCreateRequest{TodoID: got.ID, Body: "@nobody@example.com, @carol@example.com: ask @bob@example.com."}); err != nil {
+		t.Fatalf("CreateComment error = %v", err)
+	}
+	if got, want := watchers(), []string{"Bob@Example.com", "ann@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("watchers after the comment = %q, want %q", got, want)
+	}
+}
+
+// TestMoveActivityTodos checks that todos moved to another group go after
+// its todos, in their order, and lose the assignees and watchers that are
+// not members of it.
+func TestMoveActivityTodos(t *testing.T) {
+	for _, last := range []string{"m", strings.Repeat("z", rank.MaxLength)} {
+		db := infrastructure.NewMemoryDatabase()
+		ctx := model.WithWorkspace(context.Background(), 1)
+		activities, todos := activity.NewActivityMemoryRepository(db), todo.NewTodoMemoryRepository(db)
+		uc := newMemoryUC(db)
+
+		from, err := activities.InsertActivity(ctx, entity.Activity{Title: "Old"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		to, err := activities.InsertActivity(ctx, entity.Activity{Title: "New"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []entity.User{{ID: 1, Email: "ann@example.com"}, {ID: 2, Email: "bob@example.com"}} {
+			db.Users[u.ID] = u
+		}
+		for _, m := range []entity.MemberKey{{ActivityID: from.ID, UserID: 1}, {ActivityID: from.ID, UserID: 2}, {ActivityID: to.ID, UserID: 1}} {
+			db.ActivityMembers[m] = entity.ActivityMember{ActivityID: m.ActivityID, UserID: m.UserID, Role: entity.MemberRoleEditor}
+		}
+
+		insert := func(group int64, title, position string, assigneeID *int64) int64 {
+			got, err := todos.InsertTodo(ctx, entity.Todo{ActivityGroupID: group, Title: title, Status: entity.StatusTodo, IsActive: true, Position: position, AssigneeID: assigneeID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return got.ID
+		}
+		ann, bob := int64(1), int64(2)
+		insert(to.ID, "first", "a", nil)
+		insert(to.ID, "last", last, nil)
+		insert(from.ID, "moved 1", "a", &bob)
+		moved := insert(from.ID, "moved 2", "b", &ann)
+		insert(from.ID, "moved 3", "c", nil)
+		for _, userID := range []int64{ann, bob} {
+			db.TodoWatchers[entity.WatcherKey{TodoID: moved, UserID: userID}] = entity.TodoWatcher{TodoID: moved, UserID: userID}
+		}
+
+		if err = uc.MoveActivityTodos(ctx, from.ID, to.ID); err != nil {
+			t.Fatalf("MoveActivityTodos error = %v", err)
+		}
+		group, err := todos.GetTodoByActivityGroupID(ctx, to.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for i, got := range group {
+			titles = append(titles, got.Title)
+			if !rank.Valid(got.Position) || (i > 0 && got.Position <= group[i-1].Position) {
+				t.Errorf("last %q: position of %q = %q after %q", last, got.Title, got.Position, group[i-1].Position)
+			}
+			switch got.Title {
+			case "moved 1":
+				if got.AssigneeID != nil {
+					t.Errorf("last %q: assignee of %q = %d, want none", last, got.Title, *got.AssigneeID)
+				}
+			case "moved 2":
+				if got.AssigneeID == nil || *got.AssigneeID != ann {
+					t.Errorf("last %q: assignee of %q = %v, want %d", last, got.Title, got.AssigneeID, ann)
+				}
+			}
+		}
+		if want := []string{"first", "last", "moved 1", "moved 2", "moved 3"}; !reflect.DeepEqual(titles, want) {
+			t.Errorf("last %q: group = %q, want %q", last, titles, want)
+		}
+		if _, ok := db.TodoWatchers[entity.WatcherKey{TodoID: moved, UserID: bob}]; ok {
+			t.Errorf("last %q: bob still watches %d", last, moved)
+		}
+		if _, ok := db.TodoWatchers[entity.WatcherKey{TodoID: moved, UserID: ann}]; !ok {
+			t.Errorf("last %q: ann no longer watches %d", last, moved)
+		}
+	}
+}
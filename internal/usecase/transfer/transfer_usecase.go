@@ -0,0 +1,13 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type TransferUC interface {
+	ExportActivity(ctx context.Context, req web.ActivityExportRequest) ([]byte, error)
+	ImportActivity(ctx context.Context, req web.ActivityImportRequest) (*web.ActivityDTO, error)
+	ImportTodos(ctx context.Context, req web.TodoImportRequest) ([]*web.TodoDTO, error)
+}
@@ -0,0 +1,504 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/rank"
+	"github.com/vnnyx/golang-todo-api/internal/recurrence"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/comment"
+	"github.com/vnnyx/golang-todo-api/internal/repository/event"
+	"github.com/vnnyx/golang-todo-api/internal/repository/member"
+	"github.com/vnnyx/golang-todo-api/internal/repository/tag"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	codec "github.com/vnnyx/golang-todo-api/internal/transfer"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+type TransferUCImpl struct {
+	activityRepository activity.ActivityRepository
+	todoRepository     todo.TodoRepository
+	eventRepository    event.EventRepository
+	tagRepository      tag.TagRepository
+	memberRepository   member.MemberRepository
+	commentRepository  comment.CommentRepository
+	txManager          transaction.TxManager
+}
+
+func NewTransferUC(activityRepository activity.ActivityRepository, todoRepository todo.TodoRepository, eventRepository event.EventRepository, tagRepository tag.TagRepository, memberRepository member.MemberRepository, commentRepository comment.CommentRepository, txManager transaction.TxManager) TransferUC {
+	return &TransferUCImpl{
+		activityRepository: activityRepository,
+		todoRepository:     todoRepository,
+		eventRepository:    eventRepository,
+		tagRepository:      tagRepository,
+		memberRepository:   memberRepository,
+		commentRepository:  commentRepository,
+		txManager:          txManager,
+	}
+}
+
+// ExportActivity renders an activity group and its live todos in the
+// requested format. The workflow is only exported when the group has its
+// own, and a status only when it is not the one is_active implies.
+func (uc *TransferUCImpl) ExportActivity(ctx context.Context, req web.ActivityExportRequest) ([]byte, error) {
+	if !codec.Valid(req.Format) {
+		return nil, model.ErrInvalidFormat
+	}
+	got, err := uc.activityRepository.GetActivityByID(ctx, req.ID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	todos, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, got.ID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	ids := make([]int64, 0, len(todos))
+	for _, t := range todos {
+		ids = append(ids, t.ID)
+	}
+	tags, err := uc.tagRepository.GetTagByTodoIDs(ctx, ids)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	comments, err := uc.commentRepository.GetCommentByTodoIDs(ctx, ids)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	list := &codec.List{Title: got.Title, Email: got.Email, Todos: []*codec.Item{}}
+	if got.Workflow != nil {
+		list.Workflow = got.Workflow.ToDTO()
+	}
+	workflow := got.WorkflowOrDefault()
+	items := make(map[int64]*codec.Item, len(todos))
+	for _, t := range todos {
+		item := &codec.Item{
+			Title:        t.Title,
+			Priority:     t.Priority,
+			Done:         !t.IsActive,
+			StartAt:      t.StartAt,
+			DueAt:        t.DueAt,
+			Recurrence:   t.Recurrence,
+			AutoComplete: t.AutoComplete,
+		}
+		if t.Status != workflow.StatusFor("", t.IsActive) {
+			item.Status = t.Status
+		}
+		for _, g := range tags[t.ID] {
+			item.Tags = append(item.Tags, g.Name)
+		}
+		for _, c := range comments[t.ID] {
+			item.Comments = append(item.Comments, &codec.Comment{Author: c.Email, Body: c.Body, CreatedAt: c.CreatedAt})
+		}
+		items[t.ID] = item
+	}
+	for _, t := range todos {
+		if t.ParentTodoID == nil {
+			list.Todos = append(list.Todos, items[t.ID])
+		} else if parent, ok := items[*t.ParentTodoID]; ok {
+			parent.Subtasks = append(parent.Subtasks, items[t.ID])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = codec.Encode(&buf, req.Format, list); err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importTodo is a todo ready to be inserted. parent is the index of the
+// parent within the import, or -1.
+type importTodo struct {
+	todo   entity.Todo
+	tags   []string
+	parent int
+}
+
+// ImportActivity creates an activity group with the todos of an export.
+// Nothing is written unless every todo is valid; tags are matched by name
+// and created when missing.
+func (uc *TransferUCImpl) ImportActivity(ctx context.Context, req web.ActivityImportRequest) (*web.ActivityDTO, error) {
+	list, err := decodeImport(req.Format, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		list.Title = title
+	}
+	list.Title = strings.TrimSpace(list.Title)
+	if list.Title == "" {
+		return nil, model.ErrImportTitleRequired
+	}
+	if err = validation.Struct(web.ActivityCreateRequest{Title: list.Title, Email: list.Email}); err != nil {
+		return nil, err
+	}
+	workflow, err := importWorkflow(list.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := importTodos(list, workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	var got *entity.Activity
+	var owner *entity.ActivityMember
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		got, err = uc.activityRepository.InsertActivity(ctx, entity.Activity{
+			Title:    list.Title,
+			Email:    list.Email,
+			Workflow: workflow,
+		})
+		if err != nil {
+			return err
+		}
+		if userID, ok := model.UserFromContext(ctx); ok {
+			owner, err = uc.memberRepository.InsertMember(ctx, entity.ActivityMember{
+				ActivityID: got.ID,
+				UserID:     userID,
+				Role:       entity.MemberRoleOwner,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		err = uc.eventRepository.InsertActivityEvent(ctx, entity.NewActivityEvent(entity.EventActionCreate, nil, got, model.ActorFromContext(ctx)))
+		if err != nil {
+			return err
+		}
+		_, err = uc.insertTodos(ctx, got.ID, todos, rank.Spread(len(todos)))
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	res := got.ToDTO()
+	if owner != nil {
+		res.Members = []*web.MemberDTO{owner.ToDTO()}
+	}
+	return res, nil
+}
+
+// ImportTodos adds the todos of an export to an existing activity group,
+// after the todos it already has. The title and workflow of the export are
+// ignored; statuses have to exist in the workflow of the group.
+func (uc *TransferUCImpl) ImportTodos(ctx context.Context, req web.TodoImportRequest) ([]*web.TodoDTO, error) {
+	list, err := decodeImport(req.Format, req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var got []*entity.Todo
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activity, err := uc.activityRepository.GetActivityByID(ctx, req.ActivityGroupID)
+		if err != nil {
+			return err
+		}
+		if err = uc.authorize(ctx, activity.ID, entity.MemberRoleEditor); err != nil {
+			return err
+		}
+		todos, err := importTodos(list, activity.WorkflowOrDefault())
+		if err != nil {
+			return err
+		}
+		siblings, err := uc.todoRepository.GetTodoByActivityGroupID(ctx, activity.ID)
+		if err != nil {
+			return err
+		}
+
+		// The group is spread out again, so the imported todos get short
+		// positions however many there are.
+		ranks := rank.Spread(len(siblings) + len(todos))
+		if len(siblings) > 0 {
+			positions := make(map[int64]string, len(siblings))
+			for i, t := range siblings {
+				positions[t.ID] = ranks[i]
+			}
+			if err = uc.todoRepository.SetTodoPositions(ctx, positions); err != nil {
+				return err
+			}
+		}
+		got, err = uc.insertTodos(ctx, activity.ID, todos, ranks[len(siblings):])
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.TodoDTO, 0, len(got))
+	for _, t := range got {
+		res = append(res, t.ToDTO())
+	}
+	return res, nil
+}
+
+// decodeImport reads the data of an import and checks its size.
+func decodeImport(format string, data []byte) (*codec.List, error) {
+	if !codec.ValidImport(format) {
+		return nil, model.ErrInvalidImportFormat
+	}
+	list, err := codec.Decode(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, model.ErrInvalidImport.WithMessage("%s", err.Error())
+	}
+	if list.Len() > web.MaxImportTodos {
+		return nil, model.ErrImportTooLarge
+	}
+	return list, nil
+}
+
+// insertTodos inserts the todos of an import into a group at the given
+// positions, tagging them and recording their history.
+func (uc *TransferUCImpl) insertTodos(ctx context.Context, activityGroupID int64, todos []importTodo, positions []string) ([]*entity.Todo, error) {
+	actor := model.ActorFromContext(ctx)
+	got := make([]*entity.Todo, 0, len(todos))
+	tags := make(map[string]*entity.Tag)
+	transitions := make([]entity.TodoTransition, 0, len(todos))
+	events := make([]entity.TodoEvent, 0, len(todos))
+	for i, t := range todos {
+		t.todo.ActivityGroupID = activityGroupID
+		t.todo.Position = positions[i]
+		if t.parent >= 0 {
+			t.todo.ParentTodoID = &got[t.parent].ID
+		}
+		saved, err := uc.todoRepository.InsertTodo(ctx, t.todo)
+		if err != nil {
+			return nil, err
+		}
+		got = append(got, saved)
+		for _, name := range t.tags {
+			g, err := uc.resolveTag(ctx, tags, name)
+			if err != nil {
+				return nil, err
+			}
+			if err = uc.tagRepository.AddTodoTag(ctx, entity.TodoTag{TodoID: saved.ID, TagID: g.ID}); err != nil {
+				return nil, err
+			}
+		}
+		transitions = append(transitions, entity.TodoTransition{TodoID: saved.ID, ToStatus: saved.Status})
+		events = append(events, entity.NewTodoEvent(entity.EventActionCreate, nil, saved, actor))
+	}
+	if err := uc.todoRepository.InsertTodoTransitions(ctx, transitions); err != nil {
+		return nil, err
+	}
+	if err := uc.eventRepository.InsertTodoEvents(ctx, events); err != nil {
+		return nil, err
+	}
+	return got, nil
+}
+
+// resolveTag finds a tag by name, creating it on first use. seen caches the
+// tags of the running import by lower case name.
+func (uc *TransferUCImpl) resolveTag(ctx context.Context, seen map[string]*entity.Tag, name string) (*entity.Tag, error) {
+	key := strings.ToLower(name)
+	if g, ok := seen[key]; ok {
+		return g, nil
+	}
+	g, err := uc.tagRepository.GetTagByName(ctx, name)
+	if apperror.IsNotFound(err) {
+		g, err = uc.tagRepository.InsertTag(ctx, entity.Tag{Name: name})
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen[key] = g
+	return g, nil
+}
+
+// importWorkflow validates the workflow of an import; without one the
+// group follows the default workflow.
+func importWorkflow(dto *web.WorkflowDTO) (*entity.Workflow, error) {
+	if dto == nil {
+		return nil, nil
+	}
+	workflow := &entity.Workflow{
+		Initial:     dto.Initial,
+		Done:        dto.Done,
+		Closed:      dto.Closed,
+		Transitions: dto.Transitions,
+	}
+	if workflow.Closed == nil {
+		workflow.Closed = []string{}
+	}
+	for status, targets := range workflow.Transitions {
+		if targets == nil {
+			workflow.Transitions[status] = []string{}
+		}
+	}
+	if err := workflow.Validate(); err != nil {
+		return nil, model.ErrInvalidWorkflow.WithMessage("%s", err.Error())
+	}
+	return workflow, nil
+}
+
+// importTodos checks the items of a list and flattens them in position
+// order, each parent followed by its subtasks.
+func importTodos(list *codec.List, workflow *entity.Workflow) ([]importTodo, error) {
+	statuses := workflow
+	if statuses == nil {
+		statuses = entity.DefaultWorkflow()
+	}
+
+	todos := make([]importTodo, 0, list.Len())
+	for i, item := range list.Todos {
+		path := fmt.Sprintf("todos[%d]", i)
+		t, err := importItem(path, item, statuses)
+		if err != nil {
+			return nil, err
+		}
+		t.parent = -1
+		parent := len(todos)
+		todos = append(todos, t)
+
+		for j, sub := range item.Subtasks {
+			path := fmt.Sprintf("%s.subtasks[%d]", path, j)
+			if len(sub.Subtasks) > 0 {
+				return nil, itemError(path, model.ErrNestedSubtask)
+			}
+			t, err := importItem(path, sub, statuses)
+			if err != nil {
+				return nil, err
+			}
+			t.parent = parent
+			todos = append(todos, t)
+		}
+	}
+	return todos, nil
+}
+
+// importItem builds the todo for a single item. An explicit status decides
+// is_active; otherwise done picks the initial or the done status.
+func importItem(path string, item *codec.Item, workflow *entity.Workflow) (importTodo, error) {
+	item.Title = strings.TrimSpace(item.Title)
+	var rule string
+	if item.Recurrence != nil {
+		rule = strings.TrimSpace(*item.Recurrence)
+	}
+	err := validation.Struct(web.ActivityImportTodo{
+		Title:      item.Title,
+		Priority:   item.Priority,
+		Status:     item.Status,
+		Recurrence: rule,
+	})
+	if err != nil {
+		return importTodo{}, itemError(path, err)
+	}
+
+	status := item.Status
+	if status == "" {
+		status = workflow.StatusFor("", !item.Done)
+	} else if !workflow.Has(status) {
+		return importTodo{}, itemError(path, model.ErrInvalidStatus)
+	}
+	startAt, dueAt := normalizeTime(item.StartAt), normalizeTime(item.DueAt)
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		return importTodo{}, itemError(path, model.ErrStartAfterDue)
+	}
+	var canonical *string
+	if rule != "" {
+		r, err := recurrence.Parse(rule)
+		if err != nil {
+			return importTodo{}, itemError(path, model.ErrInvalidRecurrence.WithMessage("recurrence %s", err.Error()))
+		}
+		s := r.String()
+		canonical = &s
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for k, name := range item.Tags {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if err := validation.Struct(web.TagCreateRequest{Name: name}); err != nil {
+			return importTodo{}, itemError(fmt.Sprintf("%s.tags[%d]", path, k), err)
+		}
+		seen[strings.ToLower(name)] = true
+		tags = append(tags, name)
+	}
+
+	return importTodo{
+		todo: entity.Todo{
+			Title:        item.Title,
+			Priority:     item.Priority,
+			IsActive:     !workflow.IsClosed(status),
+			Status:       status,
+			StartAt:      startAt,
+			DueAt:        dueAt,
+			Recurrence:   canonical,
+			AutoComplete: item.AutoComplete,
+		},
+		tags: tags,
+	}, nil
+}
+
+// itemError names the item an error is about, both in the message and in
+// the fields it reports.
+func itemError(path string, err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return err
+	}
+	named := appErr.WithMessage("%s: %s", path, appErr.Message)
+	if len(named.Fields) == 0 {
+		named.Fields = []apperror.FieldError{{Field: path, Code: appErr.Code, Message: appErr.Message}}
+		return named
+	}
+	fields := make([]apperror.FieldError, 0, len(named.Fields))
+	for _, f := range named.Fields {
+		f.Field = path + "." + f.Field
+		fields = append(fields, f)
+	}
+	named.Fields = fields
+	return named
+}
+
+// normalizeTime stores timestamps in UTC at the precision of DATETIME
+// columns.
+func normalizeTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Truncate(time.Second)
+	return &utc
+}
+
+// authorize checks that the user of ctx has at least role in the activity
+// group. Without a user, as on the command line, everything is allowed.
+func (uc *TransferUCImpl) authorize(ctx context.Context, activityID int64, role string) error {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	member, err := uc.memberRepository.GetMember(ctx, activityID, userID)
+	if err != nil {
+		if apperror.IsNotFound(err) {
+			return model.ErrInsufficientRole
+		}
+		return err
+	}
+	if !member.Can(role) {
+		return model.ErrInsufficientRole
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type TrashUC interface {
+	GetTrash(ctx context.Context, req web.TrashRequest) (*web.TrashDTO, *web.Pagination, error)
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (*web.TrashPurgeDTO, error)
+}
@@ -0,0 +1,120 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/activity"
+	"github.com/vnnyx/golang-todo-api/internal/repository/query"
+	"github.com/vnnyx/golang-todo-api/internal/repository/todo"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+)
+
+type TrashUCImpl struct {
+	activityRepository activity.ActivityRepository
+	todoRepository     todo.TodoRepository
+	txManager          transaction.TxManager
+}
+
+func NewTrashUC(activityRepository activity.ActivityRepository, todoRepository todo.TodoRepository, txManager transaction.TxManager) TrashUC {
+	return &TrashUCImpl{
+		activityRepository: activityRepository,
+		todoRepository:     todoRepository,
+		txManager:          txManager,
+	}
+}
+
+// GetTrash lists trashed activity groups and todos together, most recently
+// deleted first, a page at a time.
+func (uc *TrashUCImpl) GetTrash(ctx context.Context, req web.TrashRequest) (*web.TrashDTO, *web.Pagination, error) {
+	if req.Limit == 0 {
+		req.Limit = query.DefaultLimit
+	}
+	pagination, err := model.NewPagination("", "", req.Limit, 0, req.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := query.NewMixedPage(pagination, query.TrashSort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		items []trashItem
+		total int64
+	)
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		activities, activityTotal, err := uc.activityRepository.GetAllTrashedActivity(ctx, pagination)
+		if err != nil {
+			return err
+		}
+		todos, todoTotal, err := uc.todoRepository.GetAllTrashedTodo(ctx, pagination)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range activities {
+			items = append(items, trashItem{activity: a})
+		}
+		for _, t := range todos {
+			items = append(items, trashItem{todo: t})
+		}
+		total = activityTotal + todoTotal
+		return nil
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+
+	// Each repository returned a page of its own; merged, they make one.
+	items, page := query.Result(p, query.Apply(p, items, trashItem.key), total, trashItem.key)
+	res := &web.TrashDTO{
+		ActivityGroups: make([]*web.ActivityDTO, 0),
+		TodoItems:      make([]*web.TodoDTO, 0),
+	}
+	for _, item := range items {
+		if item.activity != nil {
+			res.ActivityGroups = append(res.ActivityGroups, item.activity.ToDTO())
+		} else {
+			res.TodoItems = append(res.TodoItems, item.todo.ToDTO())
+		}
+	}
+	return res, &web.Pagination{Total: page.Total, Next: page.Next, Prev: page.Prev}, nil
+}
+
+// trashItem is a trashed activity group or a trashed todo.
+type trashItem struct {
+	activity *entity.Activity
+	todo     *entity.Todo
+}
+
+func (item trashItem) key() (interface{}, int64) {
+	if item.activity != nil {
+		return query.TrashActivityKey(item.activity.ID, *item.activity.DeletedAt)
+	}
+	return query.TrashTodoKey(item.todo.ID, *item.todo.DeletedAt)
+}
+
+// PurgeTrash permanently removes everything trashed before deletedBefore.
+// Todos go first so no purged activity group is still referenced.
+func (uc *TrashUCImpl) PurgeTrash(ctx context.Context, deletedBefore time.Time) (*web.TrashPurgeDTO, error) {
+	res := new(web.TrashPurgeDTO)
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		res.TodoItems, err = uc.todoRepository.PurgeTodo(ctx, deletedBefore)
+		if err != nil {
+			return err
+		}
+		res.ActivityGroups, err = uc.activityRepository.PurgeActivity(ctx, deletedBefore)
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return res, nil
+}
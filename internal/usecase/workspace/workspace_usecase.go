@@ -0,0 +1,15 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+type WorkspaceUC interface {
+	GetAllWorkspace(ctx context.Context) ([]*web.WorkspaceDTO, error)
+	CreateWorkspace(ctx context.Context, req web.WorkspaceCreateRequest) (*web.WorkspaceDTO, error)
+	UpdateWorkspace(ctx context.Context, req web.WorkspaceUpdateRequest) (*web.WorkspaceDTO, error)
+	AddMember(ctx context.Context, req web.WorkspaceMemberRequest) error
+	DeleteMember(ctx context.Context, req web.WorkspaceMemberRequest) error
+}
@@ -0,0 +1,221 @@
+package workspace
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/entity"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+	"github.com/vnnyx/golang-todo-api/internal/repository/transaction"
+	"github.com/vnnyx/golang-todo-api/internal/repository/user"
+	"github.com/vnnyx/golang-todo-api/internal/repository/workspace"
+	"github.com/vnnyx/golang-todo-api/internal/validation"
+)
+
+// WorkspaceUCImpl lists the workspaces of the current user. Creating
+// workspaces and managing their members and quotas is left to operators,
+// through the workspace command.
+type WorkspaceUCImpl struct {
+	cfg                 *infrastructure.Config
+	workspaceRepository workspace.WorkspaceRepository
+	userRepository      user.UserRepository
+	txManager           transaction.TxManager
+}
+
+func NewWorkspaceUC(cfg *infrastructure.Config, workspaceRepository workspace.WorkspaceRepository, userRepository user.UserRepository, txManager transaction.TxManager) WorkspaceUC {
+	return &WorkspaceUCImpl{
+		cfg:                 cfg,
+		workspaceRepository: workspaceRepository,
+		userRepository:      userRepository,
+		txManager:           txManager,
+	}
+}
+
+// GetAllWorkspace lists the workspaces of the current user with how much of
+// their quotas is used.
+func (uc *WorkspaceUCImpl) GetAllWorkspace(ctx context.Context) ([]*web.WorkspaceDTO, error) {
+	userID, ok := model.UserFromContext(ctx)
+	if !ok {
+		return nil, model.ErrUnauthenticated
+	}
+	got, err := uc.workspaceRepository.GetWorkspaceByUserID(ctx, userID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.toDTOs(ctx, got)
+}
+
+// CreateWorkspace sets up a workspace without members. Quotas left out take
+// the configured defaults.
+func (uc *WorkspaceUCImpl) CreateWorkspace(ctx context.Context, req web.WorkspaceCreateRequest) (*web.WorkspaceDTO, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Slug = strings.TrimSpace(req.Slug)
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(req.Slug, entity.PersonalWorkspaceSlugPrefix) {
+		return nil, model.ErrSlugTaken.WithMessage("slugs starting with %q are kept for personal workspaces", entity.PersonalWorkspaceSlugPrefix)
+	}
+	maxActivityGroups, maxTodos := uc.cfg.WorkspaceQuotas()
+	if req.MaxActivityGroups != nil {
+		maxActivityGroups = quota(*req.MaxActivityGroups)
+	}
+	if req.MaxTodos != nil {
+		maxTodos = quota(*req.MaxTodos)
+	}
+
+	var got *entity.Workspace
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		if _, err = uc.workspaceRepository.GetWorkspaceBySlug(ctx, req.Slug); err == nil {
+			return model.ErrSlugTaken
+		} else if !apperror.IsNotFound(err) {
+			return err
+		}
+		got, err = uc.workspaceRepository.InsertWorkspace(ctx, entity.Workspace{
+			Name:              req.Name,
+			Slug:              req.Slug,
+			MaxActivityGroups: maxActivityGroups,
+			MaxTodos:          maxTodos,
+		})
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.toDTO(ctx, got)
+}
+
+// UpdateWorkspace renames a workspace or changes its quotas. Lowering a
+// quota below the usage keeps the rows but stops new ones.
+func (uc *WorkspaceUCImpl) UpdateWorkspace(ctx context.Context, req web.WorkspaceUpdateRequest) (*web.WorkspaceDTO, error) {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var got *entity.Workspace
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		w, err := workspace.GetWorkspaceByRef(ctx, uc.workspaceRepository, req.Workspace)
+		if err != nil {
+			return err
+		}
+		if req.Name != nil {
+			w.Name = *req.Name
+		}
+		if req.MaxActivityGroups != nil {
+			w.MaxActivityGroups = quota(*req.MaxActivityGroups)
+		}
+		if req.MaxTodos != nil {
+			w.MaxTodos = quota(*req.MaxTodos)
+		}
+		got, err = uc.workspaceRepository.UpdateWorkspace(ctx, *w)
+		return err
+	})
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return uc.toDTO(ctx, got)
+}
+
+// AddMember lets the user with the given email work in a workspace.
+func (uc *WorkspaceUCImpl) AddMember(ctx context.Context, req web.WorkspaceMemberRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		w, u, err := uc.memberOf(ctx, req)
+		if err != nil {
+			return err
+		}
+		if member, err := uc.workspaceRepository.IsWorkspaceMember(ctx, w.ID, u.ID); err != nil {
+			return err
+		} else if member {
+			return model.ErrAlreadyWorkspaceMember
+		}
+		return uc.workspaceRepository.InsertWorkspaceMember(ctx, entity.WorkspaceMember{WorkspaceID: w.ID, UserID: u.ID})
+	})
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteMember takes a user out of a workspace. What they created there
+// stays, and so do their memberships of its activity groups, which they can
+// no longer reach.
+func (uc *WorkspaceUCImpl) DeleteMember(ctx context.Context, req web.WorkspaceMemberRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	err := uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		w, u, err := uc.memberOf(ctx, req)
+		if err != nil {
+			return err
+		}
+		return uc.workspaceRepository.DeleteWorkspaceMember(ctx, w.ID, u.ID)
+	})
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (uc *WorkspaceUCImpl) memberOf(ctx context.Context, req web.WorkspaceMemberRequest) (*entity.Workspace, *entity.User, error) {
+	w, err := workspace.GetWorkspaceByRef(ctx, uc.workspaceRepository, req.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := uc.userRepository.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, u, nil
+}
+
+func (uc *WorkspaceUCImpl) toDTO(ctx context.Context, w *entity.Workspace) (*web.WorkspaceDTO, error) {
+	res, err := uc.toDTOs(ctx, []*entity.Workspace{w})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+func (uc *WorkspaceUCImpl) toDTOs(ctx context.Context, workspaces []*entity.Workspace) ([]*web.WorkspaceDTO, error) {
+	ids := make([]int64, 0, len(workspaces))
+	for _, w := range workspaces {
+		ids = append(ids, w.ID)
+	}
+	usage, err := uc.workspaceRepository.GetWorkspaceUsage(ctx, ids)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	res := make([]*web.WorkspaceDTO, 0, len(workspaces))
+	for _, w := range workspaces {
+		res = append(res, w.ToDTO(usage[w.ID]))
+	}
+	return res, nil
+}
+
+// quota turns a requested quota into a stored one, where 0 is unlimited.
+func quota(n int64) *int64 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/model/web"
+)
+
+// DecodeJSON decodes a JSON object into the struct dst points to and then
+// validates it. Unknown keys and values of the wrong type are reported as
+// field errors together with the failing validation rules.
+func DecodeJSON(body []byte, dst interface{}) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return model.ErrInvalidBody.Wrap(err)
+	}
+
+	rv := reflect.ValueOf(dst).Elem()
+	rt := rv.Type()
+
+	var fields []apperror.FieldError
+	mistyped := make(map[string]bool)
+	known := make(map[string]bool)
+	for i := 0; i < rt.NumField(); i++ {
+		name := fieldName(rt.Field(i))
+		if name == "" {
+			continue
+		}
+		known[name] = true
+
+		raw, ok := object[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, rv.Field(i).Addr().Interface()); err != nil {
+			mistyped[name] = true
+			fields = append(fields, fieldError(name, "invalid_type", "%s must be %s", name, describe(rt.Field(i).Type)))
+		}
+	}
+
+	fields = append(fields, check(dst, mistyped)...)
+
+	var unknown []string
+	for name := range object {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		fields = append(fields, fieldError(name, "unknown_field", "%s is not a known field", name))
+	}
+
+	return failed(fields)
+}
+
+func describe(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(web.NullTime{}) {
+		return "an RFC 3339 timestamp"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	default:
+		return "a valid " + t.Kind().String()
+	}
+}
@@ -0,0 +1,177 @@
+// Package validation checks request structs against their `validate` tags.
+//
+// Tags hold comma separated rules applied in order; the first failing rule
+// is reported for the field:
+//
+//	required     the value must not be the zero value (or a nil pointer)
+//	omitempty    skip the remaining rules when the value is zero
+//	min=N, max=N length in characters for strings, value for numbers
+//	email        a bare email address
+//	oneof=a b c  one of the space separated values
+//	hexcolor     a color in #rrggbb notation
+//	slug         lowercase letters, digits and dashes, starting with a letter
+//
+// Fields are reported under their JSON name.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vnnyx/golang-todo-api/internal/apperror"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+)
+
+var (
+	hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	slug     = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// Struct validates the struct v points to and returns every failing field at
+// once as model.ErrValidationFailed.
+func Struct(v interface{}) error {
+	return failed(check(v, nil))
+}
+
+func check(v interface{}, skip map[string]bool) []apperror.FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	var fields []apperror.FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		name := fieldName(sf)
+		if tag == "" || name == "" || skip[name] {
+			continue
+		}
+		if fe, ok := checkField(name, rv.Field(i), strings.Split(tag, ",")); !ok {
+			fields = append(fields, fe)
+		}
+	}
+	return fields
+}
+
+func checkField(name string, v reflect.Value, rules []string) (apperror.FieldError, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			for _, rule := range rules {
+				if rule == "required" {
+					return fieldError(name, "required", "%s is required", name), false
+				}
+			}
+			return apperror.FieldError{}, true
+		}
+		v = v.Elem()
+	}
+
+	for _, rule := range rules {
+		rule, arg, _ := strings.Cut(rule, "=")
+		switch rule {
+		case "required":
+			if v.IsZero() {
+				return fieldError(name, rule, "%s is required", name), false
+			}
+		case "omitempty":
+			if v.IsZero() {
+				return apperror.FieldError{}, true
+			}
+		case "min", "max":
+			limit, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validation: bad %s rule on %s", rule, name))
+			}
+			if fe, ok := checkBound(name, v, rule, limit); !ok {
+				return fe, false
+			}
+		case "email":
+			if addr, err := mail.ParseAddress(v.String()); err != nil || addr.Address != v.String() {
+				return fieldError(name, rule, "%s must be a valid email address", name), false
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			if !contains(options, v.String()) {
+				return fieldError(name, rule, "%s must be one of %s", name, strings.Join(options, ", ")), false
+			}
+		case "hexcolor":
+			if !hexColor.MatchString(v.String()) {
+				return fieldError(name, rule, "%s must be a color in #rrggbb notation", name), false
+			}
+		case "slug":
+			if !slug.MatchString(v.String()) {
+				return fieldError(name, rule, "%s must hold lowercase letters, digits and dashes and start with a letter", name), false
+			}
+		default:
+			panic(fmt.Sprintf("validation: unknown rule %q on %s", rule, name))
+		}
+	}
+	return apperror.FieldError{}, true
+}
+
+func checkBound(name string, v reflect.Value, rule string, limit int64) (apperror.FieldError, bool) {
+	var n int64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = int64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	default:
+		panic(fmt.Sprintf("validation: %s rule on unsupported field %s", rule, name))
+	}
+
+	if rule == "min" && n < limit {
+		return fieldError(name, rule, "%s must be at least %d%s", name, limit, unit), false
+	}
+	if rule == "max" && n > limit {
+		return fieldError(name, rule, "%s must be at most %d%s", name, limit, unit), false
+	}
+	return apperror.FieldError{}, true
+}
+
+func fieldError(field, code, format string, args ...interface{}) apperror.FieldError {
+	return apperror.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func failed(fields []apperror.FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	err := *model.ErrValidationFailed
+	err.Fields = fields
+	return &err
+}
+
+// fieldName returns the JSON name of a field, or "" when it is not part of
+// the JSON representation.
+func fieldName(sf reflect.StructField) string {
+	if !sf.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return sf.Name
+	}
+	return name
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
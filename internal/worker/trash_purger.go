@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vnnyx/golang-todo-api/internal/infrastructure"
+	"github.com/vnnyx/golang-todo-api/internal/model"
+	"github.com/vnnyx/golang-todo-api/internal/usecase/trash"
+)
+
+// TrashPurger periodically removes trashed todos and activity groups that
+// have outlived the configured retention period.
+type TrashPurger struct {
+	trashUC   trash.TrashUC
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurger(cfg *infrastructure.Config, trashUC trash.TrashUC) *TrashPurger {
+	return &TrashPurger{
+		trashUC:   trashUC,
+		retention: time.Duration(cfg.TrashRetentionDay) * 24 * time.Hour,
+		interval:  time.Duration(cfg.TrashPurgeIntervalMinute) * time.Minute,
+	}
+}
+
+// Run purges once immediately and then on every interval until ctx is done.
+func (p *TrashPurger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		logrus.Warn("trash purge interval is not positive, background purge disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge empties the trash of every workspace.
+func (p *TrashPurger) purge(ctx context.Context) {
+	res, err := p.trashUC.PurgeTrash(model.WithAllWorkspaces(ctx), time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		return
+	}
+	if res.ActivityGroups > 0 || res.TodoItems > 0 {
+		logrus.Infof("purged %d activity groups and %d todo items from the trash", res.ActivityGroups, res.TodoItems)
+	}
+}
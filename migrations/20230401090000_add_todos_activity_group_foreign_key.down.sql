@@ -0,0 +1 @@
+ALTER TABLE todos DROP FOREIGN KEY fk_todos_activity_group_id;
\ No newline at end of file
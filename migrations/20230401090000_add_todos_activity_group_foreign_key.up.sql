@@ -0,0 +1,4 @@
+DELETE FROM todos WHERE activity_group_id NOT IN (SELECT activity_id FROM activities);
+
+ALTER TABLE todos
+    ADD CONSTRAINT fk_todos_activity_group_id FOREIGN KEY (activity_group_id) REFERENCES activities(activity_id);
@@ -0,0 +1,5 @@
+DROP INDEX idx_todos_deleted_at ON todos;
+DROP INDEX idx_activities_deleted_at ON activities;
+
+ALTER TABLE todos DROP COLUMN deleted_at;
+ALTER TABLE activities DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE activities ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE todos ADD COLUMN deleted_at DATETIME NULL;
+
+CREATE INDEX idx_activities_deleted_at ON activities(deleted_at);
+CREATE INDEX idx_todos_deleted_at ON todos(deleted_at);
@@ -0,0 +1,19 @@
+CREATE TABLE activity_events(
+    event_id BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    activity_id int NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    changes JSON NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_activity_events_activity_id (activity_id, event_id)
+)ENGINE = InnoDB;
+
+CREATE TABLE todo_events(
+    event_id BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    todo_id int NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    changes JSON NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_todo_events_todo_id (todo_id, event_id)
+)ENGINE = InnoDB;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS todo_status_transitions;
+
+ALTER TABLE todos DROP COLUMN status;
+ALTER TABLE activities DROP COLUMN workflow;
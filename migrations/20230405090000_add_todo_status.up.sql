@@ -0,0 +1,13 @@
+ALTER TABLE activities ADD COLUMN workflow JSON NULL;
+ALTER TABLE todos ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'todo';
+
+UPDATE todos SET status = 'done' WHERE is_active = false;
+
+CREATE TABLE todo_status_transitions(
+    transition_id BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    todo_id int NOT NULL,
+    from_status VARCHAR(32) NULL,
+    to_status VARCHAR(32) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_todo_status_transitions_todo_id (todo_id, transition_id)
+)ENGINE = InnoDB;
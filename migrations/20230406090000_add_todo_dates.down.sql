@@ -0,0 +1,4 @@
+DROP INDEX idx_todos_due_at ON todos;
+
+ALTER TABLE todos DROP COLUMN due_at;
+ALTER TABLE todos DROP COLUMN start_at;
@@ -0,0 +1,4 @@
+ALTER TABLE todos DROP FOREIGN KEY fk_todos_parent_todo_id;
+
+ALTER TABLE todos DROP COLUMN auto_complete;
+ALTER TABLE todos DROP COLUMN parent_todo_id;
@@ -0,0 +1,5 @@
+ALTER TABLE todos ADD COLUMN parent_todo_id int NULL;
+ALTER TABLE todos ADD COLUMN auto_complete boolean NOT NULL DEFAULT false;
+
+ALTER TABLE todos
+    ADD CONSTRAINT fk_todos_parent_todo_id FOREIGN KEY (parent_todo_id) REFERENCES todos(todo_id) ON DELETE CASCADE;
@@ -0,0 +1,17 @@
+CREATE TABLE tags(
+    tag_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(64) NOT NULL,
+    color VARCHAR(7) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_tags_name (name)
+)ENGINE = InnoDB;
+
+CREATE TABLE todo_tags(
+    todo_id int NOT NULL,
+    tag_id int NOT NULL,
+    PRIMARY KEY (todo_id, tag_id),
+    INDEX idx_todo_tags_tag_id (tag_id),
+    CONSTRAINT fk_todo_tags_todo_id FOREIGN KEY (todo_id) REFERENCES todos(todo_id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_tags_tag_id FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
@@ -0,0 +1,3 @@
+DROP INDEX idx_todos_position ON todos;
+
+ALTER TABLE todos DROP COLUMN position;
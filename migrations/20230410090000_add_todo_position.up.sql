@@ -0,0 +1,6 @@
+-- Ranks compare byte by byte, hence the binary collation. Existing todos
+-- keep their creation order.
+ALTER TABLE todos ADD COLUMN position VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '';
+UPDATE todos SET position = CONCAT(LPAD(todo_id, 10, '0'), 'i'), updated_at = updated_at;
+
+CREATE INDEX idx_todos_position ON todos(activity_group_id, position);
@@ -0,0 +1,11 @@
+-- Only the SHA-256 of a feed token is stored. A NULL activity group marks
+-- the feed of all groups.
+CREATE TABLE calendar_feeds(
+    feed_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    activity_group_id int NULL,
+    token_hash CHAR(64) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_calendar_feeds_token_hash (token_hash),
+    UNIQUE INDEX idx_calendar_feeds_activity_group_id (activity_group_id),
+    CONSTRAINT fk_calendar_feeds_activity_group_id FOREIGN KEY (activity_group_id) REFERENCES activities(activity_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
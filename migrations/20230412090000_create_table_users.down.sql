@@ -0,0 +1,9 @@
+ALTER TABLE calendar_feeds DROP FOREIGN KEY fk_calendar_feeds_user_id;
+ALTER TABLE calendar_feeds DROP COLUMN user_id;
+
+ALTER TABLE activities DROP FOREIGN KEY fk_activities_user_id;
+ALTER TABLE activities DROP INDEX idx_activities_user_id;
+ALTER TABLE activities DROP COLUMN user_id;
+
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
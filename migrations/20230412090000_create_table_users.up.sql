@@ -0,0 +1,35 @@
+CREATE TABLE users(
+    user_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    password_hash VARCHAR(255) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_users_email (email)
+)ENGINE = InnoDB;
+
+-- Only the SHA-256 of a key is stored; the prefix tells keys apart in
+-- listings.
+CREATE TABLE api_keys(
+    api_key_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    user_id int NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) CHARACTER SET ascii NOT NULL,
+    key_hash CHAR(64) CHARACTER SET ascii NOT NULL,
+    last_used_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_api_keys_key_hash (key_hash),
+    INDEX idx_api_keys_user_id (user_id),
+    CONSTRAINT fk_api_keys_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
+
+-- Groups created before accounts existed have no owner and are only
+-- reachable from the command line.
+ALTER TABLE activities ADD COLUMN user_id int NULL;
+ALTER TABLE activities
+    ADD INDEX idx_activities_user_id (user_id),
+    ADD CONSTRAINT fk_activities_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+ALTER TABLE calendar_feeds ADD COLUMN user_id int NULL;
+ALTER TABLE calendar_feeds
+    ADD CONSTRAINT fk_calendar_feeds_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+-- Only the feed of the owner survives for each group.
+DELETE FROM calendar_feeds
+WHERE activity_group_id IS NOT NULL
+  AND user_id NOT IN (SELECT user_id FROM activity_members WHERE activity_id = calendar_feeds.activity_group_id AND role = 'owner');
+ALTER TABLE calendar_feeds ADD UNIQUE INDEX idx_calendar_feeds_activity_group_id (activity_group_id);
+ALTER TABLE calendar_feeds DROP INDEX idx_calendar_feeds_activity_group_id_user_id;
+
+ALTER TABLE activities ADD COLUMN user_id int NULL;
+UPDATE activities SET user_id = (SELECT user_id FROM activity_members WHERE activity_id = activities.activity_id AND role = 'owner');
+ALTER TABLE activities
+    ADD INDEX idx_activities_user_id (user_id),
+    ADD CONSTRAINT fk_activities_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS activity_members;
@@ -0,0 +1,26 @@
+-- Members share an activity group. Every group with an owner has exactly
+-- one member with the owner role, which replaces activities.user_id.
+CREATE TABLE activity_members(
+    activity_id int NOT NULL,
+    user_id int NOT NULL,
+    role VARCHAR(16) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (activity_id, user_id),
+    INDEX idx_activity_members_user_id (user_id),
+    CONSTRAINT fk_activity_members_activity_id FOREIGN KEY (activity_id) REFERENCES activities(activity_id) ON DELETE CASCADE,
+    CONSTRAINT fk_activity_members_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
+
+INSERT INTO activity_members(activity_id, user_id, role, created_at, updated_at)
+SELECT activity_id, user_id, 'owner', created_at, created_at FROM activities WHERE user_id IS NOT NULL;
+
+ALTER TABLE activities
+    DROP FOREIGN KEY fk_activities_user_id,
+    DROP INDEX idx_activities_user_id,
+    DROP COLUMN user_id;
+
+-- Every member may have a feed of the group. The new index has to exist
+-- before the old one goes, as the foreign key needs one of them.
+ALTER TABLE calendar_feeds ADD UNIQUE INDEX idx_calendar_feeds_activity_group_id_user_id (activity_group_id, user_id);
+ALTER TABLE calendar_feeds DROP INDEX idx_calendar_feeds_activity_group_id;
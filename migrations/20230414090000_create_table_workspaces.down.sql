@@ -0,0 +1,33 @@
+ALTER TABLE api_keys
+    DROP FOREIGN KEY fk_api_keys_workspace_id,
+    DROP INDEX fk_api_keys_workspace_id,
+    DROP COLUMN workspace_id;
+
+ALTER TABLE calendar_feeds
+    DROP FOREIGN KEY fk_calendar_feeds_workspace_id,
+    DROP INDEX idx_calendar_feeds_workspace_id,
+    DROP COLUMN workspace_id;
+
+-- Names used in several workspaces keep their oldest tag only.
+UPDATE IGNORE todo_tags tt
+JOIN (SELECT t.tag_id, MIN(o.tag_id) AS keep_id FROM tags t JOIN tags o ON o.name = t.name GROUP BY t.tag_id) k ON k.tag_id = tt.tag_id
+SET tt.tag_id = k.keep_id;
+DELETE t FROM tags t JOIN tags o ON o.name = t.name AND o.tag_id < t.tag_id;
+ALTER TABLE tags ADD UNIQUE INDEX idx_tags_name (name);
+ALTER TABLE tags
+    DROP FOREIGN KEY fk_tags_workspace_id,
+    DROP INDEX idx_tags_workspace_id_name,
+    DROP COLUMN workspace_id;
+
+ALTER TABLE todos
+    DROP FOREIGN KEY fk_todos_workspace_id,
+    DROP INDEX idx_todos_workspace_id,
+    DROP COLUMN workspace_id;
+
+ALTER TABLE activities
+    DROP FOREIGN KEY fk_activities_workspace_id,
+    DROP INDEX idx_activities_workspace_id,
+    DROP COLUMN workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,60 @@
+-- Workspaces keep the data of the teams sharing the service apart. Every
+-- activity group, todo, tag and calendar feed belongs to one. A NULL quota
+-- is unlimited; trashed rows count until they are purged.
+CREATE TABLE workspaces(
+    workspace_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(64) CHARACTER SET ascii NOT NULL,
+    max_activity_groups int NULL,
+    max_todos int NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_workspaces_slug (slug)
+)ENGINE = InnoDB;
+
+CREATE TABLE workspace_members(
+    workspace_id int NOT NULL,
+    user_id int NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id),
+    INDEX idx_workspace_members_user_id (user_id),
+    CONSTRAINT fk_workspace_members_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+    CONSTRAINT fk_workspace_members_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
+
+-- Everything from before workspaces, and everyone, moves to the default
+-- workspace.
+INSERT INTO workspaces(workspace_id, name, slug) VALUES(1, 'Default', 'default');
+INSERT INTO workspace_members(workspace_id, user_id) SELECT 1, user_id FROM users;
+
+ALTER TABLE activities ADD COLUMN workspace_id int NOT NULL DEFAULT 1;
+ALTER TABLE activities
+    ALTER COLUMN workspace_id DROP DEFAULT,
+    ADD INDEX idx_activities_workspace_id (workspace_id),
+    ADD CONSTRAINT fk_activities_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id);
+
+ALTER TABLE todos ADD COLUMN workspace_id int NOT NULL DEFAULT 1;
+ALTER TABLE todos
+    ALTER COLUMN workspace_id DROP DEFAULT,
+    ADD INDEX idx_todos_workspace_id (workspace_id),
+    ADD CONSTRAINT fk_todos_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id);
+
+-- Tag names are unique within a workspace only.
+ALTER TABLE tags ADD COLUMN workspace_id int NOT NULL DEFAULT 1;
+ALTER TABLE tags
+    ALTER COLUMN workspace_id DROP DEFAULT,
+    ADD UNIQUE INDEX idx_tags_workspace_id_name (workspace_id, name),
+    ADD CONSTRAINT fk_tags_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id);
+ALTER TABLE tags DROP INDEX idx_tags_name;
+
+-- A user has a feed of all groups in every workspace.
+ALTER TABLE calendar_feeds ADD COLUMN workspace_id int NOT NULL DEFAULT 1;
+ALTER TABLE calendar_feeds
+    ALTER COLUMN workspace_id DROP DEFAULT,
+    ADD INDEX idx_calendar_feeds_workspace_id (workspace_id),
+    ADD CONSTRAINT fk_calendar_feeds_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id);
+
+-- A key bound to a workspace only works there.
+ALTER TABLE api_keys ADD COLUMN workspace_id int NULL;
+ALTER TABLE api_keys
+    ADD CONSTRAINT fk_api_keys_workspace_id FOREIGN KEY (workspace_id) REFERENCES workspaces(workspace_id) ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS todo_watchers;
+
+ALTER TABLE todos
+    DROP FOREIGN KEY fk_todos_assignee_id,
+    DROP INDEX idx_todos_assignee_id,
+    DROP COLUMN assignee_id;
@@ -0,0 +1,16 @@
+-- A todo has at most one assignee responsible for it, and any number of
+-- watchers following it. Both are members of the group of the todo.
+ALTER TABLE todos ADD COLUMN assignee_id int NULL;
+ALTER TABLE todos
+    ADD INDEX idx_todos_assignee_id (assignee_id),
+    ADD CONSTRAINT fk_todos_assignee_id FOREIGN KEY (assignee_id) REFERENCES users(user_id) ON DELETE SET NULL;
+
+CREATE TABLE todo_watchers(
+    todo_id int NOT NULL,
+    user_id int NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todo_id, user_id),
+    INDEX idx_todo_watchers_user_id (user_id),
+    CONSTRAINT fk_todo_watchers_todo_id FOREIGN KEY (todo_id) REFERENCES todos(todo_id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_watchers_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
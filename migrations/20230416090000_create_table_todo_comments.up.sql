@@ -0,0 +1,13 @@
+-- Comments discuss a todo. Bodies are Markdown; only their author may edit
+-- or delete them.
+CREATE TABLE todo_comments(
+    comment_id int NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    todo_id int NOT NULL,
+    user_id int NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_todo_comments_todo_id (todo_id, comment_id),
+    CONSTRAINT fk_todo_comments_todo_id FOREIGN KEY (todo_id) REFERENCES todos(todo_id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_comments_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+)ENGINE = InnoDB;
@@ -0,0 +1,2 @@
+ALTER TABLE activities DROP INDEX idx_activities_title_fulltext;
+ALTER TABLE todos DROP INDEX idx_todos_title_fulltext;
@@ -0,0 +1,3 @@
+-- Search matches todo and activity group titles through these indexes.
+ALTER TABLE todos ADD FULLTEXT INDEX idx_todos_title_fulltext (title);
+ALTER TABLE activities ADD FULLTEXT INDEX idx_activities_title_fulltext (title);
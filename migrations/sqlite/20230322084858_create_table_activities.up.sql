@@ -0,0 +1,12 @@
+CREATE TABLE activities(
+    activity_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER activities_updated_at AFTER UPDATE ON activities
+BEGIN
+    UPDATE activities SET updated_at = CURRENT_TIMESTAMP WHERE activity_id = NEW.activity_id;
+END;
@@ -0,0 +1,14 @@
+CREATE TABLE todos(
+    todo_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_group_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    priority VARCHAR(255) NOT NULL DEFAULT 'very-high',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER todos_updated_at AFTER UPDATE ON todos
+BEGIN
+    UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE todo_id = NEW.todo_id;
+END;
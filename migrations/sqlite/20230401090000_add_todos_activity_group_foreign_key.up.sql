@@ -0,0 +1,22 @@
+DELETE FROM todos WHERE activity_group_id NOT IN (SELECT activity_id FROM activities);
+
+CREATE TABLE todos_new(
+    todo_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_group_id INTEGER NOT NULL REFERENCES activities(activity_id),
+    title VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    priority VARCHAR(255) NOT NULL DEFAULT 'very-high',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO todos_new SELECT * FROM todos;
+DROP TABLE todos;
+ALTER TABLE todos_new RENAME TO todos;
+
+CREATE INDEX idx_todos_activity_group_id ON todos(activity_group_id);
+
+CREATE TRIGGER todos_updated_at AFTER UPDATE ON todos
+BEGIN
+    UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE todo_id = NEW.todo_id;
+END;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todo_events;
+DROP TABLE IF EXISTS activity_events;
@@ -0,0 +1,21 @@
+CREATE TABLE activity_events(
+    event_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_id INTEGER NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    changes TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_activity_events_activity_id ON activity_events(activity_id, event_id);
+
+CREATE TABLE todo_events(
+    event_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    changes TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_todo_events_todo_id ON todo_events(todo_id, event_id);
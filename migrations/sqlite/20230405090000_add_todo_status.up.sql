@@ -0,0 +1,14 @@
+ALTER TABLE activities ADD COLUMN workflow TEXT NULL;
+ALTER TABLE todos ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'todo';
+
+UPDATE todos SET status = 'done' WHERE is_active = false;
+
+CREATE TABLE todo_status_transitions(
+    transition_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL,
+    from_status VARCHAR(32) NULL,
+    to_status VARCHAR(32) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_todo_status_transitions_todo_id ON todo_status_transitions(todo_id, transition_id);
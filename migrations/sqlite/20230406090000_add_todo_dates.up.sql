@@ -0,0 +1,4 @@
+ALTER TABLE todos ADD COLUMN start_at DATETIME NULL;
+ALTER TABLE todos ADD COLUMN due_at DATETIME NULL;
+
+CREATE INDEX idx_todos_due_at ON todos(due_at);
@@ -0,0 +1,4 @@
+ALTER TABLE todos ADD COLUMN recurrence VARCHAR(255) NULL;
+ALTER TABLE todos ADD COLUMN series_id INT NULL;
+
+CREATE INDEX idx_todos_series_id ON todos(series_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_todos_parent_todo_id;
+
+ALTER TABLE todos DROP COLUMN auto_complete;
+ALTER TABLE todos DROP COLUMN parent_todo_id;
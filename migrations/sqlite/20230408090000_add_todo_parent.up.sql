@@ -0,0 +1,7 @@
+-- No foreign key here: SQLite cannot drop a column a foreign key uses
+-- without rebuilding the table. Subtasks are trashed and purged together
+-- with their parent.
+ALTER TABLE todos ADD COLUMN parent_todo_id INTEGER NULL;
+ALTER TABLE todos ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_todos_parent_todo_id ON todos(parent_todo_id);
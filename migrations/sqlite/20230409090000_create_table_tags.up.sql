@@ -0,0 +1,20 @@
+CREATE TABLE tags(
+    tag_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL COLLATE NOCASE UNIQUE,
+    color VARCHAR(7) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER tags_updated_at AFTER UPDATE ON tags
+BEGIN
+    UPDATE tags SET updated_at = CURRENT_TIMESTAMP WHERE tag_id = NEW.tag_id;
+END;
+
+CREATE TABLE todo_tags(
+    todo_id INTEGER NOT NULL REFERENCES todos(todo_id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(tag_id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX idx_todo_tags_tag_id ON todo_tags(tag_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_todos_position;
+
+DROP TRIGGER todos_updated_at;
+
+CREATE TRIGGER todos_updated_at AFTER UPDATE ON todos
+BEGIN
+    UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE todo_id = NEW.todo_id;
+END;
+
+ALTER TABLE todos DROP COLUMN position;
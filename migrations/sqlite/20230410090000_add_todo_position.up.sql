@@ -0,0 +1,17 @@
+-- Ranks compare byte by byte, which is the default BINARY collation.
+ALTER TABLE todos ADD COLUMN position VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Rebalancing rewrites positions without touching versions, and should not
+-- touch updated_at either.
+DROP TRIGGER todos_updated_at;
+
+-- Existing todos keep their creation order.
+UPDATE todos SET position = printf('%010di', todo_id);
+
+CREATE TRIGGER todos_updated_at AFTER UPDATE ON todos
+WHEN NEW.version <> OLD.version
+BEGIN
+    UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE todo_id = NEW.todo_id;
+END;
+
+CREATE INDEX idx_todos_position ON todos(activity_group_id, position);
@@ -0,0 +1,8 @@
+-- Only the SHA-256 of a feed token is stored. A NULL activity group marks
+-- the feed of all groups.
+CREATE TABLE calendar_feeds(
+    feed_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_group_id INTEGER NULL UNIQUE REFERENCES activities(activity_id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+ALTER TABLE calendar_feeds DROP COLUMN user_id;
+
+DROP INDEX IF EXISTS idx_activities_user_id;
+ALTER TABLE activities DROP COLUMN user_id;
+
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
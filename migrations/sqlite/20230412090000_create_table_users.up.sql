@@ -0,0 +1,35 @@
+CREATE TABLE users(
+    user_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(255) NOT NULL COLLATE NOCASE UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    password_hash VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER users_updated_at AFTER UPDATE ON users
+BEGIN
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE user_id = NEW.user_id;
+END;
+
+-- Only the SHA-256 of a key is stored; the prefix tells keys apart in
+-- listings.
+CREATE TABLE api_keys(
+    api_key_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    last_used_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- No foreign keys on the owners: SQLite cannot drop a column a foreign key
+-- uses without rebuilding the table. Groups created before accounts existed
+-- have no owner and are only reachable from the command line.
+ALTER TABLE activities ADD COLUMN user_id INTEGER NULL;
+CREATE INDEX idx_activities_user_id ON activities(user_id);
+
+ALTER TABLE calendar_feeds ADD COLUMN user_id INTEGER NULL;
@@ -0,0 +1,23 @@
+-- Only the feed of the owner survives for each group.
+DELETE FROM calendar_feeds
+WHERE activity_group_id IS NOT NULL
+  AND user_id NOT IN (SELECT user_id FROM activity_members WHERE activity_id = calendar_feeds.activity_group_id AND role = 'owner');
+DROP INDEX IF EXISTS idx_calendar_feeds_activity_group_id_user_id;
+
+CREATE TABLE calendar_feeds_old(
+    feed_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_group_id INTEGER NULL UNIQUE REFERENCES activities(activity_id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NULL
+);
+INSERT INTO calendar_feeds_old(feed_id, activity_group_id, token_hash, created_at, user_id)
+SELECT feed_id, activity_group_id, token_hash, created_at, user_id FROM calendar_feeds;
+DROP TABLE calendar_feeds;
+ALTER TABLE calendar_feeds_old RENAME TO calendar_feeds;
+
+ALTER TABLE activities ADD COLUMN user_id INTEGER NULL;
+UPDATE activities SET user_id = (SELECT user_id FROM activity_members WHERE activity_id = activities.activity_id AND role = 'owner');
+CREATE INDEX idx_activities_user_id ON activities(user_id);
+
+DROP TABLE IF EXISTS activity_members;
@@ -0,0 +1,39 @@
+-- Members share an activity group. Every group with an owner has exactly
+-- one member with the owner role, which replaces activities.user_id.
+CREATE TABLE activity_members(
+    activity_id INTEGER NOT NULL REFERENCES activities(activity_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (activity_id, user_id)
+);
+
+CREATE INDEX idx_activity_members_user_id ON activity_members(user_id);
+
+CREATE TRIGGER activity_members_updated_at AFTER UPDATE ON activity_members
+BEGIN
+    UPDATE activity_members SET updated_at = CURRENT_TIMESTAMP WHERE activity_id = NEW.activity_id AND user_id = NEW.user_id;
+END;
+
+INSERT INTO activity_members(activity_id, user_id, role, created_at, updated_at)
+SELECT activity_id, user_id, 'owner', created_at, created_at FROM activities WHERE user_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_activities_user_id;
+ALTER TABLE activities DROP COLUMN user_id;
+
+-- Every member may have a feed of the group. The unique group column has
+-- to go, which SQLite can only do by rebuilding the table.
+CREATE TABLE calendar_feeds_new(
+    feed_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    activity_group_id INTEGER NULL REFERENCES activities(activity_id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NULL
+);
+INSERT INTO calendar_feeds_new(feed_id, activity_group_id, token_hash, created_at, user_id)
+SELECT feed_id, activity_group_id, token_hash, created_at, user_id FROM calendar_feeds;
+DROP TABLE calendar_feeds;
+ALTER TABLE calendar_feeds_new RENAME TO calendar_feeds;
+
+CREATE UNIQUE INDEX idx_calendar_feeds_activity_group_id_user_id ON calendar_feeds(activity_group_id, user_id);
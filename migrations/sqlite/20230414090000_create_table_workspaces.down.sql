@@ -0,0 +1,42 @@
+-- Names used in several workspaces keep their oldest tag only.
+CREATE TEMPORARY TABLE todo_tags_backup AS
+SELECT tt.todo_id, MIN(o.tag_id) AS tag_id FROM todo_tags tt
+JOIN tags t ON t.tag_id = tt.tag_id
+JOIN tags o ON o.name = t.name
+GROUP BY tt.todo_id, t.tag_id;
+
+CREATE TABLE tags_old(
+    tag_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL COLLATE NOCASE UNIQUE,
+    color VARCHAR(7) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO tags_old(tag_id, name, color, created_at, updated_at)
+SELECT tag_id, name, color, created_at, updated_at FROM tags
+WHERE tag_id IN (SELECT MIN(tag_id) FROM tags GROUP BY name);
+DROP TABLE tags;
+ALTER TABLE tags_old RENAME TO tags;
+
+CREATE TRIGGER tags_updated_at AFTER UPDATE ON tags
+BEGIN
+    UPDATE tags SET updated_at = CURRENT_TIMESTAMP WHERE tag_id = NEW.tag_id;
+END;
+
+DELETE FROM todo_tags;
+INSERT OR IGNORE INTO todo_tags(todo_id, tag_id) SELECT todo_id, tag_id FROM todo_tags_backup;
+DROP TABLE todo_tags_backup;
+
+ALTER TABLE api_keys DROP COLUMN workspace_id;
+
+DROP INDEX IF EXISTS idx_calendar_feeds_workspace_id;
+ALTER TABLE calendar_feeds DROP COLUMN workspace_id;
+
+DROP INDEX IF EXISTS idx_todos_workspace_id;
+ALTER TABLE todos DROP COLUMN workspace_id;
+
+DROP INDEX IF EXISTS idx_activities_workspace_id;
+ALTER TABLE activities DROP COLUMN workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
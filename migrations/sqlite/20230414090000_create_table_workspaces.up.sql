@@ -0,0 +1,76 @@
+-- Workspaces keep the data of the teams sharing the service apart. Every
+-- activity group, todo, tag and calendar feed belongs to one. A NULL quota
+-- is unlimited; trashed rows count until they are purged.
+CREATE TABLE workspaces(
+    workspace_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    max_activity_groups INTEGER NULL,
+    max_todos INTEGER NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER workspaces_updated_at AFTER UPDATE ON workspaces
+BEGIN
+    UPDATE workspaces SET updated_at = CURRENT_TIMESTAMP WHERE workspace_id = NEW.workspace_id;
+END;
+
+CREATE TABLE workspace_members(
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- Everything from before workspaces, and everyone, moves to the default
+-- workspace.
+INSERT INTO workspaces(workspace_id, name, slug) VALUES(1, 'Default', 'default');
+INSERT INTO workspace_members(workspace_id, user_id) SELECT 1, user_id FROM users;
+
+-- SQLite cannot add a NOT NULL column without a default, nor drop a column
+-- a foreign key uses, so the columns keep the default workspace as their
+-- default and go without foreign keys. The repositories always set them.
+ALTER TABLE activities ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX idx_activities_workspace_id ON activities(workspace_id);
+
+ALTER TABLE todos ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX idx_todos_workspace_id ON todos(workspace_id);
+
+-- A user has a feed of all groups in every workspace.
+ALTER TABLE calendar_feeds ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX idx_calendar_feeds_workspace_id ON calendar_feeds(workspace_id);
+
+-- A key bound to a workspace only works there.
+ALTER TABLE api_keys ADD COLUMN workspace_id INTEGER NULL;
+
+-- Tag names are unique within a workspace only. The unique name column has
+-- to go, which SQLite can only do by rebuilding the table; the tags of todos
+-- are put aside in case foreign keys are enforced while it is dropped.
+CREATE TEMPORARY TABLE todo_tags_backup AS SELECT todo_id, tag_id FROM todo_tags;
+
+CREATE TABLE tags_new(
+    tag_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL COLLATE NOCASE,
+    color VARCHAR(7) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    workspace_id INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO tags_new(tag_id, name, color, created_at, updated_at)
+SELECT tag_id, name, color, created_at, updated_at FROM tags;
+DROP TABLE tags;
+ALTER TABLE tags_new RENAME TO tags;
+
+CREATE UNIQUE INDEX idx_tags_workspace_id_name ON tags(workspace_id, name);
+
+CREATE TRIGGER tags_updated_at AFTER UPDATE ON tags
+BEGIN
+    UPDATE tags SET updated_at = CURRENT_TIMESTAMP WHERE tag_id = NEW.tag_id;
+END;
+
+DELETE FROM todo_tags;
+INSERT INTO todo_tags(todo_id, tag_id) SELECT todo_id, tag_id FROM todo_tags_backup;
+DROP TABLE todo_tags_backup;
@@ -0,0 +1,15 @@
+-- A todo has at most one assignee responsible for it, and any number of
+-- watchers following it. Both are members of the group of the todo.
+-- SQLite cannot drop a column a foreign key uses, so assignee_id goes
+-- without one.
+ALTER TABLE todos ADD COLUMN assignee_id INTEGER NULL;
+CREATE INDEX idx_todos_assignee_id ON todos(assignee_id);
+
+CREATE TABLE todo_watchers(
+    todo_id INTEGER NOT NULL REFERENCES todos(todo_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todo_id, user_id)
+);
+
+CREATE INDEX idx_todo_watchers_user_id ON todo_watchers(user_id);
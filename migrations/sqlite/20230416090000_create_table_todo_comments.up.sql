@@ -0,0 +1,17 @@
+-- Comments discuss a todo. Bodies are Markdown; only their author may edit
+-- or delete them.
+CREATE TABLE todo_comments(
+    comment_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL REFERENCES todos(todo_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_todo_comments_todo_id ON todo_comments(todo_id, comment_id);
+
+CREATE TRIGGER todo_comments_updated_at AFTER UPDATE ON todo_comments
+BEGIN
+    UPDATE todo_comments SET updated_at = CURRENT_TIMESTAMP WHERE comment_id = NEW.comment_id;
+END;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS activities_fts_update;
+DROP TRIGGER IF EXISTS activities_fts_delete;
+DROP TRIGGER IF EXISTS activities_fts_insert;
+DROP TABLE IF EXISTS activities_fts;
+
+DROP TRIGGER IF EXISTS todos_fts_update;
+DROP TRIGGER IF EXISTS todos_fts_delete;
+DROP TRIGGER IF EXISTS todos_fts_insert;
+DROP TABLE IF EXISTS todos_fts;
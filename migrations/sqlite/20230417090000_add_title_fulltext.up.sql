@@ -0,0 +1,40 @@
+-- Search matches todo and activity group titles through these FTS5 tables.
+-- They index the titles of their table without a copy of them, and the
+-- triggers below keep them in sync with every write.
+CREATE VIRTUAL TABLE todos_fts USING fts5(title, content='todos', content_rowid='todo_id');
+INSERT INTO todos_fts(todos_fts) VALUES ('rebuild');
+
+CREATE TRIGGER todos_fts_insert AFTER INSERT ON todos
+BEGIN
+    INSERT INTO todos_fts(rowid, title) VALUES (NEW.todo_id, NEW.title);
+END;
+
+CREATE TRIGGER todos_fts_delete AFTER DELETE ON todos
+BEGIN
+    INSERT INTO todos_fts(todos_fts, rowid, title) VALUES ('delete', OLD.todo_id, OLD.title);
+END;
+
+CREATE TRIGGER todos_fts_update AFTER UPDATE OF title ON todos
+BEGIN
+    INSERT INTO todos_fts(todos_fts, rowid, title) VALUES ('delete', OLD.todo_id, OLD.title);
+    INSERT INTO todos_fts(rowid, title) VALUES (NEW.todo_id, NEW.title);
+END;
+
+CREATE VIRTUAL TABLE activities_fts USING fts5(title, content='activities', content_rowid='activity_id');
+INSERT INTO activities_fts(activities_fts) VALUES ('rebuild');
+
+CREATE TRIGGER activities_fts_insert AFTER INSERT ON activities
+BEGIN
+    INSERT INTO activities_fts(rowid, title) VALUES (NEW.activity_id, NEW.title);
+END;
+
+CREATE TRIGGER activities_fts_delete AFTER DELETE ON activities
+BEGIN
+    INSERT INTO activities_fts(activities_fts, rowid, title) VALUES ('delete', OLD.activity_id, OLD.title);
+END;
+
+CREATE TRIGGER activities_fts_update AFTER UPDATE OF title ON activities
+BEGIN
+    INSERT INTO activities_fts(activities_fts, rowid, title) VALUES ('delete', OLD.activity_id, OLD.title);
+    INSERT INTO activities_fts(rowid, title) VALUES (NEW.activity_id, NEW.title);
+END;